@@ -1,6 +1,7 @@
 package linkdrop
 
 import (
+	"context"
 	"encoding/hex"
 	"encoding/json"
 	"fmt"
@@ -8,11 +9,87 @@ import (
 	"github.com/LinkdropHQ/linkdrop-go-sdk/types"
 	"github.com/ethereum/go-ethereum/common"
 	"math/big"
+	"net/http"
 	"strconv"
+	"time"
 )
 
 type Client struct {
-	config *ClientConfig // Client scoped configuration - endpoints
+	config     *ClientConfig // Client scoped configuration - endpoints
+	httpClient *http.Client  // httpClient - a client with the middleware chain applied to its transport
+}
+
+func newClient(config *ClientConfig) *Client {
+	httpClient := *config.httpClient
+	transport := httpClient.Transport
+	if transport == nil {
+		transport = http.DefaultTransport
+	}
+	// The first middleware provided is the outermost one
+	for i := len(config.middlewares) - 1; i >= 0; i-- {
+		transport = config.middlewares[i](transport)
+	}
+	httpClient.Transport = transport
+	return &Client{
+		config:     config,
+		httpClient: &httpClient,
+	}
+}
+
+// request sends the API request retrying transient failures according to the RetryPolicy.
+// GET requests are always safe to retry, other requests are retried only with an idempotency key attached to ctx.
+func (c *Client) request(ctx context.Context, method string, url string, body []byte) ([]byte, error) {
+	idempotencyKey, hasIdempotencyKey := IdempotencyKeyFromContext(ctx)
+	maxAttempts := 1
+	if method == http.MethodGet || hasIdempotencyKey {
+		maxAttempts = max(c.config.retryPolicy.MaxAttempts, 1)
+	}
+
+	for attempt := 1; ; attempt++ {
+		headers := helpers.DefineHeaders(c.config.apiKey)
+		if hasIdempotencyKey {
+			headers.Set(IdempotencyKeyHeader, idempotencyKey)
+		}
+		resp, err := helpers.Request(ctx, c.httpClient, url, method, headers, body)
+		if err == nil || attempt >= maxAttempts || !isRetryable(err) {
+			return resp, apiError(err)
+		}
+
+		delay, ok := c.config.retryPolicy.backoff(attempt, err)
+		if !ok {
+			return resp, apiError(err)
+		}
+		timer := time.NewTimer(delay)
+		select {
+		case <-ctx.Done():
+			timer.Stop()
+			return nil, ctx.Err()
+		case <-timer.C:
+		}
+	}
+}
+
+// callApi sends the API request and decodes the response into T.
+// Responses with "success": false are returned as Error.
+func callApi[T any](c *Client, ctx context.Context, method string, url string, body []byte) (*T, error) {
+	resp, err := c.request(ctx, method, url, body)
+	if err != nil {
+		return nil, err
+	}
+	envelope := apiEnvelope{}
+	err = json.Unmarshal(resp, &envelope)
+	if err != nil {
+		return nil, fmt.Errorf("failed to decode API response: %w", err)
+	}
+	if envelope.Success != nil && !*envelope.Success {
+		return nil, newApiError(http.StatusOK, nil, resp)
+	}
+	result := new(T)
+	err = json.Unmarshal(resp, result)
+	if err != nil {
+		return nil, fmt.Errorf("failed to decode API response: %w", err)
+	}
+	return result, nil
 }
 
 // RedeemRecoveredLink allows a receiver to redeem a link that has been recovered.
@@ -20,6 +97,7 @@ type Client struct {
 // receiver and sender signatures are required for recovery.
 //
 // Parameters:
+// - ctx: The context used to cancel the request or bound its duration.
 // - receiver: The Ethereum address of the receiver.
 // - transferId: The unique identifier of the transfer.
 // - receiverSig: The signature of the receiver as a byte slice.
@@ -33,17 +111,20 @@ type Client struct {
 // - error: An error object if the redemption fails, otherwise nil.
 //
 // Notes:
-// - This function sends a POST request to the API endpoint `/redeem-recovered`.
+// - This function sends a POST request to the API endpoint `/redeem-recovered` of the token chain host, see ChainRegistry.ApiHost.
+// - RedeemLink with senderSig calls the same endpoint, so both redeem the recovered links through the same host.
 // - Ensure all required parameters are valid and signatures are properly formed.
 // - The API validates sender, receiver, and escrow information against the signatures.
+// - The request is retried on transient errors only if an idempotency key is attached to ctx, see WithIdempotencyKey.
 func (c *Client) RedeemRecoveredLink(
+	ctx context.Context,
 	transferId common.Address,
 	token types.Token,
 	sender common.Address,
 	receiver common.Address,
 	escrow common.Address,
 	receiverSig []byte,
-) ([]byte, error) {
+) (*RedeemResponse, error) {
 	body, _ := json.Marshal(map[string]string{
 		"receiver":     receiver.Hex(),
 		"sender":       sender.Hex(),
@@ -53,13 +134,18 @@ func (c *Client) RedeemRecoveredLink(
 
 		"token": token.Address.Hex(),
 	})
-	return helpers.Request(fmt.Sprintf("%s/redeem-recovered", c.config.apiURL), "POST", helpers.DefineHeaders(c.config.apiKey), body)
+	apiHost, err := c.config.chains.ApiHost(c.config.apiURL, token.ChainId)
+	if err != nil {
+		return nil, err
+	}
+	return callApi[RedeemResponse](c, ctx, "POST", fmt.Sprintf("%s/redeem-recovered", apiHost), body)
 }
 
 // RedeemLink allows a receiver to redeem a link by providing details such as transfer ID,
 // receiver signature, and optionally the sender, escrow, and token information.
 //
 // Parameters:
+// - ctx: The context used to cancel the request or bound its duration.
 // - receiver: The Ethereum address of the receiver.
 // - transferId: The unique identifier of the transfer.
 // - receiverSig: The signature of the receiver as a byte slice.
@@ -75,7 +161,9 @@ func (c *Client) RedeemRecoveredLink(
 // - This function sends a POST request to the API to redeem the link.
 // - Ensure all required parameters are valid before calling this function.
 // - If optional parameters (sender, escrow, or token) are not provided, they will be ignored in the request body.
+// - The request is retried on transient errors only if an idempotency key is attached to ctx, see WithIdempotencyKey.
 func (c *Client) RedeemLink(
+	ctx context.Context,
 	transferId common.Address,
 	token types.Token,
 	sender common.Address,
@@ -83,11 +171,11 @@ func (c *Client) RedeemLink(
 	escrow common.Address,
 	receiverSig []byte,
 	senderSig []byte,
-) ([]byte, error) {
-	apiHost, err := helpers.DefineApiHost(c.config.apiURL, int64(token.ChainId))
+) (*RedeemResponse, error) {
+	apiHost, err := c.config.chains.ApiHost(c.config.apiURL, token.ChainId)
 	apiEndpoint := "%s/redeem"
 	if err != nil {
-		return []byte{}, err
+		return nil, err
 	}
 	bodyRaw := map[string]string{
 		"transfer_id":  transferId.Hex(),
@@ -102,12 +190,13 @@ func (c *Client) RedeemLink(
 		apiEndpoint = "%s/redeem-recovered"
 	}
 	body, _ := json.Marshal(bodyRaw)
-	return helpers.Request(fmt.Sprintf(apiEndpoint, apiHost), "POST", helpers.DefineHeaders(c.config.apiKey), body)
+	return callApi[RedeemResponse](c, ctx, "POST", fmt.Sprintf(apiEndpoint, apiHost), body)
 }
 
 // GetTransferStatus retrieves the payment status of a transfer using its unique transfer ID.
 //
 // Parameters:
+// - ctx: The context used to cancel the request or bound its duration.
 // - chainId: The chain ID of the blockchain network to be used for the operation.
 // - transferId: The unique identifier of the transfer.
 //
@@ -119,19 +208,22 @@ func (c *Client) RedeemLink(
 // - The function sends a GET request to the API to fetch the transfer's payment status.
 // - Ensure that the transfer ID is valid and corresponds to an existing transfer.
 func (c *Client) GetTransferStatus(
+	ctx context.Context,
 	chainId types.ChainId,
 	transferId common.Address,
-) ([]byte, error) {
-	apiHost, err := helpers.DefineApiHost(c.config.apiURL, int64(chainId))
+) (*TransferStatusResponse, error) {
+	apiHost, err := c.config.chains.ApiHost(c.config.apiURL, chainId)
 	if err != nil {
-		return []byte{}, err
+		return nil, err
 	}
-	return helpers.Request(fmt.Sprintf("%s/payment-status/transfer/%s", apiHost, transferId.Hex()), "GET", helpers.DefineHeaders(c.config.apiKey), nil)
+	resp, err := callApi[TransferStatusResponse](c, ctx, "GET", fmt.Sprintf("%s/payment-status/transfer/%s", apiHost, transferId.Hex()), nil)
+	return resp, linkNotFound(err)
 }
 
 // GetTransferStatusByTxHash retrieves the payment status of a transfer using its transaction hash.
 //
 // Parameters:
+// - ctx: The context used to cancel the request or bound its duration.
 // - chainId: The chain ID of the blockchain network to be used for the operation.
 // - txHash: The transaction hash associated with the transfer.
 //
@@ -143,20 +235,23 @@ func (c *Client) GetTransferStatus(
 // - This function sends a GET request to the API to fetch the transfer's payment status by transaction hash.
 // - Ensure the transaction hash corresponds to a valid transfer and has been processed.
 func (c *Client) GetTransferStatusByTxHash(
+	ctx context.Context,
 	chainId types.ChainId,
 	txHash string,
-) ([]byte, error) {
-	apiHost, err := helpers.DefineApiHost(c.config.apiURL, int64(chainId))
+) (*TransferStatusResponse, error) {
+	apiHost, err := c.config.chains.ApiHost(c.config.apiURL, chainId)
 	if err != nil {
-		return []byte{}, err
+		return nil, err
 	}
-	return helpers.Request(fmt.Sprintf("%s/payment-status/transaction/%s", apiHost, txHash), "GET", helpers.DefineHeaders(c.config.apiKey), nil)
+	resp, err := callApi[TransferStatusResponse](c, ctx, "GET", fmt.Sprintf("%s/payment-status/transaction/%s", apiHost, txHash), nil)
+	return resp, linkNotFound(err)
 }
 
 // GetFee calculates the transaction fee required for a transfer based on token details, sender's address, transfer ID,
 // expiration time, and transfer amount.
 //
 // Parameters:
+// - ctx: The context used to cancel the request or bound its duration.
 // - token: The token object consisting of the token's address, type, and ID.
 // - sender: The Ethereum address of the sender.
 // - transferId: The unique identifier of the transfer.
@@ -174,16 +269,17 @@ func (c *Client) GetTransferStatusByTxHash(
 // - The function sends a GET request to the API, formatted as `apiURL/fee`, with the constructed query string.
 // - Ensure the provided parameters are valid and match the expected values in the API.
 func (c *Client) GetFee(
+	ctx context.Context,
 	token types.Token,
 	sender common.Address,
 	transferId common.Address,
 	expiration int64,
 	amount *big.Int,
-) ([]byte, error) {
+) (*FeeResponse, error) {
 	if amount == nil {
 		return nil, fmt.Errorf("amount is required")
 	}
-	apiHost, err := helpers.DefineApiHost(c.config.apiURL, int64(token.ChainId))
+	apiHost, err := c.config.chains.ApiHost(c.config.apiURL, token.ChainId)
 	if err != nil {
 		return nil, err
 	}
@@ -200,13 +296,14 @@ func (c *Client) GetFee(
 		"expiration":    strconv.Itoa(int(expiration)),
 		"token_id":      tokenId,
 	})
-	return helpers.Request(fmt.Sprintf("%s/fee?%s", apiHost, query), "GET", helpers.DefineHeaders(c.config.apiKey), nil)
+	return callApi[FeeResponse](c, ctx, "GET", fmt.Sprintf("%s/fee?%s", apiHost, query), nil)
 }
 
 // GetHistory fetches the history of transfers related to a token and sender's address.
 // It allows filtering for only active transfers and supports pagination with offset and limit.
 //
 // Parameters:
+// - ctx: The context used to cancel the request or bound its duration.
 // - token: The token object containing the token's address and chain ID.
 // - sender: The sender's Ethereum address.
 // - onlyActive: A boolean flag to filter only active transfers (true) or all transfers (false).
@@ -220,12 +317,13 @@ func (c *Client) GetFee(
 // Notes:
 // - The function dynamically determines the API host based on the token's chain ID.
 func (c *Client) GetHistory(
+	ctx context.Context,
 	token types.Token,
 	sender common.Address,
 	onlyActive bool,
 	offset, limit int64,
-) ([]byte, error) {
-	apiHost, err := helpers.DefineApiHost(c.config.apiURL, int64(token.ChainId))
+) (*HistoryResponse, error) {
+	apiHost, err := c.config.chains.ApiHost(c.config.apiURL, token.ChainId)
 	if err != nil {
 		return nil, err
 	}
@@ -235,10 +333,11 @@ func (c *Client) GetHistory(
 		"limit":         fmt.Sprintf("%d", limit),
 		"token_address": token.Address.Hex(),
 	})
-	return helpers.Request(
-		fmt.Sprintf("%s/payment-status/sender/%s/get-sender-history?%s", apiHost, sender.Hex(), query),
+	return callApi[HistoryResponse](
+		c,
+		ctx,
 		"GET",
-		helpers.DefineHeaders(c.config.apiKey),
+		fmt.Sprintf("%s/payment-status/sender/%s/get-sender-history?%s", apiHost, sender.Hex(), query),
 		nil,
 	)
 }
@@ -246,6 +345,7 @@ func (c *Client) GetHistory(
 // GetLimits fetches the limits of a specific token from the API.
 //
 // Parameters:
+// - ctx: The context used to cancel the request or bound its duration.
 // - token: The token object containing the token's address, type, and chain ID.
 //
 // Returns:
@@ -254,8 +354,8 @@ func (c *Client) GetHistory(
 //
 // Notes:
 // - The function determines the appropriate API host based on the token's chain ID.
-func (c *Client) GetLimits(token types.Token) ([]byte, error) {
-	apiHost, err := helpers.DefineApiHost(c.config.apiURL, int64(token.ChainId))
+func (c *Client) GetLimits(ctx context.Context, token types.Token) (*LimitsResponse, error) {
+	apiHost, err := c.config.chains.ApiHost(c.config.apiURL, token.ChainId)
 	if err != nil {
 		return nil, err
 	}
@@ -263,15 +363,17 @@ func (c *Client) GetLimits(token types.Token) ([]byte, error) {
 		"token_address": token.Address.Hex(),
 		"token_type":    string(token.Type),
 	})
-	return helpers.Request(
-		fmt.Sprintf("%s/limits?%s", apiHost, query),
+	return callApi[LimitsResponse](
+		c,
+		ctx,
 		"GET",
-		helpers.DefineHeaders(c.config.apiKey),
+		fmt.Sprintf("%s/limits?%s", apiHost, query),
 		nil,
 	)
 }
 
 func (c *Client) Deposit(
+	ctx context.Context,
 	token types.Token,
 	sender common.Address,
 	escrow common.Address,
@@ -282,8 +384,11 @@ func (c *Client) Deposit(
 	amount *big.Int,
 	totalAmount *big.Int,
 	encryptedSenderMessage []byte,
-) ([]byte, error) {
-	apiHost, err := helpers.DefineApiHost(c.config.apiURL, int64(token.ChainId))
+) (*DepositResponse, error) {
+	apiHost, err := c.config.chains.ApiHost(c.config.apiURL, token.ChainId)
+	if err != nil {
+		return nil, err
+	}
 	if amount == nil || totalAmount == nil {
 		return nil, fmt.Errorf("amount and totalAmount are required")
 	}
@@ -324,10 +429,11 @@ func (c *Client) Deposit(
 	if err != nil {
 		return nil, fmt.Errorf("failed to marshal request body: %w", err)
 	}
-	return helpers.Request(fmt.Sprintf(endpoint, apiHost), "POST", helpers.DefineHeaders(c.config.apiKey), body)
+	return callApi[DepositResponse](c, ctx, "POST", fmt.Sprintf(endpoint, apiHost), body)
 }
 
 func (c *Client) DepositWithAuthorization(
+	ctx context.Context,
 	token types.Token,
 	sender common.Address,
 	escrow common.Address,
@@ -339,8 +445,8 @@ func (c *Client) DepositWithAuthorization(
 	amount *big.Int,
 	totalAmount *big.Int,
 	encryptedSenderMessage []byte,
-) ([]byte, error) {
-	apiHost, err := helpers.DefineApiHost(c.config.apiURL, int64(token.ChainId))
+) (*DepositResponse, error) {
+	apiHost, err := c.config.chains.ApiHost(c.config.apiURL, token.ChainId)
 	if err != nil {
 		return nil, err
 	}
@@ -368,6 +474,5 @@ func (c *Client) DepositWithAuthorization(
 	if err != nil {
 		return nil, fmt.Errorf("failed to marshal request body: %w", err)
 	}
-	resp, err := helpers.Request(fmt.Sprintf("%s/deposit-with-authorization", apiHost), "POST", helpers.DefineHeaders(c.config.apiKey), body)
-	return resp, err
+	return callApi[DepositResponse](c, ctx, "POST", fmt.Sprintf("%s/deposit-with-authorization", apiHost), body)
 }
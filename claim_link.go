@@ -1,16 +1,21 @@
 package linkdrop
 
 import (
+	"context"
 	"crypto/ecdsa"
-	"encoding/json"
 	"errors"
+	"fmt"
 	"github.com/LinkdropHQ/linkdrop-go-sdk/constants"
 	"github.com/LinkdropHQ/linkdrop-go-sdk/crypto"
 	"github.com/LinkdropHQ/linkdrop-go-sdk/helpers"
 	"github.com/LinkdropHQ/linkdrop-go-sdk/types"
+	"github.com/LinkdropHQ/linkdrop-go-sdk/userop"
+	"github.com/ethereum/go-ethereum/accounts/abi/bind"
 	"github.com/ethereum/go-ethereum/common"
+	gethtypes "github.com/ethereum/go-ethereum/core/types"
+	"github.com/ethereum/go-ethereum/signer/core/apitypes"
 	"math/big"
-	"strings"
+	"time"
 )
 
 type ClaimLink struct {
@@ -32,6 +37,8 @@ type ClaimLink struct {
 	Expiration    int64
 	Operations    []types.ClaimLinkOperation
 	Status        types.ClaimLinkStatus
+
+	messageErr error // messageErr - the error of the sender message lookup in SDK.GetClaimLink
 }
 
 type ClaimLinkCreationParams struct {
@@ -43,21 +50,27 @@ type ClaimLinkCreationParams struct {
 }
 
 func (cl *ClaimLink) new(
+	ctx context.Context,
 	sdk *SDK,
 	params *ClaimLinkCreationParams,
 	linkKey *ecdsa.PrivateKey,
 	transferId common.Address,
 ) (err error) {
 	// Token
-	err = params.Token.Validate()
+	err = params.Token.ValidateWith(sdk.config.chains)
 	if err != nil {
 		return
 	}
+	// ERC721 token is always transferred as a single item
+	if params.Token.Type == types.TokenTypeERC721 {
+		params.Amount = big.NewInt(1)
+	}
 	// Fee
 	var fee *types.ClaimLinkFee
 	var totalAmount *big.Int
 	if params.Amount != nil {
 		fee, totalAmount, err = sdk.GetCurrentFee(
+			ctx,
 			params.Token,
 			params.Sender,
 			transferId,
@@ -74,7 +87,7 @@ func (cl *ClaimLink) new(
 	if params.EscrowAddress != nil {
 		escrowAddress = *params.EscrowAddress
 	} else {
-		escrowAddress, err = helpers.EscrowAddressByToken(params.Token)
+		escrowAddress, err = sdk.config.chains.EscrowAddressByToken(params.Token)
 		if err != nil {
 			return
 		}
@@ -101,15 +114,16 @@ func (cl *ClaimLink) new(
 }
 
 func (cl *ClaimLink) AddMessage(
+	ctx context.Context,
 	message string,
 	encryptionKeyLength uint16,
-	signTypedData types.SignTypedDataCallback,
+	signer types.TypedDataSigner,
 	nonce [crypto.NonceLength]byte,
 ) (err error) {
-	if signTypedData == nil {
-		return errors.New("signTypedData callback is required")
+	if signer == nil {
+		return errors.New("signer is required")
 	}
-	initialKey, err := helpers.MessageInitialKeyCreate(cl.TransferId, cl.Token.ChainId, signTypedData)
+	initialKey, err := helpers.MessageInitialKeyCreate(ctx, cl.TransferId, cl.Token.ChainId, signer)
 	if err != nil {
 		return
 	}
@@ -125,6 +139,9 @@ func (cl *ClaimLink) AddMessageWithInitialKey(
 	if cl.Status >= types.ClaimLinkStatusDeposited {
 		return errors.New("cannot add message after deposit")
 	}
+	if err = cl.requireMessages(); err != nil {
+		return
+	}
 	if len(message) == 0 {
 		return errors.New("message text is required")
 	}
@@ -152,6 +169,9 @@ func (cl *ClaimLink) AddMessageRaw(
 	if cl.Status >= types.ClaimLinkStatusDeposited {
 		return errors.New("cannot add message after deposit")
 	}
+	if err = cl.requireMessages(); err != nil {
+		return
+	}
 	decryptedMessage, err := helpers.MessageDecrypt(&message)
 	if err != nil || decryptedMessage != originalMessage {
 		return errors.New("message is not valid")
@@ -160,7 +180,7 @@ func (cl *ClaimLink) AddMessageRaw(
 	return
 }
 
-func (cl *ClaimLink) Redeem(receiver common.Address) (txHash common.Hash, err error) {
+func (cl *ClaimLink) Redeem(ctx context.Context, receiver common.Address) (txHash common.Hash, err error) {
 	if receiver == types.ZeroAddress {
 		err = errors.New("redeem: receiver is not valid")
 		return
@@ -175,7 +195,8 @@ func (cl *ClaimLink) Redeem(receiver common.Address) (txHash common.Hash, err er
 		return
 	}
 
-	bApiResp, err := cl.SDK.Client.RedeemLink(
+	redeemResp, err := cl.SDK.Client.RedeemLink(
+		ctx,
 		cl.TransferId,
 		cl.Token,
 		cl.Sender,
@@ -187,47 +208,80 @@ func (cl *ClaimLink) Redeem(receiver common.Address) (txHash common.Hash, err er
 	if err != nil {
 		return
 	}
-	ApiRespModel := struct {
-		Success bool   `json:"success"`
-		TxHash  string `json:"tx_hash"`
-		Error   string `json:"error"`
-	}{}
-	err = json.Unmarshal(bApiResp, &ApiRespModel)
+	cl.Status = types.ClaimLinkStatusRedeeming
+	return redeemResp.TxHash, cl.persistStatus(ctx, cl.Status)
+}
+
+// GetRedeemParams returns the escrow redeem transaction.
+// NOTE: the escrow accepts redeem only from its relayers (see CheckRelayer), use Redeem to redeem with the Linkdrop relayer.
+func (cl *ClaimLink) GetRedeemParams(receiver common.Address) (params *types.TransactionParams, err error) {
+	if receiver == types.ZeroAddress {
+		return nil, errors.New("redeem: receiver is not valid")
+	}
+	if cl.LinkKey == nil {
+		return nil, errors.New("redeem: can't redeem without linkKey")
+	}
+	if cl.Status == types.ClaimLinkStatusRedeeming || cl.Status == types.ClaimLinkStatusRedeemed {
+		return nil, ErrLinkAlreadyRedeemed
+	}
+	receiverSig, err := helpers.GenerateReceiverSig(cl.LinkKey, receiver)
+	if err != nil {
+		return
+	}
+	escrowVersion, err := cl.escrowVersion()
 	if err != nil {
 		return
 	}
-	if !ApiRespModel.Success {
-		err = errors.New(ApiRespModel.Error)
+	data, err := escrowVersion.Abi(cl.Token.Type).Pack("redeem", receiver, cl.Sender, cl.Token.Address, receiverSig)
+	if err != nil {
+		return
+	}
+	return &types.TransactionParams{
+		ChainId: cl.Token.ChainId,
+		Value:   big.NewInt(0),
+		Data:    data,
+		To:      cl.EscrowAddress,
+	}, nil
+}
+
+// RedeemOnChain sends the redeem transaction with sender, see GetRedeemParams. The sender must be the escrow relayer.
+// If sender implements types.ReceiptWaiter, the link is Redeemed after the transaction is mined,
+// the reverted redeem returns ErrTransactionFailed and the link gets its previous status back. Otherwise the link stays Redeeming.
+func (cl *ClaimLink) RedeemOnChain(
+	ctx context.Context,
+	receiver common.Address,
+	sender types.TransactionSender,
+) (txHash common.Hash, err error) {
+	params, err := cl.GetRedeemParams(receiver)
+	if err != nil {
 		return
 	}
-	return common.HexToHash(ApiRespModel.TxHash), nil
+	return cl.state().send(ctx, sender, params, "redeem", receiver, types.ClaimLinkStatusRedeeming, types.ClaimLinkStatusRedeemed)
 }
 
-func (cl *ClaimLink) GetStatus() (status types.ClaimLinkStatus, operations []types.ClaimLinkOperation, err error) {
-	linkB, err := cl.SDK.Client.GetTransferStatus(cl.Token.ChainId, cl.TransferId)
-	claimLink := struct {
-		Status     types.ClaimLinkStatus      `json:"status"`
-		Operations []types.ClaimLinkOperation `json:"operations"`
-	}{}
-	err = json.Unmarshal(linkB, &claimLink)
+func (cl *ClaimLink) GetStatus(ctx context.Context) (status types.ClaimLinkStatus, operations []types.ClaimLinkOperation, err error) {
+	statusResp, err := cl.SDK.Client.GetTransferStatus(ctx, cl.Token.ChainId, cl.TransferId)
 	if err != nil {
 		return
 	}
+	claimLink := statusResp.ClaimLink
 	if claimLink.Status != cl.Status {
 		cl.Status = claimLink.Status
 		cl.Operations = claimLink.Operations
+		err = cl.persist(ctx)
 	}
-	return claimLink.Status, claimLink.Operations, nil
+	return claimLink.Status, claimLink.Operations, err
 }
 
 func (cl *ClaimLink) DecryptSenderMessage() (message string, err error) {
-	if cl.Message == nil {
-		return "", errors.New("message is not set")
-	}
-	if cl.Message.LinkKey == "" {
-		return "", errors.New("message link key is not set")
-	}
-	return helpers.MessageDecrypt(cl.Message)
+	return decryptSenderMessage(cl.Message, cl.messageErr)
+}
+
+// LoadSenderMessage fetches the encrypted sender message of the link, see ClaimLinkRecovered.LoadSenderMessage
+func (cl *ClaimLink) LoadSenderMessage(ctx context.Context) (err error) {
+	cl.Message, err = cl.SDK.loadSenderMessage(ctx, cl.Token.ChainId, cl.TransferId, cl.Message)
+	cl.messageErr = err
+	return
 }
 
 func (cl *ClaimLink) GetDepositParams() (params *types.ClaimLinkDepositParams, err error) {
@@ -235,26 +289,37 @@ func (cl *ClaimLink) GetDepositParams() (params *types.ClaimLinkDepositParams, e
 		return nil, errors.New("claim link was initialized without amount. Fee is not set")
 	}
 
-	var messageData []byte
-	if cl.Message != nil {
-		messageData = cl.Message.Data
+	escrowVersion, err := cl.escrowVersion()
+	if err != nil {
+		return
+	}
+	// the escrows without messages don't take the message argument
+	var messageArgs []any
+	if escrowVersion.Features.Messages {
+		var messageData []byte
+		if cl.Message != nil {
+			messageData = cl.Message.Data
+		}
+		messageArgs = append(messageArgs, messageData)
+	} else if cl.Message != nil {
+		return nil, escrowVersion.require(false, "messages")
 	}
 
-	var data []byte
+	var method string
+	var args []any
 	switch cl.Token.Type {
 	case types.TokenTypeNative:
-		data, err = constants.EscrowTokenAbi.Pack(
-			"depositETH",
+		method = "depositETH"
+		args = []any{
 			cl.TransferId,
 			cl.TotalAmount,
 			big.NewInt(cl.Expiration),
 			cl.Fee.Amount,
 			cl.Fee.Authorization,
-			messageData,
-		)
+		}
 	case types.TokenTypeERC20:
-		data, err = constants.EscrowTokenAbi.Pack(
-			"deposit",
+		method = "deposit"
+		args = []any{
 			cl.Token.Address,
 			cl.TransferId,
 			cl.TotalAmount,
@@ -262,11 +327,32 @@ func (cl *ClaimLink) GetDepositParams() (params *types.ClaimLinkDepositParams, e
 			cl.Fee.Token.Address,
 			cl.Fee.Amount,
 			cl.Fee.Authorization,
-			messageData,
-		)
+		}
+	case types.TokenTypeERC721:
+		method = "depositERC721"
+		args = []any{
+			cl.Token.Address,
+			cl.TransferId,
+			cl.Token.Id,
+			big.NewInt(cl.Expiration),
+			cl.Fee.Amount,
+			cl.Fee.Authorization,
+		}
+	case types.TokenTypeERC1155:
+		method = "depositERC1155"
+		args = []any{
+			cl.Token.Address,
+			cl.TransferId,
+			cl.Token.Id,
+			cl.Amount,
+			big.NewInt(cl.Expiration),
+			cl.Fee.Amount,
+			cl.Fee.Authorization,
+		}
 	default:
 		return nil, errors.New("invalid token type")
 	}
+	data, err := escrowVersion.Abi(cl.Token.Type).Pack(method, append(args, messageArgs...)...)
 	if err != nil {
 		return nil, err
 	}
@@ -284,20 +370,104 @@ func (cl *ClaimLink) GetDepositParams() (params *types.ClaimLinkDepositParams, e
 	}, nil
 }
 
-func (cl *ClaimLink) Deposit(sendTransaction types.SendTransactionCallback) (txHash common.Hash, err error) {
+// Deposit sends the deposit transaction and registers it.
+// If sender implements types.ReceiptWaiter (e.g. signer.PrivateKey), the deposit is registered after it's mined,
+// the reverted deposit returns ErrTransactionFailed and the link stays Created.
+func (cl *ClaimLink) Deposit(ctx context.Context, sender types.TransactionSender) (txHash common.Hash, err error) {
+	if sender == nil {
+		return txHash, errors.New("sender is required")
+	}
+	params, err := cl.GetDepositParams()
+	if err != nil {
+		return
+	}
+
+	transaction, err := sender.SendTransaction(ctx, big.NewInt(int64(params.ChainId)), params.To, params.Value, params.Data)
+	if err != nil {
+		return
+	}
+	// the link is saved before the registration so the deposit can be registered later if it fails
+	cl.Status = types.ClaimLinkStatusDepositing
+	cl.addOperation("deposit", cl.Sender, transaction.Hash)
+	err = cl.persist(ctx)
+	if err != nil {
+		return transaction.Hash, err
+	}
+	waiter, ok := sender.(types.ReceiptWaiter)
+	if !ok {
+		return transaction.Hash, cl.DepositRegister(ctx, *transaction)
+	}
+	receipt, err := waiter.WaitForReceipt(ctx, big.NewInt(int64(params.ChainId)), transaction.Hash)
+	if err != nil {
+		return transaction.Hash, fmt.Errorf("failed to wait for deposit: %w", err)
+	}
+	if receipt.TxHash != transaction.Hash {
+		// the transaction was replaced with the one paying higher fees
+		cl.replaceOperationTxHash("deposit", transaction.Hash, receipt.TxHash)
+		transaction.Hash = receipt.TxHash
+	}
+	if receipt.Status != gethtypes.ReceiptStatusSuccessful {
+		return transaction.Hash, cl.depositFailed(ctx, transaction.Hash, "reverted")
+	}
+	return transaction.Hash, cl.DepositRegister(ctx, *transaction)
+}
+
+// DepositUserOp sends the deposit from the smart account of the builder as ERC-4337 user operation.
+// The calls, e.g. ERC20 approvals, are executed before the deposit in the same user operation.
+// The deposit is registered with the user operation hash after it's included,
+// the failed deposit returns ErrTransactionFailed and the link stays Created.
+func (cl *ClaimLink) DepositUserOp(
+	ctx context.Context,
+	builder *userop.Builder,
+	calls ...types.TransactionParams,
+) (userOpHash common.Hash, err error) {
+	if builder.Account().Address() != cl.Sender {
+		return userOpHash, fmt.Errorf("user operation account %s is not the sender %s", builder.Account().Address(), cl.Sender)
+	}
 	params, err := cl.GetDepositParams()
 	if err != nil {
 		return
 	}
 
-	transaction, err := sendTransaction(big.NewInt(int64(params.ChainId)), params.To, params.Value, params.Data)
+	userOpHash, err = builder.Send(ctx, append(calls[:len(calls):len(calls)], *params)...)
+	if err != nil {
+		return
+	}
+	cl.Status = types.ClaimLinkStatusDepositing
+	cl.addOperation("deposit", cl.Sender, userOpHash)
+	err = cl.persist(ctx)
 	if err != nil {
 		return
 	}
-	return transaction.Hash, cl.DepositRegister(*transaction)
+	receipt, err := builder.WaitForReceipt(ctx, userOpHash)
+	if err != nil {
+		return userOpHash, fmt.Errorf("failed to wait for deposit: %w", err)
+	}
+	if !receipt.Success {
+		reason := "failed"
+		if receipt.Reason != "" {
+			reason += ": " + receipt.Reason
+		}
+		return userOpHash, cl.depositFailed(ctx, userOpHash, reason)
+	}
+	return userOpHash, cl.DepositRegister(ctx, types.Transaction{
+		Hash: userOpHash,
+		Type: types.TransactionTypeUserOp,
+	})
+}
+
+// depositFailed returns the link to Created, so it can be deposited again
+func (cl *ClaimLink) depositFailed(ctx context.Context, txHash common.Hash, reason string) error {
+	cl.Status = types.ClaimLinkStatusCreated
+	cl.setOperationStatus("deposit", txHash, types.LinkOperationStatusError)
+	err := cl.persist(ctx)
+	if err != nil {
+		return err
+	}
+	return fmt.Errorf("%w: deposit %s %s", ErrTransactionFailed, txHash, reason)
 }
 
-func (cl *ClaimLink) DepositRegister(transaction types.Transaction) (err error) {
+func (cl *ClaimLink) DepositRegister(ctx context.Context, transaction types.Transaction) (err error) {
 	if cl.Fee == nil {
 		return errors.New("claim link was initialized without amount. Fee is not set")
 	}
@@ -306,6 +476,7 @@ func (cl *ClaimLink) DepositRegister(transaction types.Transaction) (err error)
 		messageData = cl.Message.Data
 	}
 	_, err = cl.SDK.Client.Deposit(
+		ctx,
 		cl.Token,
 		cl.Sender,
 		cl.EscrowAddress,
@@ -321,31 +492,223 @@ func (cl *ClaimLink) DepositRegister(transaction types.Transaction) (err error)
 		return
 	}
 	cl.Status = types.ClaimLinkStatusDeposited
-	return
+	return cl.persist(ctx)
+}
+
+// GetAuthorizationTypedData returns EIP-3009 typed data authorizing the escrow to pull TotalAmount from the sender
+// Available for the stable coins of the chain, see types.Chain.StableCoins
+func (cl *ClaimLink) GetAuthorizationTypedData() (typedData *apitypes.TypedData, err error) {
+	selector, err := cl.authorizationSelector()
+	if err != nil {
+		return
+	}
+	domain, err := helpers.DefineStableCoinDomain(cl.Token, cl.SDK.config.chains)
+	if err != nil {
+		return
+	}
+	td, err := helpers.AuthorizationTypedData(
+		domain,
+		selector,
+		cl.Sender,
+		cl.EscrowAddress,
+		cl.TotalAmount,
+		big.NewInt(0),
+		big.NewInt(cl.Expiration),
+		helpers.AuthorizationNonce(cl.TransferId),
+	)
+	if err != nil {
+		return
+	}
+	return &td, nil
 }
 
-func (cl *ClaimLink) GetCurrentFee() (fee *types.ClaimLinkFeeData, err error) {
-	return cl.getFee(cl.Amount)
+// DepositWithAuthorization signs EIP-3009 authorization with signer and registers the gasless deposit.
+// The deposit transaction is sent by the Linkdrop relayer, so the sender doesn't need to hold native currency.
+func (cl *ClaimLink) DepositWithAuthorization(
+	ctx context.Context,
+	signer types.TypedDataSigner,
+) (txHash common.Hash, err error) {
+	if signer == nil {
+		return txHash, errors.New("signer is required")
+	}
+	typedData, err := cl.GetAuthorizationTypedData()
+	if err != nil {
+		return
+	}
+	signature, err := signer.SignTypedData(ctx, *typedData)
+	if err != nil {
+		return
+	}
+	return cl.DepositWithAuthorizationSignature(ctx, signature)
 }
 
-func (cl *ClaimLink) UpdateAmount(amount *big.Int) (err error) {
+// DepositWithAuthorizationSignature registers the gasless deposit with the signature of GetAuthorizationTypedData
+func (cl *ClaimLink) DepositWithAuthorizationSignature(
+	ctx context.Context,
+	signature []byte,
+) (txHash common.Hash, err error) {
+	if cl.Fee == nil {
+		return txHash, errors.New("claim link was initialized without amount. Fee is not set")
+	}
+	if cl.Status >= types.ClaimLinkStatusDepositing {
+		return txHash, errors.New("can't deposit claim link with status " + cl.Status.String())
+	}
+	if cl.Fee.Token.Address != cl.Token.Address {
+		return txHash, errors.New("fee should be paid in the deposited token for deposits with authorization")
+	}
+	selector, err := cl.authorizationSelector()
+	if err != nil {
+		return
+	}
+	authorization, err := helpers.EncodeAuthorization(
+		selector,
+		cl.Sender,
+		cl.EscrowAddress,
+		cl.TotalAmount,
+		big.NewInt(0),
+		big.NewInt(cl.Expiration),
+		helpers.AuthorizationNonce(cl.TransferId),
+		signature,
+	)
+	if err != nil {
+		return
+	}
+
+	var messageData []byte
+	if cl.Message != nil {
+		messageData = cl.Message.Data
+	}
+	depositResp, err := cl.SDK.Client.DepositWithAuthorization(
+		ctx,
+		cl.Token,
+		cl.Sender,
+		cl.EscrowAddress,
+		cl.TransferId,
+		big.NewInt(cl.Expiration),
+		authorization,
+		string(selector),
+		*cl.Fee,
+		cl.Amount,
+		cl.TotalAmount,
+		messageData,
+	)
+	if err != nil {
+		return
+	}
+	cl.Status = types.ClaimLinkStatusDepositing
+	cl.addOperation("deposit", cl.Sender, depositResp.TxHash)
+	return depositResp.TxHash, cl.persist(ctx)
+}
+
+// GetRefundParams returns the escrow refund transaction returning the deposited assets to the sender.
+// Refund is available only for deposited links after the expiration.
+// NOTE: the escrow accepts refund only from its relayers (see CheckRelayer), the Linkdrop relayer refunds the expired links.
+// The sender returns the assets with Cancel instead.
+func (cl *ClaimLink) GetRefundParams() (params *types.TransactionParams, err error) {
+	err = cl.validateWithdrawal()
+	if err != nil {
+		return
+	}
+	if time.Now().Unix() < cl.Expiration {
+		return nil, ErrLinkNotExpired
+	}
+	escrowVersion, err := cl.escrowVersion()
+	if err != nil {
+		return
+	}
+	data, err := escrowVersion.Abi(cl.Token.Type).Pack("refund", cl.Sender, cl.Token.Address, cl.TransferId)
+	if err != nil {
+		return
+	}
+	return &types.TransactionParams{
+		ChainId: cl.Token.ChainId,
+		Value:   big.NewInt(0),
+		Data:    data,
+		To:      cl.EscrowAddress,
+	}, nil
+}
+
+// Refund sends the refund transaction with sender, see GetRefundParams. The sender must be the escrow relayer.
+// If sender implements types.ReceiptWaiter, the link is Refunded after the transaction is mined,
+// the reverted refund returns ErrTransactionFailed and the link stays Deposited. Otherwise the link stays Refunding.
+// NOTE: the API has no endpoint to register refunds, GetStatus reports the refund once the API sees the transaction
+func (cl *ClaimLink) Refund(ctx context.Context, sender types.TransactionSender) (txHash common.Hash, err error) {
+	params, err := cl.GetRefundParams()
+	if err != nil {
+		return
+	}
+	return cl.state().send(ctx, sender, params, "refund", cl.Sender, types.ClaimLinkStatusRefunding, types.ClaimLinkStatusRefunded)
+}
+
+// GetCancelParams returns the escrow cancel transaction returning the deposited assets to the sender.
+// Cancel is available for deposited links before the redemption and must be sent from the sender account.
+func (cl *ClaimLink) GetCancelParams() (params *types.TransactionParams, err error) {
+	err = cl.validateWithdrawal()
+	if err != nil {
+		return
+	}
+	escrowVersion, err := cl.escrowVersion()
+	if err != nil {
+		return
+	}
+	data, err := escrowVersion.Abi(cl.Token.Type).Pack("cancel", cl.Token.Address, cl.TransferId)
+	if err != nil {
+		return
+	}
+	return &types.TransactionParams{
+		ChainId: cl.Token.ChainId,
+		Value:   big.NewInt(0),
+		Data:    data,
+		To:      cl.EscrowAddress,
+	}, nil
+}
+
+// Cancel sends the cancel transaction with sender, see GetCancelParams.
+// If sender implements types.ReceiptWaiter, the link is Cancelled after the transaction is mined,
+// the reverted cancel returns ErrTransactionFailed and the link stays Deposited. Otherwise the link stays Cancelling.
+// NOTE: the API has no endpoint to register cancels, GetStatus reports the cancel once the API sees the transaction
+func (cl *ClaimLink) Cancel(ctx context.Context, sender types.TransactionSender) (txHash common.Hash, err error) {
+	params, err := cl.GetCancelParams()
+	if err != nil {
+		return
+	}
+	return cl.state().send(ctx, sender, params, "cancel", cl.Sender, types.ClaimLinkStatusCancelling, types.ClaimLinkStatusCancelled)
+}
+
+// CheckRelayer returns ErrNotRelayer if the account is not the relayer of the link escrow.
+// Only the relayers can send refund and redeem transactions, caller is the client of the link chain, e.g. *ethclient.Client.
+func (cl *ClaimLink) CheckRelayer(ctx context.Context, caller bind.ContractCaller, account common.Address) error {
+	return checkRelayer(ctx, cl.SDK, caller, cl.EscrowAddress, cl.Token.Type, account)
+}
+
+func (cl *ClaimLink) GetCurrentFee(ctx context.Context) (fee *types.ClaimLinkFeeData, err error) {
+	return cl.getFee(ctx, cl.Amount)
+}
+
+func (cl *ClaimLink) UpdateAmount(ctx context.Context, amount *big.Int) (err error) {
 	if cl.Token.Type == types.TokenTypeERC721 {
 		return errors.New("can't update amount for ERC721 token")
 	}
 	if cl.Status >= types.ClaimLinkStatusDeposited {
 		return errors.New("can't update amount for claim link with status " + cl.Status.String())
 	}
+	if amount == nil || amount.Sign() <= 0 {
+		return errors.New("amount should be positive")
+	}
 
-	feeData, err := cl.getFee(amount)
+	feeData, err := cl.getFee(ctx, amount)
 	if err != nil {
 		return
 	}
 
-	if amount.Cmp(feeData.MinTransferAmount) < 0 {
-		return errors.New("amount should be greater than " + feeData.MinTransferAmount.String() + "")
-	}
-	if amount.Cmp(feeData.MaxTransferAmount) > 0 {
-		return errors.New("amount should be less than " + feeData.MaxTransferAmount.String() + "")
+	// Transfer limits are not applied to ERC1155 quantities
+	if cl.Token.Type != types.TokenTypeERC1155 {
+		if feeData.MinTransferAmount != nil && amount.Cmp(feeData.MinTransferAmount) < 0 {
+			return errors.New("amount should be greater than " + feeData.MinTransferAmount.String() + "")
+		}
+		if feeData.MaxTransferAmount != nil && amount.Cmp(feeData.MaxTransferAmount) > 0 {
+			return errors.New("amount should be less than " + feeData.MaxTransferAmount.String() + "")
+		}
 	}
 
 	cl.Amount = amount
@@ -369,8 +732,9 @@ func (cl *ClaimLink) ClaimUrl() (link string, err error) {
 	return
 }
 
-func (cl *ClaimLink) getFee(amount *big.Int) (fee *types.ClaimLinkFeeData, err error) {
-	feeB, err := cl.SDK.Client.GetFee(
+func (cl *ClaimLink) getFee(ctx context.Context, amount *big.Int) (fee *types.ClaimLinkFeeData, err error) {
+	feeResp, err := cl.SDK.Client.GetFee(
+		ctx,
 		cl.Token,
 		cl.Sender,
 		cl.TransferId,
@@ -380,53 +744,77 @@ func (cl *ClaimLink) getFee(amount *big.Int) (fee *types.ClaimLinkFeeData, err e
 	if err != nil {
 		return nil, err
 	}
-
-	feeRaw := struct {
-		Amount            string `json:"amount"`
-		TotalAmount       string `json:"total_amount"`
-		MaxTransferAmount string `json:"max_transfer_amount"`
-		MinTransferAmount string `json:"min_transfer_amount"`
-		FeeToken          string `json:"fee_token"`
-		FeeAmount         string `json:"fee_amount"`
-		FeeAuthorization  string `json:"fee_authorization"`
-	}{}
-	err = json.Unmarshal(feeB, &feeRaw)
-	if err != nil {
-		return nil, err
+	if feeResp.TotalAmount == nil || feeResp.FeeAmount == nil {
+		return nil, errors.New("invalid fee response: total_amount and fee_amount are required")
 	}
-	amount, ok := new(big.Int).SetString(feeRaw.Amount, 10)
-	if !ok {
-		return nil, errors.New("invalid amount")
+	if feeResp.Amount != nil {
+		amount = feeResp.Amount
 	}
-	totalAmount, ok := new(big.Int).SetString(feeRaw.TotalAmount, 10)
-	if !ok {
-		return nil, errors.New("invalid totalAmount")
+	return &types.ClaimLinkFeeData{
+		Amount:            amount,
+		TotalAmount:       feeResp.TotalAmount,
+		MaxTransferAmount: feeResp.MaxTransferAmount,
+		MinTransferAmount: feeResp.MinTransferAmount,
+		Fee:               feeResp.ClaimLinkFee(cl.Token.ChainId),
+	}, nil
+}
+
+func (cl *ClaimLink) authorizationSelector() (constants.Selector, error) {
+	if cl.Token.Type != types.TokenTypeERC20 {
+		return constants.SelectorUndefined, errors.New("deposit with authorization is available for ERC20 tokens only")
 	}
-	maxTransferAmount, ok := new(big.Int).SetString(feeRaw.MaxTransferAmount, 10)
-	if !ok {
-		return nil, errors.New("invalid maxTransferAmount")
+	escrowVersion, err := cl.escrowVersion()
+	if err != nil {
+		return constants.SelectorUndefined, err
 	}
-	minTransferAmount, ok := new(big.Int).SetString(feeRaw.MinTransferAmount, 10)
-	if !ok {
-		return nil, errors.New("invalid minTransferAmount")
+	err = escrowVersion.require(escrowVersion.Features.AuthorizationDeposits, "authorization deposits")
+	if err != nil {
+		return constants.SelectorUndefined, err
 	}
-	feeAmount, ok := new(big.Int).SetString(feeRaw.FeeAmount, 10)
-	if !ok {
-		return nil, errors.New("invalid fee amount")
+	stableCoin, ok := cl.SDK.config.chains.StableCoin(cl.Token)
+	if !ok || stableCoin.Selector == constants.SelectorUndefined {
+		return constants.SelectorUndefined, errors.New("token doesn't support deposit with authorization")
 	}
-	fee = &types.ClaimLinkFeeData{
-		Amount:            amount,
-		TotalAmount:       totalAmount,
-		MaxTransferAmount: maxTransferAmount,
-		MinTransferAmount: minTransferAmount,
-		Fee: types.ClaimLinkFee{
-			Token: types.Token{
-				Type:    types.TokenType(feeRaw.FeeToken),
-				Address: common.HexToAddress(feeRaw.FeeToken),
-			},
-			Amount:        feeAmount,
-			Authorization: common.Hex2Bytes(strings.TrimPrefix(feeRaw.FeeAuthorization, "0x")),
-		},
+	return stableCoin.Selector, nil
+}
+
+// escrowVersion returns the version of the claim link escrow
+func (cl *ClaimLink) escrowVersion() (*EscrowVersion, error) {
+	return cl.SDK.EscrowVersion(cl.EscrowAddress)
+}
+
+// requireMessages checks the claim link escrow accepts sender messages
+func (cl *ClaimLink) requireMessages() error {
+	escrowVersion, err := cl.escrowVersion()
+	if err != nil {
+		return err
 	}
-	return fee, nil
+	return escrowVersion.require(escrowVersion.Features.Messages, "messages")
+}
+
+// validateWithdrawal checks if deposited assets can be returned to the sender
+func (cl *ClaimLink) validateWithdrawal() error {
+	switch cl.Status {
+	case types.ClaimLinkStatusDeposited:
+		return nil
+	case types.ClaimLinkStatusRedeeming, types.ClaimLinkStatusRedeemed:
+		return ErrLinkAlreadyRedeemed
+	}
+	return errors.New("assets can't be returned for claim link with status " + cl.Status.String() + ", use GetStatus to refresh it")
+}
+
+func (cl *ClaimLink) replaceOperationTxHash(operationType string, txHash common.Hash, replacement common.Hash) {
+	cl.state().replaceOperationTxHash(operationType, txHash, replacement)
+}
+
+func (cl *ClaimLink) setOperationStatus(operationType string, txHash common.Hash, status types.ClaimLinkOperationStatus) {
+	cl.state().setOperationStatus(operationType, txHash, status)
+}
+
+func (cl *ClaimLink) addOperation(operationType string, receiver common.Address, txHash common.Hash) {
+	cl.state().addOperation(operationType, receiver, txHash)
+}
+
+func (cl *ClaimLink) state() *linkState {
+	return &linkState{status: &cl.Status, operations: &cl.Operations, persist: cl.persist}
 }
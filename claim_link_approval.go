@@ -0,0 +1,205 @@
+package linkdrop
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"github.com/LinkdropHQ/linkdrop-go-sdk/constants"
+	"github.com/LinkdropHQ/linkdrop-go-sdk/helpers"
+	"github.com/LinkdropHQ/linkdrop-go-sdk/types"
+	"github.com/LinkdropHQ/linkdrop-go-sdk/userop"
+	"github.com/ethereum/go-ethereum/accounts/abi/bind"
+	"github.com/ethereum/go-ethereum/common"
+	gethtypes "github.com/ethereum/go-ethereum/core/types"
+	"math/big"
+)
+
+// IsNFTApproved checks whether the escrow is allowed to transfer the sender's ERC721 or ERC1155 token
+func (cl *ClaimLink) IsNFTApproved(ctx context.Context, caller bind.ContractCaller) (approved bool, err error) {
+	switch cl.Token.Type {
+	case types.TokenTypeERC721:
+		outputs, err := helpers.CallContract(ctx, caller, constants.ERC721Abi, cl.Token.Address, "getApproved", cl.Token.Id)
+		if err != nil {
+			return false, err
+		}
+		if operator, ok := outputs[0].(common.Address); ok && operator == cl.EscrowAddress {
+			return true, nil
+		}
+		return cl.isApprovedForAll(ctx, caller)
+	case types.TokenTypeERC1155:
+		return cl.isApprovedForAll(ctx, caller)
+	}
+	return false, errors.New("approval check is available for ERC721 and ERC1155 tokens only")
+}
+
+// GetNFTApprovalParams returns the transaction allowing the escrow to transfer the sender's token.
+// ERC721 token is approved individually, ERC1155 tokens are approved with setApprovalForAll.
+func (cl *ClaimLink) GetNFTApprovalParams() (params *types.TransactionParams, err error) {
+	var data []byte
+	switch cl.Token.Type {
+	case types.TokenTypeERC721:
+		data, err = constants.ERC721Abi.Pack("approve", cl.EscrowAddress, cl.Token.Id)
+	case types.TokenTypeERC1155:
+		data, err = constants.ERC1155Abi.Pack("setApprovalForAll", cl.EscrowAddress, true)
+	default:
+		return nil, errors.New("approval is available for ERC721 and ERC1155 tokens only")
+	}
+	if err != nil {
+		return nil, err
+	}
+	return &types.TransactionParams{
+		ChainId: cl.Token.ChainId,
+		Value:   big.NewInt(0),
+		Data:    data,
+		To:      cl.Token.Address,
+	}, nil
+}
+
+// GetApprovalParams returns the transactions allowing the escrow to transfer the sender's tokens for the deposit,
+// empty if the escrow is already allowed. ERC20 allowance covers TotalAmount and the fee if it's paid in another ERC20 token.
+// A non-zero ERC20 allowance is reset with approve of 0 first, as tokens like USDT require.
+// The transactions must be mined in order.
+func (cl *ClaimLink) GetApprovalParams(ctx context.Context, caller bind.ContractCaller) (params []types.TransactionParams, err error) {
+	if cl.Fee == nil {
+		return nil, errors.New("claim link was initialized without amount. Fee is not set")
+	}
+	switch cl.Token.Type {
+	case types.TokenTypeNative:
+	case types.TokenTypeERC20:
+		approvals, err := cl.erc20ApprovalParams(ctx, caller, cl.Token.Address, cl.TotalAmount)
+		if err != nil {
+			return nil, err
+		}
+		params = append(params, approvals...)
+	case types.TokenTypeERC721, types.TokenTypeERC1155:
+		approved, err := cl.IsNFTApproved(ctx, caller)
+		if err != nil {
+			return nil, err
+		}
+		if !approved {
+			approval, err := cl.GetNFTApprovalParams()
+			if err != nil {
+				return nil, err
+			}
+			params = append(params, *approval)
+		}
+	default:
+		return nil, errors.New("invalid token type")
+	}
+
+	if cl.Fee.Token.Type == types.TokenTypeERC20 && cl.Fee.Token.Address != cl.Token.Address {
+		approvals, err := cl.erc20ApprovalParams(ctx, caller, cl.Fee.Token.Address, cl.Fee.Amount)
+		if err != nil {
+			return nil, err
+		}
+		params = append(params, approvals...)
+	}
+	return
+}
+
+// DepositWithApproval sends the missing approvals of GetApprovalParams and then the deposit, see Deposit.
+// The deposit is sent after the approvals are mined, so sender must implement types.ReceiptWaiter
+// if an approval is missing, ErrApprovalRequired is returned otherwise.
+func (cl *ClaimLink) DepositWithApproval(
+	ctx context.Context,
+	sender types.TransactionSender,
+	caller bind.ContractCaller,
+) (txHash common.Hash, err error) {
+	if sender == nil {
+		return txHash, errors.New("sender is required")
+	}
+	approvals, err := cl.GetApprovalParams(ctx, caller)
+	if err != nil {
+		return
+	}
+	waiter, ok := sender.(types.ReceiptWaiter)
+	if len(approvals) > 0 && !ok {
+		return txHash, ErrApprovalRequired
+	}
+	for _, approval := range approvals {
+		chainId := big.NewInt(int64(approval.ChainId))
+		transaction, err := sender.SendTransaction(ctx, chainId, approval.To, approval.Value, approval.Data)
+		if err != nil {
+			return txHash, fmt.Errorf("failed to approve %s: %w", approval.To, err)
+		}
+		receipt, err := waiter.WaitForReceipt(ctx, chainId, transaction.Hash)
+		if err != nil {
+			return txHash, fmt.Errorf("failed to wait for approval: %w", err)
+		}
+		if receipt.Status != gethtypes.ReceiptStatusSuccessful {
+			return txHash, fmt.Errorf("%w: approval %s reverted", ErrTransactionFailed, receipt.TxHash)
+		}
+	}
+	return cl.Deposit(ctx, sender)
+}
+
+// DepositUserOpWithApproval sends the missing approvals of GetApprovalParams and the deposit in one user operation,
+// see DepositUserOp
+func (cl *ClaimLink) DepositUserOpWithApproval(
+	ctx context.Context,
+	builder *userop.Builder,
+	caller bind.ContractCaller,
+) (userOpHash common.Hash, err error) {
+	approvals, err := cl.GetApprovalParams(ctx, caller)
+	if err != nil {
+		return
+	}
+	return cl.DepositUserOp(ctx, builder, approvals...)
+}
+
+// erc20ApprovalParams returns approve of amount if the sender's allowance for the escrow is lower, nil otherwise.
+// A non-zero allowance is reset to 0 first.
+func (cl *ClaimLink) erc20ApprovalParams(
+	ctx context.Context,
+	caller bind.ContractCaller,
+	token common.Address,
+	amount *big.Int,
+) (params []types.TransactionParams, err error) {
+	if amount == nil || amount.Sign() == 0 {
+		return nil, nil
+	}
+	outputs, err := helpers.CallContract(ctx, caller, constants.ERC20Abi, token, "allowance", cl.Sender, cl.EscrowAddress)
+	if err != nil {
+		return nil, err
+	}
+	allowance, ok := outputs[0].(*big.Int)
+	if !ok {
+		return nil, errors.New("unexpected allowance output")
+	}
+	if allowance.Cmp(amount) >= 0 {
+		return nil, nil
+	}
+	amounts := []*big.Int{amount}
+	if allowance.Sign() > 0 {
+		amounts = []*big.Int{big.NewInt(0), amount}
+	}
+	for _, approved := range amounts {
+		data, err := constants.ERC20Abi.Pack("approve", cl.EscrowAddress, approved)
+		if err != nil {
+			return nil, err
+		}
+		params = append(params, types.TransactionParams{
+			ChainId: cl.Token.ChainId,
+			Value:   big.NewInt(0),
+			Data:    data,
+			To:      token,
+		})
+	}
+	return
+}
+
+func (cl *ClaimLink) isApprovedForAll(ctx context.Context, caller bind.ContractCaller) (bool, error) {
+	contractAbi := constants.ERC1155Abi
+	if cl.Token.Type == types.TokenTypeERC721 {
+		contractAbi = constants.ERC721Abi
+	}
+	outputs, err := helpers.CallContract(ctx, caller, contractAbi, cl.Token.Address, "isApprovedForAll", cl.Sender, cl.EscrowAddress)
+	if err != nil {
+		return false, err
+	}
+	approved, ok := outputs[0].(bool)
+	if !ok {
+		return false, errors.New("unexpected isApprovedForAll output")
+	}
+	return approved, nil
+}
@@ -0,0 +1,278 @@
+package linkdrop
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"github.com/LinkdropHQ/linkdrop-go-sdk/constants"
+	"github.com/LinkdropHQ/linkdrop-go-sdk/types"
+	"github.com/ethereum/go-ethereum"
+	"github.com/ethereum/go-ethereum/common"
+	gethtypes "github.com/ethereum/go-ethereum/core/types"
+	"math/big"
+	"net/http"
+	"strings"
+	"testing"
+)
+
+// newApprovalClaimLink returns the created ERC20 link paying the fee in the same token
+func newApprovalClaimLink(t *testing.T, sdk *SDK) *ClaimLink {
+	t.Helper()
+	claimLink := newDepositedClaimLink(t, sdk)
+	claimLink.Status = types.ClaimLinkStatusCreated
+	claimLink.Fee = &types.ClaimLinkFee{Token: claimLink.Token, Amount: big.NewInt(1000)}
+	claimLink.TotalAmount = big.NewInt(1001000)
+	return claimLink
+}
+
+func TestClaimLinkGetApprovalParams(t *testing.T) {
+	tests := []struct {
+		name        string
+		allowance   int64
+		wantAmounts []int64
+	}{
+		{name: "no allowance", allowance: 0, wantAmounts: []int64{1001000}},
+		{name: "insufficient allowance is reset", allowance: 500, wantAmounts: []int64{0, 1001000}},
+		{name: "sufficient allowance", allowance: 1001000},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			sdk := newTestSDK(t, func(w http.ResponseWriter, r *http.Request) {
+				t.Fatalf("unexpected request %s", r.URL)
+			})
+			claimLink := newApprovalClaimLink(t, sdk)
+			caller := &stubCaller{output: common.LeftPadBytes(big.NewInt(tt.allowance).Bytes(), 32)}
+
+			params, err := claimLink.GetApprovalParams(context.Background(), caller)
+			if err != nil {
+				t.Fatal(err)
+			}
+			if len(params) != len(tt.wantAmounts) {
+				t.Fatalf("got %d approvals, want %d", len(params), len(tt.wantAmounts))
+			}
+			for i, approval := range params {
+				args, err := constants.ERC20Abi.Methods["approve"].Inputs.Unpack(approval.Data[4:])
+				if err != nil {
+					t.Fatal(err)
+				}
+				if approval.To != claimLink.Token.Address || args[0].(common.Address) != claimLink.EscrowAddress ||
+					args[1].(*big.Int).Int64() != tt.wantAmounts[i] {
+					t.Fatalf("unexpected approval %d: %v", i, args)
+				}
+			}
+		})
+	}
+}
+
+func TestClaimLinkDepositWithApproval(t *testing.T) {
+	tests := []struct {
+		name     string
+		sender   types.TransactionSender
+		wantErr  error
+		wantSent int
+	}{
+		{name: "sender can't wait for the approval", sender: &stubSender{}, wantErr: ErrApprovalRequired},
+		{
+			name:     "reverted approval",
+			sender:   &stubWaiter{receipt: &gethtypes.Receipt{Status: gethtypes.ReceiptStatusFailed}},
+			wantErr:  ErrTransactionFailed,
+			wantSent: 1,
+		},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			sdk := newTestSDK(t, func(w http.ResponseWriter, r *http.Request) {
+				t.Fatalf("unexpected request %s", r.URL)
+			})
+			claimLink := newApprovalClaimLink(t, sdk)
+
+			_, err := claimLink.DepositWithApproval(context.Background(), tt.sender, &stubCaller{output: make([]byte, 32)})
+			if !errors.Is(err, tt.wantErr) {
+				t.Fatalf("expected %v, got %v", tt.wantErr, err)
+			}
+			sent := 0
+			switch sender := tt.sender.(type) {
+			case *stubSender:
+				sent = sender.sent
+			case *stubWaiter:
+				sent = sender.sent
+			}
+			if sent != tt.wantSent || claimLink.Status != types.ClaimLinkStatusCreated {
+				t.Fatalf("unexpected %d sent transactions, status %s", sent, claimLink.Status)
+			}
+		})
+	}
+}
+
+// newNFTClaimLink returns the created link of 5 ERC721 or ERC1155 tokens with id 7 paying the fee in the native currency
+func newNFTClaimLink(t *testing.T, sdk *SDK, tokenType types.TokenType) *ClaimLink {
+	t.Helper()
+	claimLink := newApprovalClaimLink(t, sdk)
+	claimLink.Token = types.Token{
+		Type:    tokenType,
+		ChainId: types.ChainIdBase,
+		Address: common.HexToAddress("0x0000000000000000000000000000000000000721"),
+		Id:      big.NewInt(7),
+	}
+	claimLink.Amount = big.NewInt(5)
+	claimLink.TotalAmount = big.NewInt(5)
+	claimLink.Expiration = 1700000000
+	claimLink.Fee = &types.ClaimLinkFee{
+		Token:  types.Token{Type: types.TokenTypeNative, ChainId: types.ChainIdBase},
+		Amount: big.NewInt(1000),
+	}
+	escrowAddress, err := sdk.config.chains.EscrowAddressByToken(claimLink.Token)
+	if err != nil {
+		t.Fatal(err)
+	}
+	claimLink.EscrowAddress = escrowAddress
+	return claimLink
+}
+
+// nftCaller answers getApproved with approved and isApprovedForAll with approvedForAll, recording the called methods
+type nftCaller struct {
+	t              *testing.T
+	approved       common.Address
+	approvedForAll bool
+	calls          []string
+}
+
+func (c *nftCaller) CodeAt(ctx context.Context, contract common.Address, blockNumber *big.Int) ([]byte, error) {
+	return []byte{1}, nil
+}
+
+func (c *nftCaller) CallContract(ctx context.Context, call ethereum.CallMsg, blockNumber *big.Int) ([]byte, error) {
+	method, err := constants.ERC721Abi.MethodById(call.Data[:4])
+	if err != nil {
+		c.t.Fatal(err)
+	}
+	args, err := method.Inputs.Unpack(call.Data[4:])
+	if err != nil {
+		c.t.Fatal(err)
+	}
+	c.calls = append(c.calls, fmt.Sprintf("%s%v", method.Name, args))
+	if method.Name == "getApproved" {
+		return method.Outputs.Pack(c.approved)
+	}
+	return method.Outputs.Pack(c.approvedForAll)
+}
+
+func TestClaimLinkIsNFTApproved(t *testing.T) {
+	sender := "0x0000000000000000000000000000000000000004"
+	tests := []struct {
+		name      string
+		tokenType types.TokenType
+		caller    func(escrow common.Address) *nftCaller
+		want      bool
+		wantCalls []string
+	}{
+		{
+			name:      "ERC721 approved token",
+			tokenType: types.TokenTypeERC721,
+			caller:    func(escrow common.Address) *nftCaller { return &nftCaller{approved: escrow} },
+			want:      true,
+			wantCalls: []string{"getApproved[7]"},
+		},
+		{
+			name:      "ERC721 approved for all",
+			tokenType: types.TokenTypeERC721,
+			caller:    func(escrow common.Address) *nftCaller { return &nftCaller{approvedForAll: true} },
+			want:      true,
+			wantCalls: []string{"getApproved[7]", "isApprovedForAll[" + sender + " %s]"},
+		},
+		{
+			name:      "ERC721 not approved",
+			tokenType: types.TokenTypeERC721,
+			caller:    func(escrow common.Address) *nftCaller { return &nftCaller{approved: common.HexToAddress("0x01")} },
+			wantCalls: []string{"getApproved[7]", "isApprovedForAll[" + sender + " %s]"},
+		},
+		{
+			name:      "ERC1155 approved for all",
+			tokenType: types.TokenTypeERC1155,
+			caller:    func(escrow common.Address) *nftCaller { return &nftCaller{approvedForAll: true} },
+			want:      true,
+			wantCalls: []string{"isApprovedForAll[" + sender + " %s]"},
+		},
+		{
+			name:      "ERC1155 not approved",
+			tokenType: types.TokenTypeERC1155,
+			caller:    func(escrow common.Address) *nftCaller { return &nftCaller{approved: escrow} },
+			wantCalls: []string{"isApprovedForAll[" + sender + " %s]"},
+		},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			sdk := newTestSDK(t, func(w http.ResponseWriter, r *http.Request) {
+				t.Fatalf("unexpected request %s", r.URL)
+			})
+			claimLink := newNFTClaimLink(t, sdk, tt.tokenType)
+			caller := tt.caller(claimLink.EscrowAddress)
+			caller.t = t
+
+			approved, err := claimLink.IsNFTApproved(context.Background(), caller)
+			if err != nil {
+				t.Fatal(err)
+			}
+			if approved != tt.want {
+				t.Fatalf("got approved %v, want %v", approved, tt.want)
+			}
+			if len(caller.calls) != len(tt.wantCalls) {
+				t.Fatalf("unexpected calls %v", caller.calls)
+			}
+			for i, call := range tt.wantCalls {
+				if strings.Contains(call, "%s") {
+					call = fmt.Sprintf(call, claimLink.EscrowAddress.Hex())
+				}
+				if caller.calls[i] != call {
+					t.Fatalf("got call %s, want %s", caller.calls[i], call)
+				}
+			}
+		})
+	}
+}
+
+func TestClaimLinkGetNFTApprovalParams(t *testing.T) {
+	sdk := newTestSDK(t, func(w http.ResponseWriter, r *http.Request) {
+		t.Fatalf("unexpected request %s", r.URL)
+	})
+
+	erc721 := newNFTClaimLink(t, sdk, types.TokenTypeERC721)
+	params, err := erc721.GetNFTApprovalParams()
+	if err != nil {
+		t.Fatal(err)
+	}
+	method, err := constants.ERC721Abi.MethodById(params.Data[:4])
+	if err != nil || method.Name != "approve" {
+		t.Fatalf("unexpected ERC721 approval method %v: %v", method, err)
+	}
+	args, err := method.Inputs.Unpack(params.Data[4:])
+	if err != nil {
+		t.Fatal(err)
+	}
+	if params.To != erc721.Token.Address || params.Value.Sign() != 0 ||
+		args[0].(common.Address) != erc721.EscrowAddress || args[1].(*big.Int).Int64() != 7 {
+		t.Fatalf("unexpected ERC721 approval %+v: %v", params, args)
+	}
+
+	erc1155 := newNFTClaimLink(t, sdk, types.TokenTypeERC1155)
+	params, err = erc1155.GetNFTApprovalParams()
+	if err != nil {
+		t.Fatal(err)
+	}
+	method, err = constants.ERC1155Abi.MethodById(params.Data[:4])
+	if err != nil || method.Name != "setApprovalForAll" {
+		t.Fatalf("unexpected ERC1155 approval method %v: %v", method, err)
+	}
+	args, err = method.Inputs.Unpack(params.Data[4:])
+	if err != nil {
+		t.Fatal(err)
+	}
+	if params.To != erc1155.Token.Address || params.Value.Sign() != 0 ||
+		args[0].(common.Address) != erc1155.EscrowAddress || args[1].(bool) != true {
+		t.Fatalf("unexpected ERC1155 approval %+v: %v", params, args)
+	}
+
+	if _, err = newApprovalClaimLink(t, sdk).GetNFTApprovalParams(); err == nil {
+		t.Fatal("expected error for ERC20 token")
+	}
+}
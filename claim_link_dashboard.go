@@ -0,0 +1,101 @@
+package linkdrop
+
+import (
+	"context"
+	"crypto/ecdsa"
+	"errors"
+	"github.com/LinkdropHQ/linkdrop-go-sdk/helpers"
+	"github.com/LinkdropHQ/linkdrop-go-sdk/types"
+	"github.com/ethereum/go-ethereum/common"
+	"math/big"
+)
+
+// DashboardApi resolves and redeems the links created in the Linkdrop Dashboard, see WithDashboardApi.
+// The Dashboard API isn't part of the Linkdrop escrow API, so the SDK leaves its client to the caller.
+type DashboardApi interface {
+	// GetLink returns the link identified by the address of the link key
+	GetLink(ctx context.Context, linkId common.Address) (*DashboardLink, error)
+	// RedeemLink redeems the link to the receiver, receiverSig is the receiver address signed with the link key
+	RedeemLink(ctx context.Context, linkId common.Address, receiver common.Address, receiverSig []byte) (txHash common.Hash, err error)
+}
+
+// DashboardLink is the link and campaign details returned by DashboardApi
+type DashboardLink struct {
+	CampaignId   string
+	CampaignName string
+
+	Token      types.Token
+	Amount     *big.Int
+	Sender     common.Address
+	Expiration int64
+	Status     types.ClaimLinkStatus
+}
+
+// ClaimLinkDashboard is a link created in the Linkdrop Dashboard (src=d)
+type ClaimLinkDashboard struct {
+	SDK *SDK
+
+	ClaimCode string            // ClaimCode - a secret code from the claim URL
+	LinkKey   *ecdsa.PrivateKey // LinkKey - a key derived from ClaimCode, used to sign the receiver address
+	LinkId    common.Address    // LinkId - a public identifier of the link, the address of LinkKey
+
+	CampaignId   string
+	CampaignName string
+
+	Token      types.Token
+	Amount     *big.Int
+	Sender     common.Address
+	Expiration int64
+	Status     types.ClaimLinkStatus
+}
+
+// Refresh fetches the link and campaign details from the Dashboard API
+func (cld *ClaimLinkDashboard) Refresh(ctx context.Context) (err error) {
+	if cld.SDK.config.dashboardApi == nil {
+		return ErrDashboardApiNotSet
+	}
+	link, err := cld.SDK.config.dashboardApi.GetLink(ctx, cld.LinkId)
+	if err != nil {
+		return
+	}
+
+	cld.CampaignId = link.CampaignId
+	cld.CampaignName = link.CampaignName
+	cld.Token = link.Token
+	cld.Amount = link.Amount
+	cld.Sender = link.Sender
+	cld.Expiration = link.Expiration
+	cld.Status = link.Status
+	return
+}
+
+func (cld *ClaimLinkDashboard) Redeem(ctx context.Context, receiver common.Address) (txHash common.Hash, err error) {
+	if receiver == types.ZeroAddress {
+		err = errors.New("redeem: receiver is not valid")
+		return
+	}
+	if cld.LinkKey == nil {
+		err = errors.New("redeem: can't redeem without linkKey")
+		return
+	}
+	if cld.SDK.config.dashboardApi == nil {
+		err = ErrDashboardApiNotSet
+		return
+	}
+	if cld.Status == types.ClaimLinkStatusRedeeming || cld.Status == types.ClaimLinkStatusRedeemed {
+		err = ErrLinkAlreadyRedeemed
+		return
+	}
+
+	receiverSig, err := helpers.GenerateReceiverSig(cld.LinkKey, receiver)
+	if err != nil {
+		return
+	}
+
+	txHash, err = cld.SDK.config.dashboardApi.RedeemLink(ctx, cld.LinkId, receiver, receiverSig)
+	if err != nil {
+		return
+	}
+	cld.Status = types.ClaimLinkStatusRedeeming
+	return
+}
@@ -0,0 +1,106 @@
+package linkdrop
+
+import (
+	"context"
+	"errors"
+	"github.com/LinkdropHQ/linkdrop-go-sdk/helpers"
+	"github.com/LinkdropHQ/linkdrop-go-sdk/types"
+	"github.com/ethereum/go-ethereum/common"
+	"math/big"
+	"net/http"
+	"testing"
+)
+
+// stubDashboardApi serves a single link and records the redeem request
+type stubDashboardApi struct {
+	link        DashboardLink
+	linkId      common.Address
+	receiver    common.Address
+	receiverSig []byte
+}
+
+func (s *stubDashboardApi) GetLink(ctx context.Context, linkId common.Address) (*DashboardLink, error) {
+	if linkId != s.linkId {
+		return nil, ErrLinkNotFound
+	}
+	link := s.link
+	return &link, nil
+}
+
+func (s *stubDashboardApi) RedeemLink(
+	ctx context.Context,
+	linkId common.Address,
+	receiver common.Address,
+	receiverSig []byte,
+) (common.Hash, error) {
+	s.receiver = receiver
+	s.receiverSig = receiverSig
+	return common.HexToHash("0x01"), nil
+}
+
+func TestClaimLinkDashboardWithoutApi(t *testing.T) {
+	sdk := newTestSDK(t, func(w http.ResponseWriter, r *http.Request) {
+		t.Fatalf("unexpected request %s", r.URL)
+	})
+	_, err := sdk.ClaimLinkDashboard(context.Background(), "claim-code")
+	if !errors.Is(err, ErrDashboardApiNotSet) {
+		t.Fatalf("expected ErrDashboardApiNotSet, got %v", err)
+	}
+}
+
+func TestClaimLinkDashboardRedeem(t *testing.T) {
+	claimCode := "claim-code"
+	linkKey, err := helpers.DashboardLinkKey(claimCode)
+	if err != nil {
+		t.Fatal(err)
+	}
+	linkId, err := helpers.AddressFromPrivateKey(linkKey)
+	if err != nil {
+		t.Fatal(err)
+	}
+	api := &stubDashboardApi{
+		linkId: linkId,
+		link: DashboardLink{
+			CampaignId: "1",
+			Token: types.Token{
+				Type:    types.TokenTypeERC20,
+				ChainId: types.ChainIdBase,
+				Address: common.HexToAddress("0x833589fCD6eDb6E08f4c7C32D4f71b54bdA02913"),
+			},
+			Amount: big.NewInt(1000000),
+			Status: types.ClaimLinkStatusDeposited,
+		},
+	}
+	sdk := newTestSDK(t, func(w http.ResponseWriter, r *http.Request) {
+		t.Fatalf("unexpected request %s", r.URL)
+	}, WithDashboardApi(api))
+
+	claimLink, err := sdk.ClaimLinkDashboard(context.Background(), claimCode)
+	if err != nil {
+		t.Fatal(err)
+	}
+	if claimLink.LinkId != linkId || claimLink.CampaignId != "1" || claimLink.Amount.Cmp(big.NewInt(1000000)) != 0 {
+		t.Fatalf("unexpected link %+v", claimLink)
+	}
+
+	receiver := common.HexToAddress("0x0000000000000000000000000000000000000002")
+	txHash, err := claimLink.Redeem(context.Background(), receiver)
+	if err != nil {
+		t.Fatal(err)
+	}
+	if txHash != common.HexToHash("0x01") || claimLink.Status != types.ClaimLinkStatusRedeeming {
+		t.Fatalf("unexpected redeem result %s, status %s", txHash, claimLink.Status)
+	}
+	expectedSig, err := helpers.GenerateReceiverSig(linkKey, receiver)
+	if err != nil {
+		t.Fatal(err)
+	}
+	if api.receiver != receiver || common.Bytes2Hex(api.receiverSig) != common.Bytes2Hex(expectedSig) {
+		t.Fatalf("unexpected redeem request %s, %x", api.receiver, api.receiverSig)
+	}
+
+	_, err = claimLink.Redeem(context.Background(), receiver)
+	if !errors.Is(err, ErrLinkAlreadyRedeemed) {
+		t.Fatalf("expected ErrLinkAlreadyRedeemed, got %v", err)
+	}
+}
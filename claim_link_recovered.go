@@ -1,13 +1,16 @@
 package linkdrop
 
 import (
+	"context"
 	"crypto/ecdsa"
-	"encoding/json"
+	"encoding/binary"
 	"errors"
 	"github.com/LinkdropHQ/linkdrop-go-sdk/helpers"
 	"github.com/LinkdropHQ/linkdrop-go-sdk/types"
+	"github.com/ethereum/go-ethereum/accounts/abi/bind"
 	"github.com/ethereum/go-ethereum/common"
 	"github.com/ethereum/go-ethereum/signer/core/apitypes"
+	"math/big"
 )
 
 type ClaimLinkRecovered struct {
@@ -20,6 +23,11 @@ type ClaimLinkRecovered struct {
 
 	Message *types.EncryptedMessage // Message - an optional encrypted message
 
+	Operations []types.ClaimLinkOperation
+	Status     types.ClaimLinkStatus
+
+	messageErr error // messageErr - the error of the sender message lookup in SDK.GetClaimLink
+
 	LinkKey         *ecdsa.PrivateKey // LinkKey - a re-generated LinkKey that can be "promoted" with SenderSignature
 	SenderSignature []byte            // SenderSignature - a sender's signature that allows the link redemption with LinkKeyId instead of an original LinkKey
 }
@@ -27,25 +35,27 @@ type ClaimLinkRecovered struct {
 func (clr *ClaimLinkRecovered) GetTypedData(
 	linkKeyId common.Address,
 ) (*apitypes.TypedData, error) {
-	escrowVersion, err := helpers.DefineEscrowVersion(clr.EscrowAddress)
+	escrowVersion, err := clr.SDK.EscrowVersion(clr.EscrowAddress)
 	if err != nil {
 		return nil, err
 	}
-	typedData := helpers.RecoveredLinkTypedData(
+	return escrowVersion.RecoveredLinkTypedData(
 		linkKeyId,
 		clr.TransferId,
 		clr.Token.ChainId,
-		escrowVersion,
 		clr.EscrowAddress,
 	)
-	return &typedData, nil
 }
 
 // GenerateClaimUrl generates a new recovered claim URL
 func (clr *ClaimLinkRecovered) GenerateClaimUrl(
+	ctx context.Context,
 	getRandomBytes types.RandomBytesCallback,
-	signTypedData types.SignTypedDataCallback,
+	signer types.TypedDataSigner,
 ) (link string, err error) {
+	if signer == nil {
+		return "", errors.New("signer is required")
+	}
 	newLinkKey, err := helpers.PrivateKey(getRandomBytes)
 	if err != nil {
 		return
@@ -58,7 +68,7 @@ func (clr *ClaimLinkRecovered) GenerateClaimUrl(
 	if err != nil {
 		return
 	}
-	senderSignature, err := signTypedData(*typedData)
+	senderSignature, err := signer.SignTypedData(ctx, *typedData)
 	if err != nil {
 		return
 	}
@@ -84,6 +94,7 @@ func (clr *ClaimLinkRecovered) ClaimUrl(
 }
 
 func (clr *ClaimLinkRecovered) Redeem(
+	ctx context.Context,
 	receiver common.Address,
 ) (txHash common.Hash, err error) {
 	if receiver == types.ZeroAddress {
@@ -99,7 +110,8 @@ func (clr *ClaimLinkRecovered) Redeem(
 		return
 	}
 
-	bApiResp, err := clr.SDK.Client.RedeemLink(
+	redeemResp, err := clr.SDK.Client.RedeemLink(
+		ctx,
 		clr.TransferId,
 		clr.Token,
 		clr.Sender,
@@ -111,18 +123,137 @@ func (clr *ClaimLinkRecovered) Redeem(
 	if err != nil {
 		return
 	}
-	ApiRespModel := struct {
-		Success bool   `json:"success"`
-		TxHash  string `json:"tx_hash"`
-		Error   string `json:"error"`
-	}{}
-	err = json.Unmarshal(bApiResp, &ApiRespModel)
+	clr.Status = types.ClaimLinkStatusRedeeming
+	return redeemResp.TxHash, nil
+}
+
+// GetRedeemParams returns the escrow redeemRecovered transaction.
+// NOTE: the escrow accepts redeemRecovered only from its relayers (see CheckRelayer), use Redeem to redeem with the Linkdrop relayer.
+func (clr *ClaimLinkRecovered) GetRedeemParams(receiver common.Address) (params *types.TransactionParams, err error) {
+	if receiver == types.ZeroAddress {
+		return nil, errors.New("redeem: receiver is not valid")
+	}
+	if clr.LinkKey == nil || clr.SenderSignature == nil {
+		return nil, errors.New("can't redeem without linkKeyId and sender signature")
+	}
+	if clr.Status == types.ClaimLinkStatusRedeeming || clr.Status == types.ClaimLinkStatusRedeemed {
+		return nil, ErrLinkAlreadyRedeemed
+	}
+	receiverSig, err := helpers.GenerateReceiverSig(clr.LinkKey, receiver)
+	if err != nil {
+		return
+	}
+	escrowVersion, err := clr.SDK.EscrowVersion(clr.EscrowAddress)
 	if err != nil {
 		return
 	}
-	if !ApiRespModel.Success {
-		err = errors.New(ApiRespModel.Error)
+	err = escrowVersion.require(escrowVersion.Features.RecoveredLinks, "recovered links")
+	if err != nil {
+		return
+	}
+	data, err := escrowVersion.Abi(clr.Token.Type).Pack(
+		"redeemRecovered",
+		receiver,
+		clr.Sender,
+		clr.Token.Address,
+		clr.TransferId,
+		receiverSig,
+		clr.SenderSignature,
+	)
+	if err != nil {
 		return
 	}
-	return common.HexToHash(ApiRespModel.TxHash), nil
+	return &types.TransactionParams{
+		ChainId: clr.Token.ChainId,
+		Value:   big.NewInt(0),
+		Data:    data,
+		To:      clr.EscrowAddress,
+	}, nil
+}
+
+// RedeemOnChain sends the redeemRecovered transaction with sender, see GetRedeemParams and ClaimLink.RedeemOnChain.
+// The recovered link is not saved to the link store, the store keeps the links of the sender.
+func (clr *ClaimLinkRecovered) RedeemOnChain(
+	ctx context.Context,
+	receiver common.Address,
+	sender types.TransactionSender,
+) (txHash common.Hash, err error) {
+	params, err := clr.GetRedeemParams(receiver)
+	if err != nil {
+		return
+	}
+	return clr.state().send(ctx, sender, params, "redeem", receiver, types.ClaimLinkStatusRedeeming, types.ClaimLinkStatusRedeemed)
+}
+
+// CheckRelayer returns ErrNotRelayer if the account is not the relayer of the link escrow, see ClaimLink.CheckRelayer
+func (clr *ClaimLinkRecovered) CheckRelayer(ctx context.Context, caller bind.ContractCaller, account common.Address) error {
+	return checkRelayer(ctx, clr.SDK, caller, clr.EscrowAddress, clr.Token.Type, account)
+}
+
+func (clr *ClaimLinkRecovered) state() *linkState {
+	return &linkState{
+		status:     &clr.Status,
+		operations: &clr.Operations,
+		persist:    func(ctx context.Context) error { return nil },
+	}
+}
+
+func (clr *ClaimLinkRecovered) DecryptSenderMessage() (message string, err error) {
+	return decryptSenderMessage(clr.Message, clr.messageErr)
+}
+
+// LoadSenderMessage fetches the encrypted sender message of the link, see WithReceiptReader.
+// The message key should be added with AddMessageKey to decrypt it.
+func (clr *ClaimLinkRecovered) LoadSenderMessage(ctx context.Context) (err error) {
+	clr.Message, err = clr.SDK.loadSenderMessage(ctx, clr.Token.ChainId, clr.TransferId, clr.Message)
+	clr.messageErr = err
+	return
+}
+
+// AddMessageKey re-derives the message key from the sender's signature, see helpers.MessageInitialKeyCreate.
+// The key is carried in the claim URLs generated afterwards.
+// encryptionKeyLength is used only if the encrypted message is not loaded, otherwise the length is read from it.
+func (clr *ClaimLinkRecovered) AddMessageKey(
+	ctx context.Context,
+	encryptionKeyLength uint16,
+	signer types.TypedDataSigner,
+) (err error) {
+	if signer == nil {
+		return errors.New("signer is required")
+	}
+	initialKey, err := helpers.MessageInitialKeyCreate(ctx, clr.TransferId, clr.Token.ChainId, signer)
+	if err != nil {
+		return
+	}
+	return clr.AddMessageKeyWithInitialKey(encryptionKeyLength, initialKey)
+}
+
+// AddMessageKeyWithInitialKey sets the message key derived from the initial key, see AddMessageKey
+func (clr *ClaimLinkRecovered) AddMessageKeyWithInitialKey(
+	encryptionKeyLength uint16,
+	initialKey types.MessageInitialKey,
+) (err error) {
+	message := types.EncryptedMessage{}
+	if clr.Message != nil {
+		message.Data = clr.Message.Data
+	}
+	if len(message.Data) >= 2 {
+		// the message data starts with the message key length, see helpers.MessageEncrypt
+		encryptionKeyLength = binary.BigEndian.Uint16(message.Data[:2])
+	}
+	if encryptionKeyLength == 0 {
+		encryptionKeyLength = 12
+	}
+	if encryptionKeyLength > clr.SDK.config.messageConfig.MaxEncryptionKeyLength ||
+		encryptionKeyLength < clr.SDK.config.messageConfig.MinEncryptionKeyLength {
+		return errors.New("wrong encryption key length")
+	}
+	message.LinkKey = initialKey.LinkKey(encryptionKeyLength)
+	if len(message.Data) > 0 {
+		if _, err = helpers.MessageDecrypt(&message); err != nil {
+			return errors.New("message key doesn't match the encrypted message")
+		}
+	}
+	clr.Message = &message
+	return
 }
@@ -0,0 +1,143 @@
+package linkdrop
+
+import (
+	"bytes"
+	"context"
+	"encoding/binary"
+	"github.com/LinkdropHQ/linkdrop-go-sdk/crypto"
+	"github.com/LinkdropHQ/linkdrop-go-sdk/helpers"
+	"github.com/LinkdropHQ/linkdrop-go-sdk/types"
+	"github.com/ethereum/go-ethereum/signer/core/apitypes"
+	"net/http"
+	"testing"
+)
+
+// testMessageSignature is the sender's signature of the message key typed data
+var testMessageSignature = bytes.Repeat([]byte{7}, 65)
+
+// newRecoveredClaimLink returns the recovered link of newDepositedClaimLink
+func newRecoveredClaimLink(t *testing.T, sdk *SDK) *ClaimLinkRecovered {
+	t.Helper()
+	claimLink := newDepositedClaimLink(t, sdk)
+	return &ClaimLinkRecovered{
+		SDK:           sdk,
+		TransferId:    claimLink.TransferId,
+		Token:         claimLink.Token,
+		EscrowAddress: claimLink.EscrowAddress,
+		Status:        types.ClaimLinkStatusDeposited,
+	}
+}
+
+// encryptTestMessage encrypts the message with the link key of initialKey cut to linkKeyLength
+func encryptTestMessage(t *testing.T, initialKey types.MessageInitialKey, linkKeyLength uint16) *types.EncryptedMessage {
+	t.Helper()
+	message, err := helpers.MessageEncrypt("message", initialKey, linkKeyLength, [crypto.NonceLength]byte{1})
+	if err != nil {
+		t.Fatal(err)
+	}
+	return message
+}
+
+func TestClaimLinkRecoveredAddMessageKey(t *testing.T) {
+	initialKey := helpers.MessageInitialKeyFromSignature(testMessageSignature)
+	otherKey := helpers.MessageInitialKeyFromSignature(bytes.Repeat([]byte{8}, 65))
+	tests := []struct {
+		name          string
+		message       func(t *testing.T) *types.EncryptedMessage
+		keyLength     uint16
+		wantKeyLength int
+		wantErr       string
+	}{
+		{
+			name:          "length from the loaded message",
+			message:       func(t *testing.T) *types.EncryptedMessage { return encryptTestMessage(t, initialKey, 20) },
+			keyLength:     12,
+			wantKeyLength: 20,
+		},
+		{name: "length argument without the message", keyLength: 16, wantKeyLength: 16},
+		{name: "fallback to 12", wantKeyLength: 12},
+		{
+			name: "zero length of the loaded message falls back to 12",
+			message: func(t *testing.T) *types.EncryptedMessage {
+				message := encryptTestMessage(t, initialKey, 12)
+				binary.BigEndian.PutUint16(message.Data[:2], 0)
+				return message
+			},
+			keyLength:     20,
+			wantKeyLength: 12,
+		},
+		{name: "too short length argument", keyLength: 5, wantErr: "wrong encryption key length"},
+		{name: "too long length argument", keyLength: 44, wantErr: "wrong encryption key length"},
+		{
+			name: "too long length of the loaded message",
+			message: func(t *testing.T) *types.EncryptedMessage {
+				message := encryptTestMessage(t, initialKey, 12)
+				binary.BigEndian.PutUint16(message.Data[:2], 44)
+				return message
+			},
+			keyLength: 12,
+			wantErr:   "wrong encryption key length",
+		},
+		{
+			name:      "message of another key",
+			message:   func(t *testing.T) *types.EncryptedMessage { return encryptTestMessage(t, otherKey, 12) },
+			keyLength: 12,
+			wantErr:   "message key doesn't match the encrypted message",
+		},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			sdk := newTestSDK(t, func(w http.ResponseWriter, r *http.Request) {
+				t.Fatalf("unexpected request %s", r.URL)
+			})
+			recovered := newRecoveredClaimLink(t, sdk)
+			if tt.message != nil {
+				recovered.Message = tt.message(t)
+				recovered.Message.LinkKey = ""
+			}
+			loaded := recovered.Message
+			var signed *apitypes.TypedData
+			signer := types.SignTypedDataCallback(func(ctx context.Context, typedData apitypes.TypedData) ([]byte, error) {
+				signed = &typedData
+				return testMessageSignature, nil
+			})
+
+			err := recovered.AddMessageKey(context.Background(), tt.keyLength, signer)
+			if tt.wantErr != "" {
+				if err == nil || err.Error() != tt.wantErr {
+					t.Fatalf("expected %q, got %v", tt.wantErr, err)
+				}
+				if recovered.Message != loaded {
+					t.Fatal("message is replaced")
+				}
+				return
+			}
+			if err != nil {
+				t.Fatal(err)
+			}
+			wantTypedData := helpers.MessageInitialKeyTypedData(recovered.TransferId, recovered.Token.ChainId)
+			if signed == nil || signed.PrimaryType != wantTypedData.PrimaryType || signed.Message["seed"] != wantTypedData.Message["seed"] {
+				t.Fatalf("unexpected signed typed data %+v", signed)
+			}
+			if len(recovered.Message.LinkKey) != tt.wantKeyLength || recovered.Message.LinkKey != initialKey.LinkKey(uint16(tt.wantKeyLength)) {
+				t.Fatalf("unexpected link key %q", recovered.Message.LinkKey)
+			}
+			if loaded != nil {
+				if !bytes.Equal(recovered.Message.Data, loaded.Data) {
+					t.Fatal("message data is modified")
+				}
+			}
+		})
+	}
+}
+
+func TestClaimLinkRecoveredAddMessageKeyWithoutSigner(t *testing.T) {
+	sdk := newTestSDK(t, func(w http.ResponseWriter, r *http.Request) {
+		t.Fatalf("unexpected request %s", r.URL)
+	})
+	recovered := newRecoveredClaimLink(t, sdk)
+	err := recovered.AddMessageKey(context.Background(), 12, nil)
+	if err == nil || err.Error() != "signer is required" || recovered.Message != nil {
+		t.Fatalf("expected signer error, got %v", err)
+	}
+}
@@ -0,0 +1,503 @@
+package linkdrop
+
+import (
+	"crypto/ecdsa"
+	"crypto/rand"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"github.com/LinkdropHQ/linkdrop-go-sdk/crypto"
+	"github.com/LinkdropHQ/linkdrop-go-sdk/helpers"
+	"github.com/LinkdropHQ/linkdrop-go-sdk/keywrap"
+	"github.com/LinkdropHQ/linkdrop-go-sdk/types"
+	"github.com/ethereum/go-ethereum/common"
+	"github.com/ethereum/go-ethereum/common/hexutil"
+	"math/big"
+)
+
+// SnapshotVersion is the current version of the claim link snapshot format.
+// It's stored in the snapshot and as the first byte of the binary form.
+const SnapshotVersion = 1
+
+// ClaimLinkSnapshot is a serializable state of ClaimLink.
+// Use ClaimLink.Snapshot to create it and SDK.RestoreClaimLink to get the ClaimLink back.
+type ClaimLinkSnapshot struct {
+	Version    int            `json:"version"`
+	TransferId common.Address `json:"transferId"`
+	SnapshotLinkKey
+	Token         types.Token                `json:"token"`
+	Amount        *big.Int                   `json:"amount"`
+	Sender        common.Address             `json:"sender"`
+	Fee           *SnapshotFee               `json:"fee,omitempty"`
+	TotalAmount   *big.Int                   `json:"totalAmount"`
+	Message       *SnapshotMessage           `json:"message,omitempty"`
+	EscrowAddress common.Address             `json:"escrowAddress"`
+	Expiration    int64                      `json:"expiration"`
+	Operations    []types.ClaimLinkOperation `json:"operations"`
+	Status        types.ClaimLinkStatus      `json:"status"`
+}
+
+// ClaimLinkRecoveredSnapshot is a serializable state of ClaimLinkRecovered.
+// Use ClaimLinkRecovered.Snapshot to create it and SDK.RestoreClaimLinkRecovered to get the ClaimLinkRecovered back.
+type ClaimLinkRecoveredSnapshot struct {
+	Version    int            `json:"version"`
+	TransferId common.Address `json:"transferId"`
+	SnapshotLinkKey
+	Sender          common.Address   `json:"sender"`
+	Token           types.Token      `json:"token"`
+	EscrowAddress   common.Address   `json:"escrowAddress"`
+	Message         *SnapshotMessage `json:"message,omitempty"`
+	SenderSignature hexutil.Bytes    `json:"senderSignature,omitempty"`
+
+	Operations []types.ClaimLinkOperation `json:"operations,omitempty"`
+	Status     types.ClaimLinkStatus      `json:"status,omitempty"`
+}
+
+// SnapshotLinkKey is the link key stored in the snapshot in one of the forms depending on SnapshotOption
+type SnapshotLinkKey struct {
+	LinkKey          hexutil.Bytes     `json:"linkKey,omitempty"`          // LinkKey - plain link key stored with SnapshotWithLinkKey
+	EncryptedLinkKey hexutil.Bytes     `json:"encryptedLinkKey,omitempty"` // EncryptedLinkKey - link key encrypted with SnapshotWithEncryptedLinkKey
+	WrappedLinkKey   *keywrap.Envelope `json:"wrappedLinkKey,omitempty"`   // WrappedLinkKey - link key sealed with SnapshotWithKeyWrapper
+}
+
+// SnapshotFee is a serializable form of types.ClaimLinkFee
+type SnapshotFee struct {
+	Token         types.Token   `json:"token"`
+	Amount        *big.Int      `json:"amount"`
+	Authorization hexutil.Bytes `json:"authorization,omitempty"`
+}
+
+// SnapshotMessage is a serializable form of types.EncryptedMessage
+type SnapshotMessage struct {
+	Data           hexutil.Bytes        `json:"data"`
+	LinkKey        types.MessageLinkKey `json:"linkKey,omitempty"`
+	WrappedLinkKey *keywrap.Envelope    `json:"wrappedLinkKey,omitempty"` // WrappedLinkKey - message key sealed with SnapshotWithKeyWrapper
+}
+
+type snapshotConfig struct {
+	excludeLinkKey    bool
+	includeLinkKey    bool // includeLinkKey - store the plain link key, it's excluded by default
+	encryptionKey     *[crypto.KeyLength]byte
+	keyWrapper        keywrap.KeyWrapper
+	skipEncryptedKeys bool // skipEncryptedKeys - restore without the link key if it's encrypted instead of failing
+}
+
+// SnapshotOption configures how the link key is stored in the snapshot and how it's restored
+type SnapshotOption func(*snapshotConfig)
+
+// SnapshotWithoutLinkKey excludes the link key from the snapshot, even if it's encrypted, and ignores it on restore.
+// The restored link has no LinkKey and lacks the functionality that requires it (e.g. ClaimUrl).
+func SnapshotWithoutLinkKey() SnapshotOption {
+	return func(sc *snapshotConfig) {
+		sc.excludeLinkKey = true
+	}
+}
+
+// SnapshotWithLinkKey stores the plain link key in the snapshot.
+// Anyone reading the snapshot can redeem the link, prefer SnapshotWithEncryptedLinkKey or SnapshotWithKeyWrapper.
+func SnapshotWithLinkKey() SnapshotOption {
+	return func(sc *snapshotConfig) {
+		sc.includeLinkKey = true
+	}
+}
+
+// SnapshotWithEncryptedLinkKey encrypts the link key with the provided symmetric key.
+// Pass the same option to SDK.RestoreClaimLink or SDK.RestoreClaimLinkRecovered to decrypt it.
+func SnapshotWithEncryptedLinkKey(encryptionKey [crypto.KeyLength]byte) SnapshotOption {
+	return func(sc *snapshotConfig) {
+		sc.encryptionKey = &encryptionKey
+	}
+}
+
+// SnapshotWithKeyWrapper seals the link key and the message key using envelope encryption, see keywrap package.
+// Pass the same option to SDK.RestoreClaimLink or SDK.RestoreClaimLinkRecovered to open them.
+func SnapshotWithKeyWrapper(keyWrapper keywrap.KeyWrapper) SnapshotOption {
+	return func(sc *snapshotConfig) {
+		sc.keyWrapper = keyWrapper
+	}
+}
+
+func newSnapshotConfig(opts []SnapshotOption) *snapshotConfig {
+	sc := &snapshotConfig{}
+	for _, opt := range opts {
+		opt(sc)
+	}
+	return sc
+}
+
+// encodeLinkKey returns the link key in the form defined by the options
+func (sc *snapshotConfig) encodeLinkKey(linkKey *ecdsa.PrivateKey) (slk SnapshotLinkKey, err error) {
+	if linkKey == nil || sc.excludeLinkKey {
+		return
+	}
+	if sc.keyWrapper != nil {
+		slk.WrappedLinkKey, err = keywrap.SealLinkKey(sc.keyWrapper, linkKey)
+		return
+	}
+	plain := helpers.PrivateKeyBytes(linkKey)
+	if sc.encryptionKey == nil {
+		if sc.includeLinkKey {
+			slk.LinkKey = plain
+		}
+		return
+	}
+	var iv [crypto.NonceLength]byte
+	if _, err = rand.Read(iv[:]); err != nil {
+		return
+	}
+	slk.EncryptedLinkKey, err = crypto.Encrypt(plain, *sc.encryptionKey, iv)
+	return
+}
+
+// decodeLinkKey restores the link key from any of the forms
+func (sc *snapshotConfig) decodeLinkKey(slk SnapshotLinkKey) (*ecdsa.PrivateKey, error) {
+	if sc.excludeLinkKey {
+		return nil, nil
+	}
+	if slk.WrappedLinkKey != nil {
+		if sc.keyWrapper == nil {
+			if sc.skipEncryptedKeys {
+				return nil, nil
+			}
+			return nil, errors.New("link key is wrapped, use SnapshotWithKeyWrapper to restore it")
+		}
+		linkKey, err := keywrap.OpenLinkKey(sc.keyWrapper, slk.WrappedLinkKey)
+		if err != nil {
+			return nil, fmt.Errorf("failed to unwrap link key: %w", err)
+		}
+		return linkKey, nil
+	}
+	plain, encrypted := slk.LinkKey, slk.EncryptedLinkKey
+	if len(encrypted) > 0 {
+		if sc.encryptionKey == nil {
+			if sc.skipEncryptedKeys {
+				return nil, nil
+			}
+			return nil, errors.New("link key is encrypted, use SnapshotWithEncryptedLinkKey to restore it")
+		}
+		decrypted, err := crypto.Decrypt(encrypted, *sc.encryptionKey)
+		if err != nil {
+			return nil, fmt.Errorf("failed to decrypt link key: %w", err)
+		}
+		plain = []byte(decrypted)
+	}
+	if len(plain) == 0 {
+		return nil, nil
+	}
+	return helpers.PrivateKeyFromBytes(plain)
+}
+
+// Snapshot returns the serializable state of the claim link.
+// The link key is excluded unless SnapshotWithEncryptedLinkKey, SnapshotWithKeyWrapper or SnapshotWithLinkKey is provided.
+func (cl *ClaimLink) Snapshot(opts ...SnapshotOption) (snapshot *ClaimLinkSnapshot, err error) {
+	snapshot = &ClaimLinkSnapshot{
+		Version:       SnapshotVersion,
+		TransferId:    cl.TransferId,
+		Token:         cl.Token,
+		Amount:        cl.Amount,
+		Sender:        cl.Sender,
+		TotalAmount:   cl.TotalAmount,
+		EscrowAddress: cl.EscrowAddress,
+		Expiration:    cl.Expiration,
+		Operations:    cl.Operations,
+		Status:        cl.Status,
+	}
+	if cl.Fee != nil {
+		snapshot.Fee = &SnapshotFee{
+			Token:         cl.Fee.Token,
+			Amount:        cl.Fee.Amount,
+			Authorization: cl.Fee.Authorization,
+		}
+	}
+	sc := newSnapshotConfig(opts)
+	snapshot.SnapshotLinkKey, err = sc.encodeLinkKey(cl.LinkKey)
+	if err != nil {
+		return nil, err
+	}
+	snapshot.Message, err = sc.encodeMessage(cl.Message)
+	if err != nil {
+		return nil, err
+	}
+	return
+}
+
+// MarshalJSON encodes the claim link as ClaimLinkSnapshot without the link key.
+// Use Snapshot to store the link key encrypted or as is.
+func (cl *ClaimLink) MarshalJSON() ([]byte, error) {
+	snapshot, err := cl.Snapshot()
+	if err != nil {
+		return nil, err
+	}
+	return json.Marshal(snapshot)
+}
+
+// UnmarshalJSON decodes the claim link from ClaimLinkSnapshot.
+// SDK is not set and the encrypted link key is not restored, use SDK.RestoreClaimLink for that.
+func (cl *ClaimLink) UnmarshalJSON(data []byte) error {
+	var snapshot ClaimLinkSnapshot
+	if err := json.Unmarshal(data, &snapshot); err != nil {
+		return err
+	}
+	return cl.restore(&snapshot, &snapshotConfig{skipEncryptedKeys: true})
+}
+
+// MarshalBinary encodes the claim link in the versioned binary form without the link key, see ClaimLinkSnapshot.MarshalBinary
+func (cl *ClaimLink) MarshalBinary() ([]byte, error) {
+	snapshot, err := cl.Snapshot()
+	if err != nil {
+		return nil, err
+	}
+	return snapshot.MarshalBinary()
+}
+
+// UnmarshalBinary decodes the claim link from the versioned binary form, see ClaimLink.UnmarshalJSON
+func (cl *ClaimLink) UnmarshalBinary(data []byte) error {
+	var snapshot ClaimLinkSnapshot
+	if err := snapshot.UnmarshalBinary(data); err != nil {
+		return err
+	}
+	return cl.restore(&snapshot, &snapshotConfig{skipEncryptedKeys: true})
+}
+
+// MarshalBinary encodes the snapshot as [version(1 byte), JSON payload(...)]
+func (s *ClaimLinkSnapshot) MarshalBinary() ([]byte, error) {
+	return marshalSnapshotBinary(s.Version, s)
+}
+
+func (s *ClaimLinkSnapshot) UnmarshalBinary(data []byte) error {
+	return unmarshalSnapshotBinary(data, s, &s.Version)
+}
+
+// restore fills the claim link from the snapshot keeping the current SDK
+func (cl *ClaimLink) restore(snapshot *ClaimLinkSnapshot, sc *snapshotConfig) (err error) {
+	if err = checkSnapshotVersion(snapshot.Version); err != nil {
+		return
+	}
+	linkKey, err := sc.decodeLinkKey(snapshot.SnapshotLinkKey)
+	if err != nil {
+		return
+	}
+	message, err := sc.decodeMessage(snapshot.Message)
+	if err != nil {
+		return
+	}
+	var fee *types.ClaimLinkFee
+	if snapshot.Fee != nil {
+		fee = &types.ClaimLinkFee{
+			Token:         snapshot.Fee.Token,
+			Amount:        snapshot.Fee.Amount,
+			Authorization: snapshot.Fee.Authorization,
+		}
+	}
+	*cl = ClaimLink{
+		SDK:           cl.SDK,
+		LinkKey:       linkKey,
+		TransferId:    snapshot.TransferId,
+		Token:         snapshot.Token,
+		Amount:        snapshot.Amount,
+		Sender:        snapshot.Sender,
+		Fee:           fee,
+		TotalAmount:   snapshot.TotalAmount,
+		Message:       message,
+		EscrowAddress: snapshot.EscrowAddress,
+		Expiration:    snapshot.Expiration,
+		Operations:    snapshot.Operations,
+		Status:        snapshot.Status,
+	}
+	return
+}
+
+// Snapshot returns the serializable state of the recovered claim link, see ClaimLink.Snapshot
+func (clr *ClaimLinkRecovered) Snapshot(opts ...SnapshotOption) (snapshot *ClaimLinkRecoveredSnapshot, err error) {
+	snapshot = &ClaimLinkRecoveredSnapshot{
+		Version:         SnapshotVersion,
+		TransferId:      clr.TransferId,
+		Sender:          clr.Sender,
+		Token:           clr.Token,
+		EscrowAddress:   clr.EscrowAddress,
+		SenderSignature: clr.SenderSignature,
+		Operations:      clr.Operations,
+		Status:          clr.Status,
+	}
+	sc := newSnapshotConfig(opts)
+	snapshot.SnapshotLinkKey, err = sc.encodeLinkKey(clr.LinkKey)
+	if err != nil {
+		return nil, err
+	}
+	snapshot.Message, err = sc.encodeMessage(clr.Message)
+	if err != nil {
+		return nil, err
+	}
+	return
+}
+
+// MarshalJSON encodes the recovered claim link as ClaimLinkRecoveredSnapshot without the link key, see ClaimLink.MarshalJSON
+func (clr *ClaimLinkRecovered) MarshalJSON() ([]byte, error) {
+	snapshot, err := clr.Snapshot()
+	if err != nil {
+		return nil, err
+	}
+	return json.Marshal(snapshot)
+}
+
+// UnmarshalJSON decodes the recovered claim link from ClaimLinkRecoveredSnapshot, see ClaimLink.UnmarshalJSON
+func (clr *ClaimLinkRecovered) UnmarshalJSON(data []byte) error {
+	var snapshot ClaimLinkRecoveredSnapshot
+	if err := json.Unmarshal(data, &snapshot); err != nil {
+		return err
+	}
+	return clr.restore(&snapshot, &snapshotConfig{skipEncryptedKeys: true})
+}
+
+func (clr *ClaimLinkRecovered) MarshalBinary() ([]byte, error) {
+	snapshot, err := clr.Snapshot()
+	if err != nil {
+		return nil, err
+	}
+	return snapshot.MarshalBinary()
+}
+
+func (clr *ClaimLinkRecovered) UnmarshalBinary(data []byte) error {
+	var snapshot ClaimLinkRecoveredSnapshot
+	if err := snapshot.UnmarshalBinary(data); err != nil {
+		return err
+	}
+	return clr.restore(&snapshot, &snapshotConfig{skipEncryptedKeys: true})
+}
+
+// MarshalBinary encodes the snapshot as [version(1 byte), JSON payload(...)]
+func (s *ClaimLinkRecoveredSnapshot) MarshalBinary() ([]byte, error) {
+	return marshalSnapshotBinary(s.Version, s)
+}
+
+func (s *ClaimLinkRecoveredSnapshot) UnmarshalBinary(data []byte) error {
+	return unmarshalSnapshotBinary(data, s, &s.Version)
+}
+
+func (clr *ClaimLinkRecovered) restore(snapshot *ClaimLinkRecoveredSnapshot, sc *snapshotConfig) (err error) {
+	if err = checkSnapshotVersion(snapshot.Version); err != nil {
+		return
+	}
+	linkKey, err := sc.decodeLinkKey(snapshot.SnapshotLinkKey)
+	if err != nil {
+		return
+	}
+	message, err := sc.decodeMessage(snapshot.Message)
+	if err != nil {
+		return
+	}
+	*clr = ClaimLinkRecovered{
+		SDK:             clr.SDK,
+		TransferId:      snapshot.TransferId,
+		Sender:          snapshot.Sender,
+		Token:           snapshot.Token,
+		EscrowAddress:   snapshot.EscrowAddress,
+		Message:         message,
+		LinkKey:         linkKey,
+		SenderSignature: snapshot.SenderSignature,
+		Operations:      snapshot.Operations,
+		Status:          snapshot.Status,
+	}
+	return
+}
+
+// encodeMessage returns the message with the message key sealed if SnapshotWithKeyWrapper is provided
+func (sc *snapshotConfig) encodeMessage(message *types.EncryptedMessage) (sm *SnapshotMessage, err error) {
+	if message == nil {
+		return nil, nil
+	}
+	sm = &SnapshotMessage{Data: message.Data}
+	if sc.keyWrapper == nil || message.LinkKey == "" {
+		sm.LinkKey = message.LinkKey
+		return
+	}
+	sm.WrappedLinkKey, err = keywrap.SealMessageLinkKey(sc.keyWrapper, message.LinkKey)
+	return
+}
+
+func (sc *snapshotConfig) decodeMessage(sm *SnapshotMessage) (message *types.EncryptedMessage, err error) {
+	if sm == nil {
+		return nil, nil
+	}
+	message = &types.EncryptedMessage{
+		Data:    sm.Data,
+		LinkKey: sm.LinkKey,
+	}
+	if sm.WrappedLinkKey == nil {
+		return
+	}
+	if sc.keyWrapper == nil {
+		if sc.skipEncryptedKeys {
+			return
+		}
+		return nil, errors.New("message key is wrapped, use SnapshotWithKeyWrapper to restore it")
+	}
+	message.LinkKey, err = keywrap.OpenMessageLinkKey(sc.keyWrapper, sm.WrappedLinkKey)
+	if err != nil {
+		return nil, fmt.Errorf("failed to unwrap message key: %w", err)
+	}
+	return
+}
+
+// Rewrap re-wraps the sealed keys of the snapshot with the current KEK, see keywrap.Rewrap
+func (s *ClaimLinkSnapshot) Rewrap(keyWrapper keywrap.KeyWrapper) (err error) {
+	return rewrapSnapshot(keyWrapper, &s.SnapshotLinkKey, s.Message)
+}
+
+// Rewrap re-wraps the sealed keys of the snapshot with the current KEK, see keywrap.Rewrap
+func (s *ClaimLinkRecoveredSnapshot) Rewrap(keyWrapper keywrap.KeyWrapper) (err error) {
+	return rewrapSnapshot(keyWrapper, &s.SnapshotLinkKey, s.Message)
+}
+
+// rewrapSnapshot updates the snapshot only if all the keys are re-wrapped successfully
+func rewrapSnapshot(keyWrapper keywrap.KeyWrapper, slk *SnapshotLinkKey, sm *SnapshotMessage) (err error) {
+	linkKey := slk.WrappedLinkKey
+	if linkKey != nil {
+		if linkKey, err = keywrap.Rewrap(keyWrapper, linkKey); err != nil {
+			return
+		}
+	}
+	var messageKey *keywrap.Envelope
+	if sm != nil && sm.WrappedLinkKey != nil {
+		if messageKey, err = keywrap.Rewrap(keyWrapper, sm.WrappedLinkKey); err != nil {
+			return
+		}
+		sm.WrappedLinkKey = messageKey
+	}
+	slk.WrappedLinkKey = linkKey
+	return
+}
+
+func checkSnapshotVersion(version int) error {
+	if version < 1 || version > SnapshotVersion {
+		return fmt.Errorf("unsupported snapshot version: %d", version)
+	}
+	return nil
+}
+
+func marshalSnapshotBinary(version int, snapshot any) ([]byte, error) {
+	if err := checkSnapshotVersion(version); err != nil {
+		return nil, err
+	}
+	payload, err := json.Marshal(snapshot)
+	if err != nil {
+		return nil, err
+	}
+	return append([]byte{byte(version)}, payload...), nil
+}
+
+func unmarshalSnapshotBinary(data []byte, snapshot any, version *int) error {
+	if len(data) < 1 {
+		return errors.New("invalid snapshot format")
+	}
+	if err := checkSnapshotVersion(int(data[0])); err != nil {
+		return err
+	}
+	if err := json.Unmarshal(data[1:], snapshot); err != nil {
+		return err
+	}
+	if *version != int(data[0]) {
+		return errors.New("snapshot version mismatch")
+	}
+	return nil
+}
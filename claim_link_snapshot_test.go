@@ -0,0 +1,185 @@
+package linkdrop
+
+import (
+	"bytes"
+	"encoding/hex"
+	"encoding/json"
+	"github.com/LinkdropHQ/linkdrop-go-sdk/crypto"
+	"github.com/LinkdropHQ/linkdrop-go-sdk/keywrap"
+	"github.com/LinkdropHQ/linkdrop-go-sdk/types"
+	gethcrypto "github.com/ethereum/go-ethereum/crypto"
+	"net/http"
+	"testing"
+)
+
+// newSnapshotClaimLink returns the deposited link with the link key, the fee and the message
+func newSnapshotClaimLink(t *testing.T, sdk *SDK) *ClaimLink {
+	t.Helper()
+	claimLink := newDepositedClaimLink(t, sdk)
+	linkKey, err := gethcrypto.GenerateKey()
+	if err != nil {
+		t.Fatal(err)
+	}
+	claimLink.LinkKey = linkKey
+	claimLink.Fee = &types.ClaimLinkFee{Token: claimLink.Token, Amount: claimLink.Amount, Authorization: []byte{1, 2}}
+	claimLink.TotalAmount = claimLink.Amount
+	claimLink.Message = &types.EncryptedMessage{Data: []byte{3, 4}, LinkKey: "message-key"}
+	return claimLink
+}
+
+func TestClaimLinkSnapshotRoundTrip(t *testing.T) {
+	var encryptionKey [crypto.KeyLength]byte
+	copy(encryptionKey[:], "snapshot-encryption-key-32-bytes")
+	keyWrapper, err := keywrap.NewAESGCM("kek-1", encryptionKey)
+	if err != nil {
+		t.Fatal(err)
+	}
+	otherKeyWrapper, err := keywrap.NewAESGCM("kek-1", [32]byte{1})
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	tests := []struct {
+		name        string
+		opts        []SnapshotOption
+		restoreOpts []SnapshotOption
+		wantPlain   bool // wantPlain - the plain link key is stored in the snapshot
+		wantLinkKey bool // wantLinkKey - the link key is restored
+		wantErr     bool
+	}{
+		{name: "default", wantLinkKey: false},
+		{name: "plain link key", opts: []SnapshotOption{SnapshotWithLinkKey()}, wantPlain: true, wantLinkKey: true},
+		{name: "without link key", opts: []SnapshotOption{SnapshotWithLinkKey(), SnapshotWithoutLinkKey()}},
+		{
+			name:        "encrypted link key",
+			opts:        []SnapshotOption{SnapshotWithEncryptedLinkKey(encryptionKey)},
+			restoreOpts: []SnapshotOption{SnapshotWithEncryptedLinkKey(encryptionKey)},
+			wantLinkKey: true,
+		},
+		{
+			name:    "encrypted link key without encryption key",
+			opts:    []SnapshotOption{SnapshotWithEncryptedLinkKey(encryptionKey)},
+			wantErr: true,
+		},
+		{
+			name:        "wrapped link key",
+			opts:        []SnapshotOption{SnapshotWithKeyWrapper(keyWrapper)},
+			restoreOpts: []SnapshotOption{SnapshotWithKeyWrapper(keyWrapper)},
+			wantLinkKey: true,
+		},
+		{
+			name:        "wrapped link key with another KEK",
+			opts:        []SnapshotOption{SnapshotWithKeyWrapper(keyWrapper)},
+			restoreOpts: []SnapshotOption{SnapshotWithKeyWrapper(otherKeyWrapper)},
+			wantErr:     true,
+		},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			sdk := newTestSDK(t, func(w http.ResponseWriter, r *http.Request) {
+				t.Fatalf("unexpected request %s", r.URL)
+			})
+			claimLink := newSnapshotClaimLink(t, sdk)
+			snapshot, err := claimLink.Snapshot(tt.opts...)
+			if err != nil {
+				t.Fatal(err)
+			}
+			data, err := json.Marshal(snapshot)
+			if err != nil {
+				t.Fatal(err)
+			}
+			plain := bytes.Contains(data, []byte(hex.EncodeToString(gethcrypto.FromECDSA(claimLink.LinkKey))))
+			if tt.wantPlain != (len(snapshot.LinkKey) > 0) || (!tt.wantPlain && plain) {
+				t.Fatalf("unexpected plain link key in %s", data)
+			}
+
+			var decoded ClaimLinkSnapshot
+			if err = json.Unmarshal(data, &decoded); err != nil {
+				t.Fatal(err)
+			}
+			restored, err := sdk.RestoreClaimLink(&decoded, tt.restoreOpts...)
+			if tt.wantErr {
+				if err == nil {
+					t.Fatal("expected error")
+				}
+				return
+			}
+			if err != nil {
+				t.Fatal(err)
+			}
+			if tt.wantLinkKey != (restored.LinkKey != nil) ||
+				(restored.LinkKey != nil && !restored.LinkKey.Equal(claimLink.LinkKey)) {
+				t.Fatalf("unexpected restored link key %v", restored.LinkKey)
+			}
+			if restored.TransferId != claimLink.TransferId ||
+				restored.Status != claimLink.Status ||
+				restored.Amount.Cmp(claimLink.Amount) != 0 ||
+				restored.Fee == nil || !bytes.Equal(restored.Fee.Authorization, claimLink.Fee.Authorization) ||
+				restored.Message == nil || restored.Message.LinkKey != claimLink.Message.LinkKey ||
+				restored.SDK != sdk {
+				t.Fatalf("unexpected restored link %+v", restored)
+			}
+		})
+	}
+}
+
+func TestClaimLinkMarshalExcludesLinkKey(t *testing.T) {
+	sdk := newTestSDK(t, func(w http.ResponseWriter, r *http.Request) {
+		t.Fatalf("unexpected request %s", r.URL)
+	})
+	claimLink := newSnapshotClaimLink(t, sdk)
+
+	data, err := json.Marshal(claimLink)
+	if err != nil {
+		t.Fatal(err)
+	}
+	if bytes.Contains(data, []byte(`"linkKey":"0x`)) {
+		t.Fatalf("link key is marshaled: %s", data)
+	}
+	var decoded ClaimLink
+	if err = json.Unmarshal(data, &decoded); err != nil {
+		t.Fatal(err)
+	}
+	if decoded.LinkKey != nil || decoded.TransferId != claimLink.TransferId || decoded.Status != claimLink.Status {
+		t.Fatalf("unexpected decoded link %+v", decoded)
+	}
+
+	binary, err := claimLink.MarshalBinary()
+	if err != nil {
+		t.Fatal(err)
+	}
+	if binary[0] != SnapshotVersion {
+		t.Fatalf("unexpected binary version %d", binary[0])
+	}
+	decoded = ClaimLink{}
+	if err = decoded.UnmarshalBinary(binary); err != nil {
+		t.Fatal(err)
+	}
+	if decoded.LinkKey != nil || decoded.TransferId != claimLink.TransferId {
+		t.Fatalf("unexpected decoded link %+v", decoded)
+	}
+
+	recovered := &ClaimLinkRecovered{
+		SDK:             sdk,
+		LinkKey:         claimLink.LinkKey,
+		TransferId:      claimLink.TransferId,
+		Sender:          claimLink.Sender,
+		Token:           claimLink.Token,
+		EscrowAddress:   claimLink.EscrowAddress,
+		SenderSignature: []byte{5, 6},
+	}
+	data, err = json.Marshal(recovered)
+	if err != nil {
+		t.Fatal(err)
+	}
+	if bytes.Contains(data, []byte(`"linkKey":"0x`)) {
+		t.Fatalf("link key is marshaled: %s", data)
+	}
+	var decodedRecovered ClaimLinkRecovered
+	if err = json.Unmarshal(data, &decodedRecovered); err != nil {
+		t.Fatal(err)
+	}
+	if decodedRecovered.LinkKey != nil || !bytes.Equal(decodedRecovered.SenderSignature, recovered.SenderSignature) {
+		t.Fatalf("unexpected decoded link %+v", decodedRecovered)
+	}
+}
@@ -0,0 +1,130 @@
+package linkdrop
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"github.com/LinkdropHQ/linkdrop-go-sdk/helpers"
+	"github.com/LinkdropHQ/linkdrop-go-sdk/types"
+	"github.com/ethereum/go-ethereum/accounts/abi/bind"
+	"github.com/ethereum/go-ethereum/common"
+	gethtypes "github.com/ethereum/go-ethereum/core/types"
+	"math/big"
+	"time"
+)
+
+// linkState is the status and the operations of ClaimLink and ClaimLinkRecovered
+type linkState struct {
+	status     *types.ClaimLinkStatus
+	operations *[]types.ClaimLinkOperation
+	persist    func(ctx context.Context) error
+}
+
+// send sends the escrow transaction of the link with sender and tracks it as the operation.
+// If sender implements types.ReceiptWaiter, the link gets finalStatus after the transaction is mined,
+// the reverted transaction returns ErrTransactionFailed and the link gets its previous status back.
+func (ls *linkState) send(
+	ctx context.Context,
+	sender types.TransactionSender,
+	params *types.TransactionParams,
+	operationType string,
+	receiver common.Address,
+	pendingStatus types.ClaimLinkStatus,
+	finalStatus types.ClaimLinkStatus,
+) (txHash common.Hash, err error) {
+	if sender == nil {
+		return txHash, errors.New("sender is required")
+	}
+	transaction, err := sender.SendTransaction(ctx, big.NewInt(int64(params.ChainId)), params.To, params.Value, params.Data)
+	if err != nil {
+		return
+	}
+	txHash = transaction.Hash
+	previousStatus := *ls.status
+	ls.addOperation(operationType, receiver, txHash)
+	*ls.status = pendingStatus
+	err = ls.persist(ctx)
+	if err != nil {
+		return
+	}
+	waiter, ok := sender.(types.ReceiptWaiter)
+	if !ok {
+		return
+	}
+	receipt, err := waiter.WaitForReceipt(ctx, big.NewInt(int64(params.ChainId)), txHash)
+	if err != nil {
+		return txHash, fmt.Errorf("failed to wait for %s: %w", operationType, err)
+	}
+	if receipt.TxHash != txHash {
+		// the transaction was replaced with the one paying higher fees
+		ls.replaceOperationTxHash(operationType, txHash, receipt.TxHash)
+		txHash = receipt.TxHash
+	}
+	if receipt.Status != gethtypes.ReceiptStatusSuccessful {
+		*ls.status = previousStatus
+		ls.setOperationStatus(operationType, txHash, types.LinkOperationStatusError)
+		err = ls.persist(ctx)
+		if err != nil {
+			return
+		}
+		return txHash, fmt.Errorf("%w: %s %s reverted", ErrTransactionFailed, operationType, txHash)
+	}
+	*ls.status = finalStatus
+	ls.setOperationStatus(operationType, txHash, types.LinkOperationStatusCompleted)
+	return txHash, ls.persist(ctx)
+}
+
+func (ls *linkState) replaceOperationTxHash(operationType string, txHash common.Hash, replacement common.Hash) {
+	operations := *ls.operations
+	for i := range operations {
+		if operations[i].Type == operationType && txHashEqual(operations[i].TxHash, &txHash) {
+			operations[i].TxHash = &replacement
+		}
+	}
+}
+
+func (ls *linkState) setOperationStatus(operationType string, txHash common.Hash, status types.ClaimLinkOperationStatus) {
+	operations := *ls.operations
+	for i := range operations {
+		if operations[i].Type == operationType && txHashEqual(operations[i].TxHash, &txHash) {
+			operations[i].Status = status
+		}
+	}
+}
+
+func (ls *linkState) addOperation(operationType string, receiver common.Address, txHash common.Hash) {
+	*ls.operations = append(*ls.operations, types.ClaimLinkOperation{
+		Type:      operationType,
+		Timestamp: time.Now().UTC().Format(time.RFC3339),
+		Status:    types.LinkOperationStatusPending,
+		Receiver:  receiver,
+		TxHash:    &txHash,
+	})
+}
+
+// checkRelayer returns ErrNotRelayer if the account is not the relayer of the escrow
+func checkRelayer(
+	ctx context.Context,
+	sdk *SDK,
+	caller bind.ContractCaller,
+	escrowAddress common.Address,
+	tokenType types.TokenType,
+	account common.Address,
+) error {
+	escrowVersion, err := sdk.EscrowVersion(escrowAddress)
+	if err != nil {
+		return err
+	}
+	outputs, err := helpers.CallContract(ctx, caller, escrowVersion.Abi(tokenType), escrowAddress, "relayers", account)
+	if err != nil {
+		return fmt.Errorf("failed to check relayer: %w", err)
+	}
+	isRelayer, ok := outputs[0].(bool)
+	if !ok {
+		return errors.New("unexpected relayers output")
+	}
+	if !isRelayer {
+		return fmt.Errorf("%w: %s", ErrNotRelayer, account)
+	}
+	return nil
+}
@@ -0,0 +1,81 @@
+package linkdrop
+
+import (
+	"context"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"github.com/LinkdropHQ/linkdrop-go-sdk/store"
+	"github.com/LinkdropHQ/linkdrop-go-sdk/types"
+	"github.com/ethereum/go-ethereum/common"
+)
+
+// LinkStore returns the store provided with WithLinkStore or nil
+func (sdk *SDK) LinkStore() store.LinkStore {
+	return sdk.config.linkStore
+}
+
+// StoredClaimLink restores the ClaimLink saved to the LinkStore
+func (sdk *SDK) StoredClaimLink(ctx context.Context, transferId common.Address) (claimLink *ClaimLink, err error) {
+	if sdk.config.linkStore == nil {
+		return nil, errors.New("link store is not configured, see WithLinkStore")
+	}
+	record, err := sdk.config.linkStore.Get(ctx, transferId)
+	if err != nil {
+		return
+	}
+	var snapshot ClaimLinkSnapshot
+	err = json.Unmarshal(record.Snapshot, &snapshot)
+	if err != nil {
+		return
+	}
+	// the status might have been updated without rewriting the snapshot
+	snapshot.Status = record.Status
+	return sdk.RestoreClaimLink(&snapshot, sdk.config.snapshotOptions...)
+}
+
+// persist saves the claim link to the LinkStore if it's configured
+func (cl *ClaimLink) persist(ctx context.Context) error {
+	linkStore := cl.SDK.config.linkStore
+	if linkStore == nil {
+		return nil
+	}
+	snapshot, err := cl.Snapshot(cl.SDK.config.snapshotOptions...)
+	if err != nil {
+		return fmt.Errorf("failed to persist claim link: %w", err)
+	}
+	data, err := json.Marshal(snapshot)
+	if err != nil {
+		return fmt.Errorf("failed to persist claim link: %w", err)
+	}
+	err = linkStore.Put(ctx, &store.Record{
+		TransferId: cl.TransferId,
+		ChainId:    cl.Token.ChainId,
+		Sender:     cl.Sender,
+		Token:      cl.Token.Address,
+		TokenType:  cl.Token.Type,
+		Amount:     cl.Amount,
+		Status:     cl.Status,
+		Snapshot:   data,
+	})
+	if err != nil {
+		return fmt.Errorf("failed to persist claim link: %w", err)
+	}
+	return nil
+}
+
+// persistStatus updates the stored claim link status, the link is saved completely if it's not stored yet
+func (cl *ClaimLink) persistStatus(ctx context.Context, status types.ClaimLinkStatus) error {
+	linkStore := cl.SDK.config.linkStore
+	if linkStore == nil {
+		return nil
+	}
+	err := linkStore.UpdateStatus(ctx, cl.TransferId, status)
+	if errors.Is(err, store.ErrNotFound) {
+		return cl.persist(ctx)
+	}
+	if err != nil {
+		return fmt.Errorf("failed to persist claim link status: %w", err)
+	}
+	return nil
+}
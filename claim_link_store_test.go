@@ -0,0 +1,56 @@
+package linkdrop
+
+import (
+	"bytes"
+	"context"
+	"encoding/hex"
+	"github.com/LinkdropHQ/linkdrop-go-sdk/keywrap"
+	"github.com/LinkdropHQ/linkdrop-go-sdk/store"
+	gethcrypto "github.com/ethereum/go-ethereum/crypto"
+	"net/http"
+	"testing"
+)
+
+func TestClaimLinkPersist(t *testing.T) {
+	keyWrapper, err := keywrap.NewAESGCM("kek-1", [32]byte{1})
+	if err != nil {
+		t.Fatal(err)
+	}
+	tests := []struct {
+		name        string
+		opts        []SnapshotOption
+		wantLinkKey bool
+	}{
+		{name: "default"},
+		{name: "wrapped link key", opts: []SnapshotOption{SnapshotWithKeyWrapper(keyWrapper)}, wantLinkKey: true},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			linkStore := store.NewMemory()
+			sdk := newTestSDK(t, func(w http.ResponseWriter, r *http.Request) {
+				t.Fatalf("unexpected request %s", r.URL)
+			}, WithLinkStore(linkStore, tt.opts...))
+			claimLink := newSnapshotClaimLink(t, sdk)
+			ctx := context.Background()
+			if err := claimLink.persist(ctx); err != nil {
+				t.Fatal(err)
+			}
+
+			record, err := linkStore.Get(ctx, claimLink.TransferId)
+			if err != nil {
+				t.Fatal(err)
+			}
+			if bytes.Contains(record.Snapshot, []byte(hex.EncodeToString(gethcrypto.FromECDSA(claimLink.LinkKey)))) {
+				t.Fatalf("plain link key is stored: %s", record.Snapshot)
+			}
+			stored, err := sdk.StoredClaimLink(ctx, claimLink.TransferId)
+			if err != nil {
+				t.Fatal(err)
+			}
+			if tt.wantLinkKey != (stored.LinkKey != nil) ||
+				(stored.LinkKey != nil && !stored.LinkKey.Equal(claimLink.LinkKey)) {
+				t.Fatalf("unexpected stored link key %v", stored.LinkKey)
+			}
+		})
+	}
+}
@@ -0,0 +1,513 @@
+package linkdrop
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"github.com/LinkdropHQ/linkdrop-go-sdk/constants"
+	"github.com/LinkdropHQ/linkdrop-go-sdk/crypto"
+	"github.com/LinkdropHQ/linkdrop-go-sdk/store"
+	"github.com/LinkdropHQ/linkdrop-go-sdk/types"
+	"github.com/ethereum/go-ethereum"
+	"github.com/ethereum/go-ethereum/common"
+	gethtypes "github.com/ethereum/go-ethereum/core/types"
+	"github.com/ethereum/go-ethereum/signer/core/apitypes"
+	"math/big"
+	"net/http"
+	"strings"
+	"testing"
+	"time"
+)
+
+// stubSender returns txHash for the sent transactions
+type stubSender struct {
+	txHash common.Hash
+	sent   int
+}
+
+func (s *stubSender) SendTransaction(ctx context.Context, chainId *big.Int, to common.Address, value *big.Int, data []byte) (*types.Transaction, error) {
+	s.sent++
+	return &types.Transaction{Hash: s.txHash, Type: types.TransactionTypeTx}, nil
+}
+
+// stubWaiter returns receipt of the sent transactions
+type stubWaiter struct {
+	stubSender
+	receipt *gethtypes.Receipt
+}
+
+func (s *stubWaiter) WaitForReceipt(ctx context.Context, chainId *big.Int, txHash common.Hash) (*gethtypes.Receipt, error) {
+	return s.receipt, nil
+}
+
+// stubCaller returns output for all contract calls
+type stubCaller struct {
+	output []byte
+}
+
+func (s *stubCaller) CodeAt(ctx context.Context, contract common.Address, blockNumber *big.Int) ([]byte, error) {
+	return []byte{1}, nil
+}
+
+func (s *stubCaller) CallContract(ctx context.Context, call ethereum.CallMsg, blockNumber *big.Int) ([]byte, error) {
+	return s.output, nil
+}
+
+// newDepositedClaimLink returns the deposited ERC20 link on Base
+func newDepositedClaimLink(t *testing.T, sdk *SDK) *ClaimLink {
+	t.Helper()
+	token := types.Token{
+		Type:    types.TokenTypeERC20,
+		ChainId: types.ChainIdBase,
+		Address: common.HexToAddress("0x833589fCD6eDb6E08f4c7C32D4f71b54bdA02913"),
+	}
+	escrowAddress, err := sdk.config.chains.EscrowAddressByToken(token)
+	if err != nil {
+		t.Fatal(err)
+	}
+	return &ClaimLink{
+		SDK:           sdk,
+		TransferId:    common.HexToAddress("0x0000000000000000000000000000000000000003"),
+		Token:         token,
+		Sender:        common.HexToAddress("0x0000000000000000000000000000000000000004"),
+		EscrowAddress: escrowAddress,
+		Amount:        big.NewInt(1000000),
+		Expiration:    time.Now().Add(-time.Hour).Unix(),
+		Status:        types.ClaimLinkStatusDeposited,
+	}
+}
+
+func TestClaimLinkCancel(t *testing.T) {
+	txHash := common.HexToHash("0x01")
+	replacement := common.HexToHash("0x02")
+	tests := []struct {
+		name          string
+		sender        types.TransactionSender
+		wantErr       error
+		wantTxHash    common.Hash
+		wantStatus    types.ClaimLinkStatus
+		wantOperation types.ClaimLinkOperationStatus
+	}{
+		{
+			name:          "without receipt waiter",
+			sender:        &stubSender{txHash: txHash},
+			wantTxHash:    txHash,
+			wantStatus:    types.ClaimLinkStatusCancelling,
+			wantOperation: types.LinkOperationStatusPending,
+		},
+		{
+			name: "mined",
+			sender: &stubWaiter{
+				stubSender: stubSender{txHash: txHash},
+				receipt:    &gethtypes.Receipt{TxHash: txHash, Status: gethtypes.ReceiptStatusSuccessful},
+			},
+			wantTxHash:    txHash,
+			wantStatus:    types.ClaimLinkStatusCancelled,
+			wantOperation: types.LinkOperationStatusCompleted,
+		},
+		{
+			name: "replaced",
+			sender: &stubWaiter{
+				stubSender: stubSender{txHash: txHash},
+				receipt:    &gethtypes.Receipt{TxHash: replacement, Status: gethtypes.ReceiptStatusSuccessful},
+			},
+			wantTxHash:    replacement,
+			wantStatus:    types.ClaimLinkStatusCancelled,
+			wantOperation: types.LinkOperationStatusCompleted,
+		},
+		{
+			name: "reverted",
+			sender: &stubWaiter{
+				stubSender: stubSender{txHash: txHash},
+				receipt:    &gethtypes.Receipt{TxHash: txHash, Status: gethtypes.ReceiptStatusFailed},
+			},
+			wantErr:       ErrTransactionFailed,
+			wantTxHash:    txHash,
+			wantStatus:    types.ClaimLinkStatusDeposited,
+			wantOperation: types.LinkOperationStatusError,
+		},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			sdk := newTestSDK(t, func(w http.ResponseWriter, r *http.Request) {
+				t.Fatalf("unexpected request %s", r.URL)
+			})
+			claimLink := newDepositedClaimLink(t, sdk)
+			txHash, err := claimLink.Cancel(context.Background(), tt.sender)
+			if !errors.Is(err, tt.wantErr) {
+				t.Fatalf("expected error %v, got %v", tt.wantErr, err)
+			}
+			if txHash != tt.wantTxHash || claimLink.Status != tt.wantStatus {
+				t.Fatalf("got %s with status %s, want %s with status %s", txHash, claimLink.Status, tt.wantTxHash, tt.wantStatus)
+			}
+			if len(claimLink.Operations) != 1 || claimLink.Operations[0].Type != "cancel" ||
+				*claimLink.Operations[0].TxHash != tt.wantTxHash || claimLink.Operations[0].Status != tt.wantOperation {
+				t.Fatalf("unexpected operations %+v", claimLink.Operations)
+			}
+		})
+	}
+}
+
+func TestClaimLinkRefund(t *testing.T) {
+	sdk := newTestSDK(t, func(w http.ResponseWriter, r *http.Request) {
+		t.Fatalf("unexpected request %s", r.URL)
+	})
+	claimLink := newDepositedClaimLink(t, sdk)
+	claimLink.Expiration = time.Now().Add(time.Hour).Unix()
+	sender := &stubSender{txHash: common.HexToHash("0x01")}
+	_, err := claimLink.Refund(context.Background(), sender)
+	if !errors.Is(err, ErrLinkNotExpired) || sender.sent != 0 {
+		t.Fatalf("expected ErrLinkNotExpired without transactions, got %v", err)
+	}
+
+	claimLink.Expiration = time.Now().Add(-time.Hour).Unix()
+	_, err = claimLink.Refund(context.Background(), sender)
+	if err != nil {
+		t.Fatal(err)
+	}
+	if claimLink.Status != types.ClaimLinkStatusRefunding {
+		t.Fatalf("expected refunding, got %s", claimLink.Status)
+	}
+	_, err = claimLink.Cancel(context.Background(), sender)
+	if err == nil || sender.sent != 1 {
+		t.Fatal("expected the refunding link not to be cancelled")
+	}
+}
+
+func TestClaimLinkCheckRelayer(t *testing.T) {
+	sdk := newTestSDK(t, func(w http.ResponseWriter, r *http.Request) {
+		t.Fatalf("unexpected request %s", r.URL)
+	})
+	claimLink := newDepositedClaimLink(t, sdk)
+	account := common.HexToAddress("0x0000000000000000000000000000000000000005")
+
+	err := claimLink.CheckRelayer(context.Background(), &stubCaller{output: common.LeftPadBytes([]byte{1}, 32)}, account)
+	if err != nil {
+		t.Fatal(err)
+	}
+	err = claimLink.CheckRelayer(context.Background(), &stubCaller{output: make([]byte, 32)}, account)
+	if !errors.Is(err, ErrNotRelayer) {
+		t.Fatalf("expected ErrNotRelayer, got %v", err)
+	}
+}
+
+func TestClaimLinkRedeemOnChain(t *testing.T) {
+	txHash := common.HexToHash("0x01")
+	receiver := common.HexToAddress("0x0000000000000000000000000000000000000006")
+	tests := []struct {
+		name          string
+		sender        types.TransactionSender
+		wantErr       error
+		wantStatus    types.ClaimLinkStatus
+		wantOperation types.ClaimLinkOperationStatus
+	}{
+		{
+			name:          "without receipt waiter",
+			sender:        &stubSender{txHash: txHash},
+			wantStatus:    types.ClaimLinkStatusRedeeming,
+			wantOperation: types.LinkOperationStatusPending,
+		},
+		{
+			name: "mined",
+			sender: &stubWaiter{
+				stubSender: stubSender{txHash: txHash},
+				receipt:    &gethtypes.Receipt{TxHash: txHash, Status: gethtypes.ReceiptStatusSuccessful},
+			},
+			wantStatus:    types.ClaimLinkStatusRedeemed,
+			wantOperation: types.LinkOperationStatusCompleted,
+		},
+		{
+			name: "reverted",
+			sender: &stubWaiter{
+				stubSender: stubSender{txHash: txHash},
+				receipt:    &gethtypes.Receipt{TxHash: txHash, Status: gethtypes.ReceiptStatusFailed},
+			},
+			wantErr:       ErrTransactionFailed,
+			wantStatus:    types.ClaimLinkStatusDeposited,
+			wantOperation: types.LinkOperationStatusError,
+		},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			linkStore := store.NewMemory()
+			sdk := newTestSDK(t, func(w http.ResponseWriter, r *http.Request) {
+				t.Fatalf("unexpected request %s", r.URL)
+			}, WithLinkStore(linkStore))
+			claimLink := newSnapshotClaimLink(t, sdk)
+			recovered := &ClaimLinkRecovered{
+				SDK:             sdk,
+				LinkKey:         claimLink.LinkKey,
+				TransferId:      claimLink.TransferId,
+				Sender:          claimLink.Sender,
+				Token:           claimLink.Token,
+				EscrowAddress:   claimLink.EscrowAddress,
+				SenderSignature: []byte{5, 6},
+				Status:          types.ClaimLinkStatusDeposited,
+			}
+			ctx := context.Background()
+
+			_, err := claimLink.RedeemOnChain(ctx, receiver, tt.sender)
+			if !errors.Is(err, tt.wantErr) {
+				t.Fatalf("expected error %v, got %v", tt.wantErr, err)
+			}
+			_, err = recovered.RedeemOnChain(ctx, receiver, tt.sender)
+			if !errors.Is(err, tt.wantErr) {
+				t.Fatalf("expected error %v, got %v", tt.wantErr, err)
+			}
+			if claimLink.Status != tt.wantStatus || recovered.Status != tt.wantStatus {
+				t.Fatalf("got statuses %s and %s, want %s", claimLink.Status, recovered.Status, tt.wantStatus)
+			}
+			for _, operations := range [][]types.ClaimLinkOperation{claimLink.Operations, recovered.Operations} {
+				if len(operations) != 1 || operations[0].Type != "redeem" || operations[0].Receiver != receiver ||
+					operations[0].Status != tt.wantOperation {
+					t.Fatalf("unexpected operations %+v", operations)
+				}
+			}
+			record, err := linkStore.Get(ctx, claimLink.TransferId)
+			if err != nil {
+				t.Fatal(err)
+			}
+			if record.Status != tt.wantStatus {
+				t.Fatalf("expected the %s status to be stored, got %s", tt.wantStatus, record.Status)
+			}
+		})
+	}
+}
+
+func TestClaimLinkRedeemOnChainRedeemed(t *testing.T) {
+	sdk := newTestSDK(t, func(w http.ResponseWriter, r *http.Request) {
+		t.Fatalf("unexpected request %s", r.URL)
+	})
+	claimLink := newSnapshotClaimLink(t, sdk)
+	claimLink.Status = types.ClaimLinkStatusRedeeming
+	sender := &stubSender{txHash: common.HexToHash("0x01")}
+	receiver := common.HexToAddress("0x0000000000000000000000000000000000000006")
+	_, err := claimLink.RedeemOnChain(context.Background(), receiver, sender)
+	if !errors.Is(err, ErrLinkAlreadyRedeemed) || sender.sent != 0 {
+		t.Fatalf("expected ErrLinkAlreadyRedeemed without transactions, got %v", err)
+	}
+	recovered := &ClaimLinkRecovered{
+		SDK:             sdk,
+		LinkKey:         claimLink.LinkKey,
+		TransferId:      claimLink.TransferId,
+		Token:           claimLink.Token,
+		EscrowAddress:   claimLink.EscrowAddress,
+		SenderSignature: []byte{5, 6},
+		Status:          types.ClaimLinkStatusRedeemed,
+	}
+	_, err = recovered.RedeemOnChain(context.Background(), receiver, sender)
+	if !errors.Is(err, ErrLinkAlreadyRedeemed) || sender.sent != 0 {
+		t.Fatalf("expected ErrLinkAlreadyRedeemed without transactions, got %v", err)
+	}
+}
+
+func TestClaimLinkNilSignerCallback(t *testing.T) {
+	var signCallback types.SignTypedDataCallback
+	var sendCallback types.SendTransactionCallback
+	var nonce [crypto.NonceLength]byte
+	receiver := common.HexToAddress("0x05")
+	tests := []struct {
+		name string
+		run  func(ctx context.Context, claimLink *ClaimLink, recovered *ClaimLinkRecovered) error
+	}{
+		{name: "add message with nil callback", run: func(ctx context.Context, cl *ClaimLink, _ *ClaimLinkRecovered) error {
+			return cl.AddMessage(ctx, "message", 12, signCallback, nonce)
+		}},
+		{name: "add message without signer", run: func(ctx context.Context, cl *ClaimLink, _ *ClaimLinkRecovered) error {
+			return cl.AddMessage(ctx, "message", 12, nil, nonce)
+		}},
+		{name: "deposit with authorization with nil callback", run: func(ctx context.Context, cl *ClaimLink, _ *ClaimLinkRecovered) error {
+			_, err := cl.DepositWithAuthorization(ctx, signCallback)
+			return err
+		}},
+		{name: "deposit with authorization without signer", run: func(ctx context.Context, cl *ClaimLink, _ *ClaimLinkRecovered) error {
+			_, err := cl.DepositWithAuthorization(ctx, nil)
+			return err
+		}},
+		{name: "deposit with nil callback", run: func(ctx context.Context, cl *ClaimLink, _ *ClaimLinkRecovered) error {
+			_, err := cl.Deposit(ctx, sendCallback)
+			return err
+		}},
+		{name: "deposit without sender", run: func(ctx context.Context, cl *ClaimLink, _ *ClaimLinkRecovered) error {
+			_, err := cl.Deposit(ctx, nil)
+			return err
+		}},
+		{name: "deposit with approval without sender", run: func(ctx context.Context, cl *ClaimLink, _ *ClaimLinkRecovered) error {
+			_, err := cl.DepositWithApproval(ctx, nil, &stubCaller{output: make([]byte, 32)})
+			return err
+		}},
+		{name: "cancel with nil callback", run: func(ctx context.Context, cl *ClaimLink, _ *ClaimLinkRecovered) error {
+			cl.Status = types.ClaimLinkStatusDeposited
+			_, err := cl.Cancel(ctx, sendCallback)
+			return err
+		}},
+		{name: "cancel without sender", run: func(ctx context.Context, cl *ClaimLink, _ *ClaimLinkRecovered) error {
+			cl.Status = types.ClaimLinkStatusDeposited
+			_, err := cl.Cancel(ctx, nil)
+			return err
+		}},
+		{name: "redeem without sender", run: func(ctx context.Context, cl *ClaimLink, _ *ClaimLinkRecovered) error {
+			cl.Status = types.ClaimLinkStatusDeposited
+			_, err := cl.RedeemOnChain(ctx, receiver, nil)
+			return err
+		}},
+		{name: "generate recovered claim URL without signer", run: func(ctx context.Context, _ *ClaimLink, clr *ClaimLinkRecovered) error {
+			_, err := clr.GenerateClaimUrl(ctx, nil, nil)
+			return err
+		}},
+		{name: "add message key without signer", run: func(ctx context.Context, _ *ClaimLink, clr *ClaimLinkRecovered) error {
+			return clr.AddMessageKey(ctx, 12, nil)
+		}},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			sdk := newTestSDK(t, func(w http.ResponseWriter, r *http.Request) {
+				t.Fatalf("unexpected request %s", r.URL)
+			})
+			claimLink := newSnapshotClaimLink(t, sdk)
+			claimLink.Status = types.ClaimLinkStatusCreated
+			recovered := &ClaimLinkRecovered{
+				SDK:           sdk,
+				TransferId:    claimLink.TransferId,
+				Token:         claimLink.Token,
+				Sender:        claimLink.Sender,
+				EscrowAddress: claimLink.EscrowAddress,
+			}
+
+			err := tt.run(context.Background(), claimLink, recovered)
+			if err == nil || !(strings.HasSuffix(err.Error(), "is required") || strings.HasSuffix(err.Error(), "is not set")) {
+				t.Fatalf("expected the missing signer error, got %v", err)
+			}
+		})
+	}
+}
+
+// TestClaimLinkGetAuthorizationTypedData checks the typed data hashes against the EIP-712 hashes computed independently
+func TestClaimLinkGetAuthorizationTypedData(t *testing.T) {
+	tests := []struct {
+		name     string
+		token    types.Token
+		wantHash common.Hash
+		wantErr  bool
+	}{
+		{
+			name:     "USDC on Base with version domain",
+			token:    types.Token{Type: types.TokenTypeERC20, ChainId: types.ChainIdBase, Address: constants.TAUsdcBase},
+			wantHash: common.HexToHash("0xdb7680a4aaa5ed4fa9a986fa367d148cccc19d8637096354363ffb7d93be488e"),
+		},
+		{
+			name:     "bridged USDC on Polygon with salt domain",
+			token:    types.Token{Type: types.TokenTypeERC20, ChainId: types.ChainIdPolygon, Address: constants.TAUsdcBridgedPolygon},
+			wantHash: common.HexToHash("0x6d51e131b993c18162d60fa1992a23deedbf886958a487dcc930c03ad009ccad"),
+		},
+		{
+			name:    "token without authorization",
+			token:   types.Token{Type: types.TokenTypeERC20, ChainId: types.ChainIdBase, Address: common.HexToAddress("0x01")},
+			wantErr: true,
+		},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			sdk := newTestSDK(t, func(w http.ResponseWriter, r *http.Request) {
+				t.Fatalf("unexpected request %s", r.URL)
+			})
+			claimLink := newApprovalClaimLink(t, sdk)
+			claimLink.Token = tt.token
+			claimLink.Expiration = 1700000000
+			escrowAddress, err := sdk.config.chains.EscrowAddressByToken(tt.token)
+			if err != nil {
+				t.Fatal(err)
+			}
+			claimLink.EscrowAddress = escrowAddress
+
+			typedData, err := claimLink.GetAuthorizationTypedData()
+			if tt.wantErr {
+				if err == nil {
+					t.Fatal("expected error")
+				}
+				return
+			}
+			if err != nil {
+				t.Fatal(err)
+			}
+			hash, _, err := apitypes.TypedDataAndHash(*typedData)
+			if err != nil {
+				t.Fatal(err)
+			}
+			if common.BytesToHash(hash) != tt.wantHash {
+				t.Fatalf("got %x, want %s", hash, tt.wantHash)
+			}
+		})
+	}
+}
+
+func TestClaimLinkGetDepositParamsNFT(t *testing.T) {
+	tests := []struct {
+		name      string
+		tokenType types.TokenType
+		fee       *types.Token // fee - the fee token, the native currency if nil
+		wantValue int64
+		wantArgs  func(cl *ClaimLink) []any
+	}{
+		{
+			name:      "ERC721",
+			tokenType: types.TokenTypeERC721,
+			wantValue: 1000,
+			wantArgs: func(cl *ClaimLink) []any {
+				return []any{cl.Token.Address, cl.TransferId, big.NewInt(7), big.NewInt(1700000000), big.NewInt(1000), []byte{1, 2}, []byte{3}}
+			},
+		},
+		{
+			name:      "ERC1155",
+			tokenType: types.TokenTypeERC1155,
+			wantValue: 1000,
+			wantArgs: func(cl *ClaimLink) []any {
+				return []any{cl.Token.Address, cl.TransferId, big.NewInt(7), big.NewInt(5), big.NewInt(1700000000), big.NewInt(1000), []byte{1, 2}, []byte{3}}
+			},
+		},
+		{
+			name:      "ERC1155 with the fee in ERC20 token",
+			tokenType: types.TokenTypeERC1155,
+			fee:       &types.Token{Type: types.TokenTypeERC20, ChainId: types.ChainIdBase, Address: constants.TAUsdcBase},
+			wantArgs: func(cl *ClaimLink) []any {
+				return []any{cl.Token.Address, cl.TransferId, big.NewInt(7), big.NewInt(5), big.NewInt(1700000000), big.NewInt(1000), []byte{1, 2}, []byte{3}}
+			},
+		},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			sdk := newTestSDK(t, func(w http.ResponseWriter, r *http.Request) {
+				t.Fatalf("unexpected request %s", r.URL)
+			})
+			claimLink := newNFTClaimLink(t, sdk, tt.tokenType)
+			claimLink.Fee.Authorization = []byte{1, 2}
+			if tt.fee != nil {
+				claimLink.Fee.Token = *tt.fee
+			}
+			claimLink.Message = &types.EncryptedMessage{Data: []byte{3}}
+
+			params, err := claimLink.GetDepositParams()
+			if err != nil {
+				t.Fatal(err)
+			}
+			if params.To != claimLink.EscrowAddress || params.ChainId != types.ChainIdBase || params.Value.Int64() != tt.wantValue {
+				t.Fatalf("unexpected deposit params %+v", params)
+			}
+			method, err := constants.EscrowNFTAbi.MethodById(params.Data[:4])
+			if err != nil {
+				t.Fatal(err)
+			}
+			args, err := method.Inputs.Unpack(params.Data[4:])
+			if err != nil {
+				t.Fatal(err)
+			}
+			wantArgs := tt.wantArgs(claimLink)
+			if method.Name != "deposit"+string(tt.tokenType) {
+				t.Fatalf("unexpected method %s", method.Name)
+			}
+			if fmt.Sprint(args) != fmt.Sprint(wantArgs) {
+				t.Fatalf("got arguments %v, want %v", args, wantArgs)
+			}
+		})
+	}
+}
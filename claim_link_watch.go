@@ -0,0 +1,175 @@
+package linkdrop
+
+import (
+	"context"
+	"fmt"
+	"github.com/LinkdropHQ/linkdrop-go-sdk/types"
+	"github.com/ethereum/go-ethereum/common"
+	"slices"
+	"time"
+)
+
+// ClaimLinkUpdate is a change of the claim link state observed by ClaimLink.Watch
+type ClaimLinkUpdate struct {
+	Status    types.ClaimLinkStatus
+	Operation *types.ClaimLinkOperation // Operation - a new or updated operation, nil if only the status has changed
+	Err       error                     // Err - an error of the status request, polling continues only after the transient errors
+}
+
+// IsTerminalStatus reports whether the claim link can't change its status anymore
+func IsTerminalStatus(status types.ClaimLinkStatus) bool {
+	switch status {
+	case types.ClaimLinkStatusRedeemed,
+		types.ClaimLinkStatusRefunded,
+		types.ClaimLinkStatusCancelled,
+		types.ClaimLinkStatusDropped,
+		types.ClaimLinkStatusError:
+		return true
+	}
+	return false
+}
+
+// Watch polls the claim link status and emits every status change and ClaimLinkOperation transition.
+// The channel is closed once the link reaches a terminal status, ctx is done or the status request fails
+// with an error that won't go away, e.g. ErrLinkNotFound, ErrUnauthorized or ErrUnsupportedChain.
+// NOTE: Watch doesn't modify the ClaimLink, use GetStatus or WaitForStatus to update it
+func (cl *ClaimLink) Watch(ctx context.Context) <-chan ClaimLinkUpdate {
+	updates := make(chan ClaimLinkUpdate)
+	pollingConfig := cl.SDK.config.pollingConfig
+	chainId, transferId := cl.Token.ChainId, cl.TransferId
+	status, operations := cl.Status, slices.Clone(cl.Operations)
+
+	go func() {
+		defer close(updates)
+		emit := func(update ClaimLinkUpdate) bool {
+			select {
+			case updates <- update:
+				return true
+			case <-ctx.Done():
+				return false
+			}
+		}
+
+		interval := pollingConfig.InitialInterval
+		for {
+			changed := false
+			statusResp, err := cl.SDK.Client.GetTransferStatus(ctx, chainId, transferId)
+			if err != nil {
+				if ctx.Err() != nil || !emit(ClaimLinkUpdate{Status: status, Err: err}) || !isTransient(err) {
+					return
+				}
+			} else {
+				claimLink := statusResp.ClaimLink
+				for i := range claimLink.Operations {
+					if i < len(operations) && operationEqual(operations[i], claimLink.Operations[i]) {
+						continue
+					}
+					changed = true
+					if !emit(ClaimLinkUpdate{Status: claimLink.Status, Operation: &claimLink.Operations[i]}) {
+						return
+					}
+				}
+				if claimLink.Status != status {
+					changed = true
+					if !emit(ClaimLinkUpdate{Status: claimLink.Status}) {
+						return
+					}
+				}
+				status, operations = claimLink.Status, claimLink.Operations
+				if IsTerminalStatus(status) {
+					return
+				}
+			}
+
+			if changed {
+				interval = pollingConfig.InitialInterval
+			}
+			timer := time.NewTimer(interval)
+			select {
+			case <-ctx.Done():
+				timer.Stop()
+				return
+			case <-timer.C:
+			}
+			interval = time.Duration(float64(interval) * max(pollingConfig.Multiplier, 1))
+			if pollingConfig.MaxInterval > 0 && interval > pollingConfig.MaxInterval {
+				interval = pollingConfig.MaxInterval
+			}
+		}
+	}()
+	return updates
+}
+
+// WaitForStatus blocks until the claim link reaches one of the target statuses and updates Status and Operations.
+// The changes are saved to the LinkStore if it's configured.
+// An error is returned if the link reaches another terminal status, ctx is done or the status request can't succeed, see Watch.
+func (cl *ClaimLink) WaitForStatus(
+	ctx context.Context,
+	target ...types.ClaimLinkStatus,
+) (status types.ClaimLinkStatus, err error) {
+	if len(target) == 0 {
+		return cl.Status, fmt.Errorf("target status is required")
+	}
+	status = cl.Status
+	if slices.Contains(target, status) {
+		return
+	}
+	ctx, cancel := context.WithCancel(ctx)
+	defer cancel()
+
+	var lastErr error
+	for update := range cl.Watch(ctx) {
+		if update.Err != nil {
+			if !isTransient(update.Err) {
+				return status, update.Err
+			}
+			lastErr = update.Err
+			continue
+		}
+		if update.Operation != nil {
+			cl.setOperation(*update.Operation)
+		}
+		status = update.Status
+		cl.Status = status
+		err = cl.persist(ctx)
+		if err != nil {
+			return
+		}
+		if slices.Contains(target, status) {
+			return status, nil
+		}
+	}
+	if ctx.Err() != nil {
+		if lastErr != nil {
+			return status, fmt.Errorf("%w (last error: %v)", ctx.Err(), lastErr)
+		}
+		return status, ctx.Err()
+	}
+	return status, fmt.Errorf("claim link reached terminal status %s", status)
+}
+
+// setOperation adds the operation or replaces the known one with the same type and transaction
+func (cl *ClaimLink) setOperation(operation types.ClaimLinkOperation) {
+	for i := range cl.Operations {
+		if cl.Operations[i].Type == operation.Type && txHashEqual(cl.Operations[i].TxHash, operation.TxHash) {
+			cl.Operations[i] = operation
+			return
+		}
+	}
+	cl.Operations = append(cl.Operations, operation)
+}
+
+func operationEqual(a, b types.ClaimLinkOperation) bool {
+	return a.Type == b.Type &&
+		a.Status == b.Status &&
+		a.Receiver == b.Receiver &&
+		a.Timestamp == b.Timestamp &&
+		txHashEqual(a.TxHash, b.TxHash)
+}
+
+func txHashEqual(a, b *common.Hash) bool {
+	if a == nil || b == nil {
+		return a == b
+	}
+	return *a == *b
+}
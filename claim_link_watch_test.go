@@ -0,0 +1,107 @@
+package linkdrop
+
+import (
+	"context"
+	"errors"
+	"github.com/LinkdropHQ/linkdrop-go-sdk/store"
+	"github.com/LinkdropHQ/linkdrop-go-sdk/types"
+	"net/http"
+	"sync/atomic"
+	"testing"
+	"time"
+)
+
+var testPollingConfig = PollingConfig{
+	InitialInterval: time.Millisecond,
+	MaxInterval:     5 * time.Millisecond,
+	Multiplier:      2,
+}
+
+func TestClaimLinkWaitForStatus(t *testing.T) {
+	var requests atomic.Int32
+	sdk := newTestSDK(t, func(w http.ResponseWriter, r *http.Request) {
+		switch requests.Add(1) {
+		case 1:
+			w.WriteHeader(http.StatusServiceUnavailable)
+		case 2:
+			_, _ = w.Write([]byte(`{"success": true, "claim_link": {"status": "redeeming", "operations": [{"type": "redeem", "status": "pending"}]}}`))
+		default:
+			_, _ = w.Write([]byte(`{"success": true, "claim_link": {"status": "redeemed", "operations": [{"type": "redeem", "status": "completed"}]}}`))
+		}
+	}, WithPollingConfig(testPollingConfig), WithRetryPolicy(RetryPolicy{MaxAttempts: 1}), WithLinkStore(store.NewMemory()))
+	claimLink := newDepositedClaimLink(t, sdk)
+
+	ctx, cancel := context.WithTimeout(context.Background(), 5*time.Second)
+	defer cancel()
+	status, err := claimLink.WaitForStatus(ctx, types.ClaimLinkStatusRedeemed)
+	if err != nil {
+		t.Fatal(err)
+	}
+	if status != types.ClaimLinkStatusRedeemed || claimLink.Status != types.ClaimLinkStatusRedeemed {
+		t.Fatalf("unexpected status %s", status)
+	}
+	if len(claimLink.Operations) != 1 || claimLink.Operations[0].Status != types.LinkOperationStatusCompleted {
+		t.Fatalf("unexpected operations %+v", claimLink.Operations)
+	}
+	record, err := sdk.config.linkStore.Get(ctx, claimLink.TransferId)
+	if err != nil {
+		t.Fatal(err)
+	}
+	if record.Status != types.ClaimLinkStatusRedeemed {
+		t.Fatalf("expected the redeemed status to be stored, got %s", record.Status)
+	}
+}
+
+func TestClaimLinkWaitForStatusStops(t *testing.T) {
+	tests := []struct {
+		name    string
+		status  int
+		wantErr error
+	}{
+		{name: "link not found", status: http.StatusNotFound, wantErr: ErrLinkNotFound},
+		{name: "unauthorized", status: http.StatusUnauthorized, wantErr: ErrUnauthorized},
+		{name: "bad request", status: http.StatusBadRequest},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			var requests atomic.Int32
+			sdk := newTestSDK(t, func(w http.ResponseWriter, r *http.Request) {
+				requests.Add(1)
+				w.WriteHeader(tt.status)
+			}, WithPollingConfig(testPollingConfig), WithRetryPolicy(RetryPolicy{MaxAttempts: 1}))
+			claimLink := newDepositedClaimLink(t, sdk)
+
+			ctx, cancel := context.WithTimeout(context.Background(), 5*time.Second)
+			defer cancel()
+			_, err := claimLink.WaitForStatus(ctx, types.ClaimLinkStatusRedeemed)
+			var apiErr *Error
+			if !errors.As(err, &apiErr) || apiErr.StatusCode != tt.status {
+				t.Fatalf("expected API error with status %d, got %v", tt.status, err)
+			}
+			if tt.wantErr != nil && !errors.Is(err, tt.wantErr) {
+				t.Fatalf("expected %v, got %v", tt.wantErr, err)
+			}
+			if ctx.Err() != nil || requests.Load() != 1 {
+				t.Fatalf("expected a single request, got %d", requests.Load())
+			}
+		})
+	}
+}
+
+func TestClaimLinkWatchStopsOnUnsupportedChain(t *testing.T) {
+	sdk := newTestSDK(t, func(w http.ResponseWriter, r *http.Request) {
+		t.Fatalf("unexpected request %s", r.URL)
+	}, WithPollingConfig(testPollingConfig))
+	claimLink := newDepositedClaimLink(t, sdk)
+	claimLink.Token.ChainId = 1234567
+
+	ctx, cancel := context.WithTimeout(context.Background(), 5*time.Second)
+	defer cancel()
+	var updates []ClaimLinkUpdate
+	for update := range claimLink.Watch(ctx) {
+		updates = append(updates, update)
+	}
+	if len(updates) != 1 || !errors.Is(updates[0].Err, ErrUnsupportedChain) || ctx.Err() != nil {
+		t.Fatalf("expected a single ErrUnsupportedChain update, got %+v", updates)
+	}
+}
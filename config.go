@@ -1,26 +1,59 @@
 package linkdrop
 
+import (
+	"github.com/LinkdropHQ/linkdrop-go-sdk/store"
+	"github.com/LinkdropHQ/linkdrop-go-sdk/types"
+	"github.com/ethereum/go-ethereum/accounts/abi/bind"
+	"net/http"
+	"time"
+)
+
 type MessageConfig struct {
 	MinEncryptionKeyLength uint16
 	MaxEncryptionKeyLength uint16
 	MaxTextLength          int64
 }
 
+// PollingConfig configures the status polling of ClaimLink.Watch and ClaimLink.WaitForStatus
+// The interval grows by Multiplier after every poll without changes and is reset once the status changes
+type PollingConfig struct {
+	InitialInterval time.Duration
+	MaxInterval     time.Duration
+	Multiplier      float64
+}
+
 // ClientConfig is a configuration of the API Client
 type ClientConfig struct {
-	apiKey string
-	apiURL string
+	apiKey      string
+	apiURL      string
+	httpClient  *http.Client
+	middlewares []Middleware
+	retryPolicy RetryPolicy
+	chains      *types.ChainRegistry
 }
 
 type SDKConfig struct {
 	baseURL       string
 	messageConfig MessageConfig
+	pollingConfig PollingConfig
 	environment   string
+
+	chains         *types.ChainRegistry
+	escrowVersions *EscrowVersionRegistry
+
+	receiptReaders map[types.ChainId]ReceiptReader
+	tokenCallers   map[types.ChainId]bind.ContractCaller
+
+	dashboardApi DashboardApi
+
+	linkStore       store.LinkStore
+	snapshotOptions []SnapshotOption // snapshotOptions - applied to the snapshots saved to linkStore
 }
 
 // applyDefaults be run by SDK before any other options
 func (sdkc *SDKConfig) applyDefaults() {
 	sdkc.applyDefaultMessageConfig()
+	sdkc.applyDefaultPollingConfig()
 	sdkc.environment = "development"
 }
 
@@ -31,3 +64,11 @@ func (sdkc *SDKConfig) applyDefaultMessageConfig() {
 		MaxTextLength:          140,
 	}
 }
+
+func (sdkc *SDKConfig) applyDefaultPollingConfig() {
+	sdkc.pollingConfig = PollingConfig{
+		InitialInterval: 2 * time.Second,
+		MaxInterval:     30 * time.Second,
+		Multiplier:      1.5,
+	}
+}
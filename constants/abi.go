@@ -5,3 +5,8 @@ import (
 )
 
 var EscrowNFTAbi, EscrowTokenAbi abi.ABI
+
+var ERC20Abi, ERC721Abi, ERC1155Abi abi.ABI
+
+// EntryPointAbi and SimpleAccountAbi are the parts of ERC-4337 EntryPoint v0.7 and SimpleAccount used by the userop package
+var EntryPointAbi, SimpleAccountAbi abi.ABI
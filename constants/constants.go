@@ -1,11 +1,16 @@
 package constants
 
-import "github.com/ethereum/go-ethereum/common"
+import (
+	"github.com/ethereum/go-ethereum/common"
+	"time"
+)
 
 const (
 	ApiURL             = "https://escrow-api.linkdrop.io/v3"
 	DashboardApiUrl    = "https://escrow-api.linkdrop.io/dashboard"
 	DevDashboardApiUrl = "https://escrow-api.linkdrop.io/staging"
+
+	DefaultRequestTimeout = 30 * time.Second
 )
 
 var (
@@ -14,6 +19,7 @@ var (
 	EscrowNFTContractAddress    = common.HexToAddress("0x5fc1316119a1b7cec52a2984c62764343dca70c9")
 	CbwEscrowContractAddress    = common.HexToAddress("0x5badb0143f69015c5c86cbd9373474a9c8ab713b")
 	CbwEscrowNFTContractAddress = common.HexToAddress("0x3c74782de03c0402d207fe41307fe50fe9b6b5c7")
+	EntryPointV07Address        = common.HexToAddress("0x0000000071727De22E5E9d8BAf0edAc6f37da032")
 )
 
 var SupportedStableCoins = map[common.Address]Selector{
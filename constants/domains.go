@@ -1,6 +1,7 @@
 package constants
 
 import (
+	"github.com/ethereum/go-ethereum/common"
 	"github.com/ethereum/go-ethereum/common/math"
 	"github.com/ethereum/go-ethereum/signer/core/apitypes"
 )
@@ -63,3 +64,15 @@ var (
 		VerifyingContract: TAUsdcPolygon.Hex(),
 	}
 )
+
+// StableCoinDomains maps supported stable coins to their EIP-712 domains
+var StableCoinDomains = map[common.Address]apitypes.TypedDataDomain{
+	TAUsdcBase:           DomainUsdcBase,
+	TAEurcBase:           DomainEurcBase,
+	TACbBtcBase:          DomainCbBtcBase,
+	TAUsdcBridgedPolygon: DomainUsdcBridgedPolygon,
+	TAUsdcPolygon:        DomainUsdcPolygon,
+	TAUsdcArbitrum:       DomainUsdcArbitrum,
+	TAUsdcOptimism:       DomainUsdcOptimism,
+	TAUsdcAvalanche:      DomainUsdcAvalanche,
+}
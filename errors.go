@@ -1,13 +1,158 @@
 package linkdrop
 
-import "fmt"
+import (
+	"encoding/json"
+	"errors"
+	"fmt"
+	"github.com/LinkdropHQ/linkdrop-go-sdk/helpers"
+	"github.com/LinkdropHQ/linkdrop-go-sdk/types"
+	"net/http"
+	"strings"
+)
 
+// Sentinel errors. Use errors.Is to check the cause of an error returned by the SDK.
+var (
+	ErrLinkAlreadyRedeemed = errors.New("link is already redeemed")
+	ErrLinkExpired         = errors.New("link is expired")
+	ErrLinkNotExpired      = errors.New("link is not expired yet")
+	ErrLinkNotFound        = errors.New("link is not found")
+	ErrInsufficientAmount  = errors.New("insufficient amount")
+	ErrUnsupportedChain    = types.ErrUnsupportedChain
+	ErrInvalidToken        = types.ErrInvalidToken
+	ErrInvalidSignature    = errors.New("invalid signature")
+	ErrUnauthorized        = errors.New("unauthorized")
+	ErrRateLimited         = errors.New("rate limited")
+	ErrTransactionFailed   = errors.New("transaction failed")
+	ErrNotRelayer          = errors.New("account is not the escrow relayer")
+	ErrDashboardApiNotSet  = errors.New("dashboard API is not set, see WithDashboardApi")
+	ErrReorgTooDeep        = errors.New("chain reorganization is deeper than the checkpoint history")
+	ErrMessageNotLoaded    = errors.New("sender message is not loaded")
+	ErrApprovalRequired    = errors.New("approval is required, send the GetApprovalParams transactions first or use a sender implementing types.ReceiptWaiter")
+)
+
+// Error describes an error returned by the Linkdrop API
 type Error struct {
-	Code    string
-	Message string
-	Err     error
+	StatusCode int    // StatusCode - HTTP status code of the API response
+	Code       string // Code - API error code
+	Message    string // Message - human-readable error description
+	RequestId  string // RequestId - API request identifier, useful for support requests
+	Err        error  // Err - the underlying sentinel error if the cause is known
 }
 
 func (e *Error) Error() string {
-	return fmt.Sprintf("%s: %s", e.Code, e.Message)
+	msg := e.Message
+	if msg == "" && e.Err != nil {
+		msg = e.Err.Error()
+	}
+	if e.Code == "" {
+		return fmt.Sprintf("linkdrop api (status %d): %s", e.StatusCode, msg)
+	}
+	return fmt.Sprintf("%s: %s", e.Code, msg)
+}
+
+func (e *Error) Unwrap() error {
+	return e.Err
+}
+
+// newApiError builds Error from the API response
+func newApiError(statusCode int, header http.Header, body []byte) *Error {
+	apiErr := &Error{StatusCode: statusCode}
+	if header != nil {
+		apiErr.RequestId = header.Get("X-Request-Id")
+	}
+
+	errBody := struct {
+		Error     string `json:"error"`
+		ErrorCode string `json:"error_code"`
+		Code      string `json:"code"`
+		Message   string `json:"message"`
+		RequestId string `json:"request_id"`
+	}{}
+	if json.Unmarshal(body, &errBody) == nil {
+		apiErr.Code = errBody.ErrorCode
+		if apiErr.Code == "" {
+			apiErr.Code = errBody.Code
+		}
+		apiErr.Message = errBody.Message
+		if apiErr.Message == "" {
+			apiErr.Message = errBody.Error
+		} else if apiErr.Code == "" {
+			apiErr.Code = errBody.Error
+		}
+		if errBody.RequestId != "" {
+			apiErr.RequestId = errBody.RequestId
+		}
+	} else {
+		apiErr.Message = strings.TrimSpace(string(body))
+	}
+	apiErr.Err = defineCause(apiErr)
+	return apiErr
+}
+
+// apiError converts the HTTP status error into Error, other errors are returned as is
+func apiError(err error) error {
+	var statusErr *helpers.StatusError
+	if errors.As(err, &statusErr) {
+		return newApiError(statusErr.StatusCode, statusErr.Header, statusErr.Body)
+	}
+	return err
+}
+
+// apiErrorCodes maps the normalized API error codes to the sentinels
+var apiErrorCodes = map[string]error{
+	"LINK_ALREADY_REDEEMED": ErrLinkAlreadyRedeemed,
+	"LINK_ALREADY_CLAIMED":  ErrLinkAlreadyRedeemed,
+	"LINK_EXPIRED":          ErrLinkExpired,
+	"TRANSFER_EXPIRED":      ErrLinkExpired,
+	"INSUFFICIENT_AMOUNT":   ErrInsufficientAmount,
+	"AMOUNT_TOO_LOW":        ErrInsufficientAmount,
+	"INVALID_SIGNATURE":     ErrInvalidSignature,
+	"INVALID_RECEIVER_SIG":  ErrInvalidSignature,
+	"INVALID_SENDER_SIG":    ErrInvalidSignature,
+}
+
+// apiErrorMessages maps the API messages without a known code to the sentinels.
+// The phrases name the link, so e.g. an expired fee authorization isn't reported as ErrLinkExpired.
+var apiErrorMessages = []struct {
+	phrase string
+	err    error
+}{
+	{"link already redeemed", ErrLinkAlreadyRedeemed},
+	{"link is already redeemed", ErrLinkAlreadyRedeemed},
+	{"link already claimed", ErrLinkAlreadyRedeemed},
+	{"link expired", ErrLinkExpired},
+	{"link is expired", ErrLinkExpired},
+	{"transfer expired", ErrLinkExpired},
+	{"insufficient amount", ErrInsufficientAmount},
+	{"invalid signature", ErrInvalidSignature},
+}
+
+// defineCause classifies the API failure by its error code, then by its message and the HTTP status
+func defineCause(apiErr *Error) error {
+	code := strings.ToUpper(strings.NewReplacer(" ", "_", "-", "_").Replace(strings.TrimSpace(apiErr.Code)))
+	if cause, ok := apiErrorCodes[code]; ok {
+		return cause
+	}
+	message := strings.ToLower(apiErr.Message)
+	for _, m := range apiErrorMessages {
+		if strings.Contains(message, m.phrase) {
+			return m.err
+		}
+	}
+	switch apiErr.StatusCode {
+	case http.StatusUnauthorized, http.StatusForbidden:
+		return ErrUnauthorized
+	case http.StatusTooManyRequests:
+		return ErrRateLimited
+	}
+	return nil
+}
+
+// linkNotFound marks 404 of the link endpoints as ErrLinkNotFound, 404 of the other endpoints means a wrong URL
+func linkNotFound(err error) error {
+	var apiErr *Error
+	if errors.As(err, &apiErr) && apiErr.StatusCode == http.StatusNotFound && apiErr.Err == nil {
+		apiErr.Err = ErrLinkNotFound
+	}
+	return err
 }
@@ -0,0 +1,174 @@
+package linkdrop
+
+import (
+	"context"
+	"errors"
+	"github.com/LinkdropHQ/linkdrop-go-sdk/types"
+	"github.com/ethereum/go-ethereum/common"
+	"net/http"
+	"testing"
+)
+
+func TestNewApiError(t *testing.T) {
+	tests := []struct {
+		name      string
+		status    int
+		header    http.Header
+		body      string
+		code      string
+		message   string
+		requestId string
+		cause     error
+	}{
+		{
+			name:    "error message",
+			status:  http.StatusBadRequest,
+			body:    `{"success": false, "error": "Fee authorization expired"}`,
+			message: "Fee authorization expired",
+		},
+		{
+			name:    "code and message",
+			status:  http.StatusBadRequest,
+			body:    `{"error_code": "SOME_CODE", "message": "Insufficient allowance"}`,
+			code:    "SOME_CODE",
+			message: "Insufficient allowance",
+		},
+		{
+			name:    "error as code with message",
+			status:  http.StatusConflict,
+			body:    `{"error": "CONFLICT", "message": "Transfer not found in cache"}`,
+			code:    "CONFLICT",
+			message: "Transfer not found in cache",
+		},
+		{
+			name:      "request id",
+			status:    http.StatusBadRequest,
+			header:    http.Header{"X-Request-Id": []string{"header-id"}},
+			body:      `{"error": "bad", "request_id": "body-id"}`,
+			message:   "bad",
+			requestId: "body-id",
+		},
+		{
+			name:    "plain text body",
+			status:  http.StatusBadGateway,
+			body:    " upstream failed \n",
+			message: "upstream failed",
+		},
+		{
+			name:    "unauthorized",
+			status:  http.StatusUnauthorized,
+			body:    `{"error": "invalid api key"}`,
+			message: "invalid api key",
+			cause:   ErrUnauthorized,
+		},
+		{
+			name:   "forbidden",
+			status: http.StatusForbidden,
+			body:   `{}`,
+			cause:  ErrUnauthorized,
+		},
+		{
+			name:   "rate limited",
+			status: http.StatusTooManyRequests,
+			body:   `{}`,
+			cause:  ErrRateLimited,
+		},
+		{
+			name:    "expired fee authorization isn't an expired link",
+			status:  http.StatusBadRequest,
+			body:    `{"error": "Fee authorization expired"}`,
+			message: "Fee authorization expired",
+		},
+		{
+			name:    "code takes precedence over status",
+			status:  http.StatusForbidden,
+			body:    `{"error_code": "INVALID_SIGNATURE", "message": "signature check failed"}`,
+			code:    "INVALID_SIGNATURE",
+			message: "signature check failed",
+			cause:   ErrInvalidSignature,
+		},
+		{
+			name:    "404 isn't a missing link by itself",
+			status:  http.StatusNotFound,
+			body:    `{"error": "not found"}`,
+			message: "not found",
+		},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			apiErr := newApiError(tt.status, tt.header, []byte(tt.body))
+			if apiErr.StatusCode != tt.status || apiErr.Code != tt.code || apiErr.Message != tt.message || apiErr.RequestId != tt.requestId {
+				t.Errorf("newApiError() = %+v", apiErr)
+			}
+			if apiErr.Err != tt.cause || (tt.cause != nil && !errors.Is(apiErr, tt.cause)) {
+				t.Errorf("cause = %v, want %v", apiErr.Err, tt.cause)
+			}
+		})
+	}
+}
+
+func TestDefineCause(t *testing.T) {
+	tests := []struct {
+		code    string
+		message string
+		cause   error
+	}{
+		{code: "LINK_ALREADY_REDEEMED", cause: ErrLinkAlreadyRedeemed},
+		{code: "LINK_ALREADY_CLAIMED", cause: ErrLinkAlreadyRedeemed},
+		{code: "LINK_EXPIRED", cause: ErrLinkExpired},
+		{code: "TRANSFER_EXPIRED", cause: ErrLinkExpired},
+		{code: "INSUFFICIENT_AMOUNT", cause: ErrInsufficientAmount},
+		{code: "AMOUNT_TOO_LOW", cause: ErrInsufficientAmount},
+		{code: "INVALID_SIGNATURE", cause: ErrInvalidSignature},
+		{code: "INVALID_RECEIVER_SIG", cause: ErrInvalidSignature},
+		{code: "INVALID_SENDER_SIG", cause: ErrInvalidSignature},
+		{code: "link-expired", cause: ErrLinkExpired},
+		{code: " invalid signature ", cause: ErrInvalidSignature},
+		{code: "UNKNOWN_CODE"},
+		{message: "Link already redeemed", cause: ErrLinkAlreadyRedeemed},
+		{message: "Link is already redeemed", cause: ErrLinkAlreadyRedeemed},
+		{message: "Link already claimed", cause: ErrLinkAlreadyRedeemed},
+		{message: "Link expired", cause: ErrLinkExpired},
+		{message: "Claim link is expired", cause: ErrLinkExpired},
+		{message: "Transfer expired", cause: ErrLinkExpired},
+		{message: "Insufficient amount to cover the fee", cause: ErrInsufficientAmount},
+		{message: "Invalid signature", cause: ErrInvalidSignature},
+		{message: "Insufficient allowance"},
+	}
+	for _, tt := range tests {
+		t.Run(tt.code+tt.message, func(t *testing.T) {
+			cause := defineCause(&Error{StatusCode: http.StatusBadRequest, Code: tt.code, Message: tt.message})
+			if cause != tt.cause {
+				t.Errorf("defineCause() = %v, want %v", cause, tt.cause)
+			}
+		})
+	}
+}
+
+func TestClientLinkNotFound(t *testing.T) {
+	sdk := newTestSDK(t, func(w http.ResponseWriter, r *http.Request) {
+		w.WriteHeader(http.StatusNotFound)
+		_, _ = w.Write([]byte(`{"success": false, "error": "not found"}`))
+	}, WithRetryPolicy(NoRetryPolicy()))
+
+	_, err := sdk.Client.GetTransferStatus(context.Background(), types.ChainIdBase, common.HexToAddress("0x01"))
+	if !errors.Is(err, ErrLinkNotFound) {
+		t.Errorf("GetTransferStatus() error = %v, want ErrLinkNotFound", err)
+	}
+	_, err = sdk.Client.GetLimits(context.Background(), types.Token{Type: types.TokenTypeNative, ChainId: types.ChainIdBase})
+	var apiErr *Error
+	if !errors.As(err, &apiErr) || errors.Is(err, ErrLinkNotFound) {
+		t.Errorf("GetLimits() error = %v, want *Error without ErrLinkNotFound", err)
+	}
+}
+
+func TestClientUnsuccessfulResponse(t *testing.T) {
+	sdk := newTestSDK(t, func(w http.ResponseWriter, r *http.Request) {
+		_, _ = w.Write([]byte(`{"success": false, "error": "Link expired"}`))
+	})
+	_, err := sdk.Client.GetTransferStatus(context.Background(), types.ChainIdBase, common.HexToAddress("0x01"))
+	var apiErr *Error
+	if !errors.As(err, &apiErr) || apiErr.Message != "Link expired" || !errors.Is(err, ErrLinkExpired) {
+		t.Errorf("GetTransferStatus() error = %#v", err)
+	}
+}
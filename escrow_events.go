@@ -0,0 +1,277 @@
+package linkdrop
+
+import (
+	"errors"
+	"fmt"
+	"github.com/LinkdropHQ/linkdrop-go-sdk/constants"
+	"github.com/LinkdropHQ/linkdrop-go-sdk/helpers"
+	"github.com/LinkdropHQ/linkdrop-go-sdk/types"
+	"github.com/ethereum/go-ethereum/accounts/abi"
+	"github.com/ethereum/go-ethereum/common"
+	gethtypes "github.com/ethereum/go-ethereum/core/types"
+	"math/big"
+)
+
+var ErrUnknownEscrowEvent = errors.New("unknown escrow event")
+
+// EscrowLog is the position of the event in the chain
+type EscrowLog struct {
+	Escrow      common.Address
+	BlockNumber uint64
+	BlockHash   common.Hash
+	TxHash      common.Hash
+	LogIndex    uint
+	Removed     bool // Removed - the log was reverted by a chain reorganization
+}
+
+// EscrowEvent is an event emitted by the escrow contracts, see DecodeEscrowEvent
+type EscrowEvent interface {
+	EventName() string
+	EventLog() EscrowLog
+}
+
+// EscrowTransferEvent is an EscrowEvent related to a single claim link
+type EscrowTransferEvent interface {
+	EscrowEvent
+	EventTransferId() common.Address
+}
+
+type DepositEvent struct {
+	Log        EscrowLog
+	Sender     common.Address
+	Token      common.Address
+	TransferId common.Address
+	Expiration int64
+	TokenType  types.TokenType
+	TokenId    *big.Int
+	Amount     *big.Int
+	FeeToken   common.Address
+	Fee        *big.Int
+}
+
+type RedeemEvent struct {
+	Log        EscrowLog
+	Sender     common.Address
+	Token      common.Address
+	Receiver   common.Address
+	TransferId common.Address
+	TokenType  types.TokenType
+	TokenId    *big.Int
+	Amount     *big.Int
+}
+
+type RefundEvent struct {
+	Log        EscrowLog
+	Sender     common.Address
+	Token      common.Address
+	TransferId common.Address
+	TokenType  types.TokenType
+	TokenId    *big.Int
+	Amount     *big.Int
+}
+
+type CancelEvent struct {
+	Log        EscrowLog
+	Sender     common.Address
+	Token      common.Address
+	TransferId common.Address
+	TokenType  types.TokenType
+	TokenId    *big.Int
+	Amount     *big.Int
+}
+
+type SenderMessageEvent struct {
+	Log        EscrowLog
+	Sender     common.Address
+	TransferId common.Address
+	Message    []byte // Message - the encrypted sender message, see ClaimLink.DecryptSenderMessage
+}
+
+type UpdateFeesEvent struct {
+	Log        EscrowLog
+	ClaimFee   *big.Int
+	DepositFee *big.Int
+}
+
+type WithdrawFeesEvent struct {
+	Log         EscrowLog
+	FeeReceiver common.Address
+	Token       common.Address
+	Amount      *big.Int
+}
+
+func (e *DepositEvent) EventName() string                     { return "Deposit" }
+func (e *DepositEvent) EventLog() EscrowLog                   { return e.Log }
+func (e *DepositEvent) EventTransferId() common.Address       { return e.TransferId }
+func (e *RedeemEvent) EventName() string                      { return "Redeem" }
+func (e *RedeemEvent) EventLog() EscrowLog                    { return e.Log }
+func (e *RedeemEvent) EventTransferId() common.Address        { return e.TransferId }
+func (e *RefundEvent) EventName() string                      { return "Refund" }
+func (e *RefundEvent) EventLog() EscrowLog                    { return e.Log }
+func (e *RefundEvent) EventTransferId() common.Address        { return e.TransferId }
+func (e *CancelEvent) EventName() string                      { return "Cancel" }
+func (e *CancelEvent) EventLog() EscrowLog                    { return e.Log }
+func (e *CancelEvent) EventTransferId() common.Address        { return e.TransferId }
+func (e *SenderMessageEvent) EventName() string               { return "SenderMessage" }
+func (e *SenderMessageEvent) EventLog() EscrowLog             { return e.Log }
+func (e *SenderMessageEvent) EventTransferId() common.Address { return e.TransferId }
+func (e *UpdateFeesEvent) EventName() string                  { return "UpdateFees" }
+func (e *UpdateFeesEvent) EventLog() EscrowLog                { return e.Log }
+func (e *WithdrawFeesEvent) EventName() string                { return "WithdrawFees" }
+func (e *WithdrawFeesEvent) EventLog() EscrowLog              { return e.Log }
+
+// DecodeEscrowEvent decodes the log emitted by the token or the NFT escrow contract.
+// ErrUnknownEscrowEvent is returned for the logs of other events.
+func DecodeEscrowEvent(log gethtypes.Log) (event EscrowEvent, err error) {
+	err = helpers.LoadABI()
+	if err != nil {
+		return
+	}
+	if len(log.Topics) == 0 {
+		return nil, ErrUnknownEscrowEvent
+	}
+	// both escrows declare the same events
+	abiEvent, err := constants.EscrowTokenAbi.EventByID(log.Topics[0])
+	if err != nil {
+		return nil, ErrUnknownEscrowEvent
+	}
+
+	fields := make(map[string]any)
+	err = abiEvent.Inputs.UnpackIntoMap(fields, log.Data)
+	if err != nil {
+		return nil, fmt.Errorf("failed to decode %s event: %w", abiEvent.Name, err)
+	}
+	var indexed abi.Arguments
+	for _, input := range abiEvent.Inputs {
+		if input.Indexed {
+			indexed = append(indexed, input)
+		}
+	}
+	err = abi.ParseTopicsIntoMap(fields, indexed, log.Topics[1:])
+	if err != nil {
+		return nil, fmt.Errorf("failed to decode %s event: %w", abiEvent.Name, err)
+	}
+
+	f := &eventFields{values: fields}
+	escrowLog := EscrowLog{
+		Escrow:      log.Address,
+		BlockNumber: log.BlockNumber,
+		BlockHash:   log.BlockHash,
+		TxHash:      log.TxHash,
+		LogIndex:    log.Index,
+		Removed:     log.Removed,
+	}
+	switch abiEvent.Name {
+	case "Deposit":
+		event = &DepositEvent{
+			Log:        escrowLog,
+			Sender:     f.address("sender"),
+			Token:      f.address("token"),
+			TransferId: f.address("transferId"),
+			Expiration: f.bigInt("expiration").Int64(),
+			TokenType:  f.tokenType("tokenType"),
+			TokenId:    f.bigInt("tokenId"),
+			Amount:     f.bigInt("amount"),
+			FeeToken:   f.address("feeToken"),
+			Fee:        f.bigInt("fee"),
+		}
+	case "Redeem":
+		event = &RedeemEvent{
+			Log:        escrowLog,
+			Sender:     f.address("sender"),
+			Token:      f.address("token"),
+			Receiver:   f.address("receiver"),
+			TransferId: f.address("transferId"),
+			TokenType:  f.tokenType("tokenType"),
+			TokenId:    f.bigInt("tokenId"),
+			Amount:     f.bigInt("amount"),
+		}
+	case "Refund":
+		event = &RefundEvent{
+			Log:        escrowLog,
+			Sender:     f.address("sender"),
+			Token:      f.address("token"),
+			TransferId: f.address("transferId"),
+			TokenType:  f.tokenType("tokenType"),
+			TokenId:    f.bigInt("tokenId"),
+			Amount:     f.bigInt("amount"),
+		}
+	case "Cancel":
+		event = &CancelEvent{
+			Log:        escrowLog,
+			Sender:     f.address("sender"),
+			Token:      f.address("token"),
+			TransferId: f.address("transferId"),
+			TokenType:  f.tokenType("tokenType"),
+			TokenId:    f.bigInt("tokenId"),
+			Amount:     f.bigInt("amount"),
+		}
+	case "SenderMessage":
+		message, _ := fields["senderMessage"].([]byte)
+		event = &SenderMessageEvent{
+			Log:        escrowLog,
+			Sender:     f.address("sender"),
+			TransferId: f.address("transferId"),
+			Message:    message,
+		}
+	case "UpdateFees":
+		event = &UpdateFeesEvent{
+			Log:        escrowLog,
+			ClaimFee:   f.bigInt("claimFee"),
+			DepositFee: f.bigInt("depositFee"),
+		}
+	case "WithdrawFees":
+		event = &WithdrawFeesEvent{
+			Log:         escrowLog,
+			FeeReceiver: f.address("feeReceiver"),
+			Token:       f.address("token_"),
+			Amount:      f.bigInt("amount"),
+		}
+	default:
+		return nil, ErrUnknownEscrowEvent
+	}
+	if f.err != nil {
+		return nil, fmt.Errorf("failed to decode %s event: %w", abiEvent.Name, f.err)
+	}
+	return
+}
+
+// eventFields reads the decoded event fields recording the first failure in err
+type eventFields struct {
+	values map[string]any
+	err    error
+}
+
+func (ef *eventFields) address(name string) (value common.Address) {
+	value, ok := ef.values[name].(common.Address)
+	if !ok {
+		ef.fail(fmt.Errorf("invalid event field %s", name))
+	}
+	return
+}
+
+func (ef *eventFields) bigInt(name string) *big.Int {
+	value, ok := ef.values[name].(*big.Int)
+	if !ok {
+		ef.fail(fmt.Errorf("invalid event field %s", name))
+		return new(big.Int)
+	}
+	return value
+}
+
+func (ef *eventFields) tokenType(name string) types.TokenType {
+	value, ok := ef.values[name].(uint8)
+	if !ok {
+		ef.fail(fmt.Errorf("invalid event field %s", name))
+		return ""
+	}
+	tokenType, err := types.TokenTypeFromEscrow(value)
+	ef.fail(err)
+	return tokenType
+}
+
+func (ef *eventFields) fail(err error) {
+	if ef.err == nil {
+		ef.err = err
+	}
+}
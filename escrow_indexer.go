@@ -0,0 +1,285 @@
+package linkdrop
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"github.com/ethereum/go-ethereum"
+	"github.com/ethereum/go-ethereum/common"
+	gethtypes "github.com/ethereum/go-ethereum/core/types"
+	"math/big"
+	"sync"
+	"time"
+)
+
+// LogFilterer is the part of *ethclient.Client used by EscrowIndexer
+type LogFilterer interface {
+	FilterLogs(ctx context.Context, query ethereum.FilterQuery) ([]gethtypes.Log, error)
+	HeaderByNumber(ctx context.Context, number *big.Int) (*gethtypes.Header, error)
+}
+
+// maxCheckpointAncestors is the number of the previous checkpoints kept to find the common ancestor after a reorganization
+const maxCheckpointAncestors = 64
+
+// Checkpoint is the last block processed by EscrowIndexer
+type Checkpoint struct {
+	BlockNumber uint64       `json:"blockNumber"`
+	BlockHash   common.Hash  `json:"blockHash"`
+	Ancestors   []Checkpoint `json:"ancestors,omitempty"` // Ancestors - the previous checkpoints, the newest first
+}
+
+// CheckpointStore persists the EscrowIndexer progress. LoadCheckpoint returns nil if there is no checkpoint yet.
+type CheckpointStore interface {
+	LoadCheckpoint(ctx context.Context) (*Checkpoint, error)
+	SaveCheckpoint(ctx context.Context, checkpoint Checkpoint) error
+}
+
+// MemoryCheckpointStore keeps the checkpoint in memory, the indexing starts over after restart
+type MemoryCheckpointStore struct {
+	mu         sync.Mutex
+	checkpoint *Checkpoint
+}
+
+func (mcs *MemoryCheckpointStore) LoadCheckpoint(ctx context.Context) (*Checkpoint, error) {
+	mcs.mu.Lock()
+	defer mcs.mu.Unlock()
+	if mcs.checkpoint == nil {
+		return nil, nil
+	}
+	checkpoint := *mcs.checkpoint
+	return &checkpoint, nil
+}
+
+func (mcs *MemoryCheckpointStore) SaveCheckpoint(ctx context.Context, checkpoint Checkpoint) error {
+	mcs.mu.Lock()
+	defer mcs.mu.Unlock()
+	mcs.checkpoint = &checkpoint
+	return nil
+}
+
+// EscrowIndexerConfig configures EscrowIndexer
+type EscrowIndexerConfig struct {
+	Escrows       []common.Address   // Escrows - the escrow contracts to index
+	Senders       []common.Address   // Senders - optional, index only the links of the senders (fee events are skipped then)
+	FromBlock     uint64             // FromBlock - the first block to index if there is no checkpoint
+	BatchSize     uint64             // BatchSize - the max number of blocks per FilterLogs request, 2000 by default
+	Confirmations *uint64            // Confirmations - the number of blocks behind the head considered final, 12 if nil
+	PollInterval  time.Duration      // PollInterval - the delay between the syncs in Run, 15s by default
+	OnReorg       EscrowReorgHandler // OnReorg - optional, receives the processed blocks orphaned by a chain reorganization
+}
+
+// EscrowEventsHandler receives the events of a single claim link found in the processed block range.
+// The events are ordered by block and log index. The events not related to a link (UpdateFees, WithdrawFees)
+// are delivered with zero transferId.
+// The handler should be idempotent: after a chain reorganization or a failure the range is processed again.
+type EscrowEventsHandler func(ctx context.Context, transferId common.Address, events []EscrowEvent) error
+
+// EscrowReorgHandler receives the range of the processed blocks that are no longer on the canonical chain.
+// The handler should roll back the events of the blocks (see EscrowLog.BlockNumber), the range is processed again after it.
+type EscrowReorgHandler func(ctx context.Context, fromBlock uint64, toBlock uint64) error
+
+// EscrowIndexer walks the escrow logs in block ranges and emits the decoded events grouped by TransferId
+type EscrowIndexer struct {
+	client        LogFilterer
+	config        EscrowIndexerConfig
+	confirmations uint64
+	checkpoints   CheckpointStore
+}
+
+// NewEscrowIndexer creates the indexer. MemoryCheckpointStore is used if checkpoints is nil.
+func NewEscrowIndexer(client LogFilterer, config EscrowIndexerConfig, checkpoints CheckpointStore) (*EscrowIndexer, error) {
+	if len(config.Escrows) == 0 {
+		return nil, errors.New("at least one escrow address is required")
+	}
+	if config.BatchSize == 0 {
+		config.BatchSize = 2000
+	}
+	confirmations := uint64(12)
+	if config.Confirmations != nil {
+		confirmations = *config.Confirmations
+	}
+	if config.PollInterval == 0 {
+		config.PollInterval = 15 * time.Second
+	}
+	if checkpoints == nil {
+		checkpoints = &MemoryCheckpointStore{}
+	}
+	return &EscrowIndexer{
+		client:        client,
+		config:        config,
+		confirmations: confirmations,
+		checkpoints:   checkpoints,
+	}, nil
+}
+
+// Run syncs the indexer every PollInterval until ctx is done or the handler fails
+func (ei *EscrowIndexer) Run(ctx context.Context, handler EscrowEventsHandler) error {
+	for {
+		if _, err := ei.Sync(ctx, handler); err != nil {
+			return err
+		}
+		timer := time.NewTimer(ei.config.PollInterval)
+		select {
+		case <-ctx.Done():
+			timer.Stop()
+			return ctx.Err()
+		case <-timer.C:
+		}
+	}
+}
+
+// Sync processes the blocks from the checkpoint up to the confirmed head and returns the new checkpoint.
+// If the processed blocks were reorganized, OnReorg receives the orphaned blocks and the indexing is resumed
+// after the common ancestor. ErrReorgTooDeep is returned if no checkpoint of the history is on the canonical chain.
+func (ei *EscrowIndexer) Sync(ctx context.Context, handler EscrowEventsHandler) (checkpoint Checkpoint, err error) {
+	from, checkpoint, err := ei.startBlock(ctx)
+	if err != nil {
+		return
+	}
+	head, err := ei.client.HeaderByNumber(ctx, nil)
+	if err != nil {
+		return
+	}
+	if head.Number.Uint64() < ei.confirmations {
+		return
+	}
+	confirmed := head.Number.Uint64() - ei.confirmations
+
+	for from <= confirmed {
+		to := min(from+ei.config.BatchSize-1, confirmed)
+		// the header is read before the logs, a reorganization in between is detected by the next Sync
+		header, err := ei.client.HeaderByNumber(ctx, new(big.Int).SetUint64(to))
+		if err != nil {
+			return checkpoint, err
+		}
+		err = ei.processRange(ctx, from, to, header.Hash(), handler)
+		if errors.Is(err, errChainChanged) {
+			return checkpoint, nil
+		}
+		if err != nil {
+			return checkpoint, err
+		}
+		checkpoint = nextCheckpoint(checkpoint, to, header.Hash())
+		err = ei.checkpoints.SaveCheckpoint(ctx, checkpoint)
+		if err != nil {
+			return checkpoint, err
+		}
+		from = to + 1
+	}
+	return
+}
+
+// startBlock returns the first block to process and the checkpoint on the canonical chain.
+// If the checkpoint block was reorganized, the checkpoint history is walked back to the common ancestor.
+func (ei *EscrowIndexer) startBlock(ctx context.Context) (from uint64, checkpoint Checkpoint, err error) {
+	loaded, err := ei.checkpoints.LoadCheckpoint(ctx)
+	if err != nil {
+		return
+	}
+	if loaded == nil {
+		return ei.config.FromBlock, checkpoint, nil
+	}
+	candidates := append([]Checkpoint{*loaded}, loaded.Ancestors...)
+	for i, candidate := range candidates {
+		header, err := ei.client.HeaderByNumber(ctx, new(big.Int).SetUint64(candidate.BlockNumber))
+		if err != nil {
+			return 0, checkpoint, err
+		}
+		if header.Hash() != candidate.BlockHash {
+			continue
+		}
+		if i == 0 {
+			return loaded.BlockNumber + 1, *loaded, nil
+		}
+		checkpoint = Checkpoint{
+			BlockNumber: candidate.BlockNumber,
+			BlockHash:   candidate.BlockHash,
+			Ancestors:   candidates[i+1:],
+		}
+		if ei.config.OnReorg != nil {
+			err = ei.config.OnReorg(ctx, checkpoint.BlockNumber+1, loaded.BlockNumber)
+			if err != nil {
+				return 0, checkpoint, err
+			}
+		}
+		err = ei.checkpoints.SaveCheckpoint(ctx, checkpoint)
+		if err != nil {
+			return 0, checkpoint, err
+		}
+		return checkpoint.BlockNumber + 1, checkpoint, nil
+	}
+	oldest := candidates[len(candidates)-1]
+	return 0, checkpoint, fmt.Errorf("%w: block %d of the oldest checkpoint is reorganized", ErrReorgTooDeep, oldest.BlockNumber)
+}
+
+// nextCheckpoint returns the checkpoint of the block keeping the previous one in the history
+func nextCheckpoint(previous Checkpoint, blockNumber uint64, blockHash common.Hash) Checkpoint {
+	checkpoint := Checkpoint{BlockNumber: blockNumber, BlockHash: blockHash}
+	if previous.BlockHash == (common.Hash{}) {
+		return checkpoint
+	}
+	ancestors := append([]Checkpoint{{BlockNumber: previous.BlockNumber, BlockHash: previous.BlockHash}}, previous.Ancestors...)
+	checkpoint.Ancestors = ancestors[:min(len(ancestors), maxCheckpointAncestors)]
+	return checkpoint
+}
+
+// errChainChanged is returned by processRange if the logs are not from the chain of the range header
+var errChainChanged = errors.New("chain changed during the sync")
+
+func (ei *EscrowIndexer) processRange(
+	ctx context.Context,
+	from uint64,
+	to uint64,
+	toHash common.Hash,
+	handler EscrowEventsHandler,
+) error {
+	query := ethereum.FilterQuery{
+		FromBlock: new(big.Int).SetUint64(from),
+		ToBlock:   new(big.Int).SetUint64(to),
+		Addresses: ei.config.Escrows,
+	}
+	if len(ei.config.Senders) > 0 {
+		senders := make([]common.Hash, len(ei.config.Senders))
+		for i, sender := range ei.config.Senders {
+			senders[i] = common.BytesToHash(sender.Bytes())
+		}
+		// sender is the first indexed argument of all link events
+		query.Topics = [][]common.Hash{nil, senders}
+	}
+	logs, err := ei.client.FilterLogs(ctx, query)
+	if err != nil {
+		return err
+	}
+
+	var order []common.Address
+	grouped := make(map[common.Address][]EscrowEvent)
+	for _, log := range logs {
+		if log.Removed {
+			continue
+		}
+		if log.BlockNumber == to && log.BlockHash != toHash {
+			return errChainChanged
+		}
+		event, err := DecodeEscrowEvent(log)
+		if errors.Is(err, ErrUnknownEscrowEvent) {
+			continue
+		}
+		if err != nil {
+			return err
+		}
+		var transferId common.Address
+		if transferEvent, ok := event.(EscrowTransferEvent); ok {
+			transferId = transferEvent.EventTransferId()
+		}
+		if _, ok := grouped[transferId]; !ok {
+			order = append(order, transferId)
+		}
+		grouped[transferId] = append(grouped[transferId], event)
+	}
+	for _, transferId := range order {
+		if err = handler(ctx, transferId, grouped[transferId]); err != nil {
+			return err
+		}
+	}
+	return nil
+}
@@ -0,0 +1,121 @@
+package linkdrop
+
+import (
+	"context"
+	"errors"
+	"github.com/ethereum/go-ethereum"
+	"github.com/ethereum/go-ethereum/common"
+	gethtypes "github.com/ethereum/go-ethereum/core/types"
+	"math/big"
+	"testing"
+)
+
+// stubChain serves the headers of the blocks up to head, the blocks from fork on have the fork extra data
+type stubChain struct {
+	head      uint64
+	fork      uint64
+	forkExtra byte
+	logs      []gethtypes.Log
+}
+
+func (c *stubChain) header(number uint64) *gethtypes.Header {
+	header := &gethtypes.Header{Number: new(big.Int).SetUint64(number)}
+	if c.forkExtra != 0 && number >= c.fork {
+		header.Extra = []byte{c.forkExtra}
+	}
+	return header
+}
+
+func (c *stubChain) HeaderByNumber(ctx context.Context, number *big.Int) (*gethtypes.Header, error) {
+	if number == nil {
+		return c.header(c.head), nil
+	}
+	return c.header(number.Uint64()), nil
+}
+
+func (c *stubChain) FilterLogs(ctx context.Context, query ethereum.FilterQuery) ([]gethtypes.Log, error) {
+	return c.logs, nil
+}
+
+func TestEscrowIndexerSync(t *testing.T) {
+	ctx := context.Background()
+	chain := &stubChain{head: 30}
+	var orphaned [][2]uint64
+	confirmations := uint64(0)
+	indexer, err := NewEscrowIndexer(chain, EscrowIndexerConfig{
+		Escrows:       []common.Address{common.HexToAddress("0x01")},
+		BatchSize:     10,
+		Confirmations: &confirmations,
+		OnReorg: func(ctx context.Context, fromBlock uint64, toBlock uint64) error {
+			orphaned = append(orphaned, [2]uint64{fromBlock, toBlock})
+			return nil
+		},
+	}, nil)
+	if err != nil {
+		t.Fatal(err)
+	}
+	handler := func(ctx context.Context, transferId common.Address, events []EscrowEvent) error {
+		return nil
+	}
+
+	checkpoint, err := indexer.Sync(ctx, handler)
+	if err != nil {
+		t.Fatal(err)
+	}
+	if checkpoint.BlockNumber != 30 || checkpoint.BlockHash != chain.header(30).Hash() || len(checkpoint.Ancestors) != 3 {
+		t.Fatalf("unexpected checkpoint %+v", checkpoint)
+	}
+
+	checkpoint, err = indexer.Sync(ctx, handler)
+	if err != nil {
+		t.Fatal(err)
+	}
+	if checkpoint.BlockNumber != 30 || checkpoint.BlockHash != chain.header(30).Hash() {
+		t.Fatalf("expected the loaded checkpoint without new blocks, got %+v", checkpoint)
+	}
+
+	// the blocks from 25 are replaced, the checkpoint of the block 19 is the common ancestor
+	chain.fork, chain.forkExtra = 25, 1
+	checkpoint, err = indexer.Sync(ctx, handler)
+	if err != nil {
+		t.Fatal(err)
+	}
+	if len(orphaned) != 1 || orphaned[0] != [2]uint64{20, 30} {
+		t.Fatalf("unexpected orphaned blocks %v", orphaned)
+	}
+	if checkpoint.BlockNumber != 30 || checkpoint.BlockHash != chain.header(30).Hash() {
+		t.Fatalf("unexpected checkpoint after the reorganization %+v", checkpoint)
+	}
+
+	chain.fork, chain.forkExtra = 0, 2
+	_, err = indexer.Sync(ctx, handler)
+	if !errors.Is(err, ErrReorgTooDeep) {
+		t.Fatalf("expected ErrReorgTooDeep, got %v", err)
+	}
+}
+
+func TestEscrowIndexerChainChanged(t *testing.T) {
+	ctx := context.Background()
+	// the log of the last block is from another chain than the header read before
+	chain := &stubChain{head: 20, logs: []gethtypes.Log{{BlockNumber: 8, BlockHash: common.HexToHash("0x08")}}}
+	indexer, err := NewEscrowIndexer(chain, EscrowIndexerConfig{
+		Escrows: []common.Address{common.HexToAddress("0x01")},
+	}, nil)
+	if err != nil {
+		t.Fatal(err)
+	}
+	checkpoint, err := indexer.Sync(ctx, func(ctx context.Context, transferId common.Address, events []EscrowEvent) error {
+		t.Fatal("unexpected events")
+		return nil
+	})
+	if err != nil {
+		t.Fatal(err)
+	}
+	if checkpoint.BlockNumber != 0 || checkpoint.BlockHash != (common.Hash{}) {
+		t.Fatalf("expected no checkpoint, got %+v", checkpoint)
+	}
+	loaded, err := indexer.checkpoints.LoadCheckpoint(ctx)
+	if err != nil || loaded != nil {
+		t.Fatalf("expected no saved checkpoint, got %+v", loaded)
+	}
+}
@@ -0,0 +1,190 @@
+package linkdrop
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"github.com/LinkdropHQ/linkdrop-go-sdk/helpers"
+	"github.com/LinkdropHQ/linkdrop-go-sdk/types"
+	"github.com/ethereum/go-ethereum"
+	"github.com/ethereum/go-ethereum/accounts/abi"
+	"github.com/ethereum/go-ethereum/accounts/abi/bind"
+	"github.com/ethereum/go-ethereum/common"
+	"math/big"
+)
+
+// OnChainDeposit is the deposit state stored in the escrow contract
+type OnChainDeposit struct {
+	Escrow     common.Address
+	Sender     common.Address
+	TransferId common.Address
+	Token      types.Token
+	Amount     *big.Int
+	Expiration int64
+	Fee        *types.ClaimLinkFee // Fee - the fee paid on deposit, set only if the reader has a log filterer, see EscrowReader.WithFeeLookup
+}
+
+// EscrowReader reads deposits directly from the escrow contracts to verify the data returned by the API
+type EscrowReader struct {
+	sdk       *SDK
+	caller    bind.ContractCaller
+	filterer  bind.ContractFilterer
+	fromBlock uint64
+}
+
+// EscrowReader creates the reader using caller, e.g. *ethclient.Client connected to the token chain
+func (sdk *SDK) EscrowReader(caller bind.ContractCaller) *EscrowReader {
+	return &EscrowReader{sdk: sdk, caller: caller}
+}
+
+// WithFeeLookup enables reading the deposit fee from the Deposit event.
+// The fee isn't kept in the escrow storage, so the logs starting from fromBlock are searched for it.
+func (er *EscrowReader) WithFeeLookup(filterer bind.ContractFilterer, fromBlock uint64) *EscrowReader {
+	er.filterer = filterer
+	er.fromBlock = fromBlock
+	return er
+}
+
+// GetDeposit reads the deposit from the default escrow of the token, see GetDepositAt.
+// ERC721 and ERC1155 deposits are read from the NFT escrow.
+func (er *EscrowReader) GetDeposit(
+	ctx context.Context,
+	token types.Token,
+	sender common.Address,
+	transferId common.Address,
+) (deposit *OnChainDeposit, err error) {
+	escrowAddress, err := er.sdk.config.chains.EscrowAddressByToken(token)
+	if err != nil {
+		return
+	}
+	return er.GetDepositAt(ctx, escrowAddress, token, sender, transferId)
+}
+
+// GetDepositAt reads the deposit from the escrow contract at escrowAddress.
+// ErrLinkNotFound is returned if there is no deposit, e.g. it was not made yet or already withdrawn.
+func (er *EscrowReader) GetDepositAt(
+	ctx context.Context,
+	escrowAddress common.Address,
+	token types.Token,
+	sender common.Address,
+	transferId common.Address,
+) (deposit *OnChainDeposit, err error) {
+	escrowVersion, err := er.sdk.EscrowVersion(escrowAddress)
+	if err != nil {
+		return
+	}
+	escrowAbi := escrowVersion.Abi(token.Type)
+	outputs, err := helpers.CallContract(ctx, er.caller, escrowAbi, escrowAddress, "getDeposit", token.Address, sender, transferId)
+	if err != nil {
+		return
+	}
+	if len(outputs) != 5 {
+		return nil, errors.New("unexpected getDeposit output")
+	}
+	depositToken, _ := outputs[0].(common.Address)
+	escrowTokenType, _ := outputs[1].(uint8)
+	tokenId, _ := outputs[2].(*big.Int)
+	amount, _ := outputs[3].(*big.Int)
+	expiration, _ := outputs[4].(*big.Int)
+	if amount == nil || amount.Sign() == 0 {
+		return nil, ErrLinkNotFound
+	}
+	tokenType, err := types.TokenTypeFromEscrow(escrowTokenType)
+	if err != nil {
+		return
+	}
+
+	deposit = &OnChainDeposit{
+		Escrow:     escrowAddress,
+		Sender:     sender,
+		TransferId: transferId,
+		Token: types.Token{
+			Type:    tokenType,
+			ChainId: token.ChainId,
+			Address: depositToken,
+		},
+		Amount:     amount,
+		Expiration: expiration.Int64(),
+	}
+	if tokenType == types.TokenTypeERC721 || tokenType == types.TokenTypeERC1155 {
+		deposit.Token.Id = tokenId
+	}
+	if er.filterer != nil {
+		deposit.Fee, err = er.depositFee(ctx, escrowAbi, deposit)
+		if err != nil {
+			return nil, err
+		}
+	}
+	return
+}
+
+// depositFee finds the fee of the deposit in the Deposit event
+func (er *EscrowReader) depositFee(ctx context.Context, escrowAbi abi.ABI, deposit *OnChainDeposit) (*types.ClaimLinkFee, error) {
+	event := escrowAbi.Events["Deposit"]
+	logs, err := er.filterer.FilterLogs(ctx, ethereum.FilterQuery{
+		FromBlock: new(big.Int).SetUint64(er.fromBlock),
+		Addresses: []common.Address{deposit.Escrow},
+		Topics: [][]common.Hash{
+			{event.ID},
+			{common.BytesToHash(deposit.Sender.Bytes())},
+			{common.BytesToHash(deposit.Token.Address.Bytes())},
+		},
+	})
+	if err != nil {
+		return nil, err
+	}
+	for _, log := range logs {
+		event, err := DecodeEscrowEvent(log)
+		if err != nil {
+			return nil, err
+		}
+		depositEvent, ok := event.(*DepositEvent)
+		if !ok || depositEvent.TransferId != deposit.TransferId {
+			continue
+		}
+		feeTokenType := types.TokenTypeERC20
+		if depositEvent.FeeToken == types.ZeroAddress {
+			feeTokenType = types.TokenTypeNative
+		}
+		return &types.ClaimLinkFee{
+			Token: types.Token{
+				Type:    feeTokenType,
+				ChainId: deposit.Token.ChainId,
+				Address: depositEvent.FeeToken,
+			},
+			Amount: depositEvent.Fee,
+		}, nil
+	}
+	return nil, errors.New("deposit event is not found")
+}
+
+// GetOnChainDeposit reads the claim link deposit from its escrow contract
+func (cl *ClaimLink) GetOnChainDeposit(ctx context.Context, reader *EscrowReader) (*OnChainDeposit, error) {
+	return reader.GetDepositAt(ctx, cl.EscrowAddress, cl.Token, cl.Sender, cl.TransferId)
+}
+
+// VerifyOnChain checks that the claim link token, amount and expiration match the escrow deposit.
+// The escrow keeps TotalAmount of the native and ERC20 deposits, it includes the fee taken in the deposited token.
+func (cl *ClaimLink) VerifyOnChain(ctx context.Context, reader *EscrowReader) (err error) {
+	deposit, err := cl.GetOnChainDeposit(ctx, reader)
+	if err != nil {
+		return
+	}
+	if deposit.Token.Address != cl.Token.Address || deposit.Token.Type != cl.Token.Type {
+		return errors.New("deposited token doesn't match the claim link token")
+	}
+	if cl.Token.Id != nil && (deposit.Token.Id == nil || deposit.Token.Id.Cmp(cl.Token.Id) != 0) {
+		return errors.New("deposited token id doesn't match the claim link token id")
+	}
+	amount := cl.TotalAmount
+	if cl.Token.Type == types.TokenTypeERC721 || cl.Token.Type == types.TokenTypeERC1155 {
+		amount = cl.Amount
+	}
+	if amount == nil || deposit.Amount.Cmp(amount) != 0 {
+		return fmt.Errorf("deposited amount %s doesn't match the claim link amount %v", deposit.Amount, amount)
+	}
+	if deposit.Expiration != cl.Expiration {
+		return fmt.Errorf("deposit expiration %d doesn't match the claim link expiration", deposit.Expiration)
+	}
+	return nil
+}
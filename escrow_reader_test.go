@@ -0,0 +1,186 @@
+package linkdrop
+
+import (
+	"context"
+	"errors"
+	"github.com/LinkdropHQ/linkdrop-go-sdk/constants"
+	"github.com/LinkdropHQ/linkdrop-go-sdk/types"
+	"github.com/ethereum/go-ethereum"
+	"github.com/ethereum/go-ethereum/common"
+	"math/big"
+	"net/http"
+	"strings"
+	"testing"
+)
+
+// onChainDeposit is the getDeposit output of the escrow
+type onChainDeposit struct {
+	token      common.Address
+	tokenType  uint8 // tokenType - the escrow TokenType enum: native, ERC20, ERC721, ERC1155
+	tokenId    int64
+	amount     int64
+	expiration int64
+}
+
+// depositCaller answers getDeposit with deposit and records the call
+type depositCaller struct {
+	t       *testing.T
+	deposit onChainDeposit
+	err     error
+	call    ethereum.CallMsg
+}
+
+func (c *depositCaller) CodeAt(ctx context.Context, contract common.Address, blockNumber *big.Int) ([]byte, error) {
+	return []byte{1}, nil
+}
+
+func (c *depositCaller) CallContract(ctx context.Context, call ethereum.CallMsg, blockNumber *big.Int) ([]byte, error) {
+	c.call = call
+	if c.err != nil {
+		return nil, c.err
+	}
+	output, err := constants.EscrowTokenAbi.Methods["getDeposit"].Outputs.Pack(
+		c.deposit.token,
+		c.deposit.tokenType,
+		big.NewInt(c.deposit.tokenId),
+		big.NewInt(c.deposit.amount),
+		big.NewInt(c.deposit.expiration),
+	)
+	if err != nil {
+		c.t.Fatal(err)
+	}
+	return output, nil
+}
+
+// newVerifiedClaimLink returns the deposited link of the token type with its escrow deposit
+func newVerifiedClaimLink(t *testing.T, sdk *SDK, tokenType types.TokenType) (*ClaimLink, onChainDeposit) {
+	t.Helper()
+	claimLink := newDepositedClaimLink(t, sdk)
+	claimLink.Expiration = 1700000000
+	if tokenType == types.TokenTypeERC20 {
+		// the fee is taken in the deposited token
+		claimLink.TotalAmount = big.NewInt(1001000)
+		return claimLink, onChainDeposit{token: claimLink.Token.Address, tokenType: 1, amount: 1001000, expiration: 1700000000}
+	}
+	claimLink.Token = types.Token{
+		Type:    types.TokenTypeERC721,
+		ChainId: types.ChainIdBase,
+		Address: common.HexToAddress("0x0000000000000000000000000000000000000721"),
+		Id:      big.NewInt(7),
+	}
+	claimLink.Amount = big.NewInt(1)
+	claimLink.TotalAmount = big.NewInt(1)
+	escrowAddress, err := sdk.config.chains.EscrowAddressByToken(claimLink.Token)
+	if err != nil {
+		t.Fatal(err)
+	}
+	claimLink.EscrowAddress = escrowAddress
+	return claimLink, onChainDeposit{token: claimLink.Token.Address, tokenType: 2, tokenId: 7, amount: 1, expiration: 1700000000}
+}
+
+func TestEscrowReaderGetDeposit(t *testing.T) {
+	sdk := newTestSDK(t, func(w http.ResponseWriter, r *http.Request) {
+		t.Fatalf("unexpected request %s", r.URL)
+	})
+	claimLink, onChain := newVerifiedClaimLink(t, sdk, types.TokenTypeERC721)
+	caller := &depositCaller{t: t, deposit: onChain}
+
+	deposit, err := sdk.EscrowReader(caller).GetDeposit(context.Background(), claimLink.Token, claimLink.Sender, claimLink.TransferId)
+	if err != nil {
+		t.Fatal(err)
+	}
+	if deposit.Escrow != claimLink.EscrowAddress || deposit.Sender != claimLink.Sender || deposit.TransferId != claimLink.TransferId ||
+		deposit.Token.Type != types.TokenTypeERC721 || deposit.Token.Address != claimLink.Token.Address ||
+		deposit.Token.ChainId != types.ChainIdBase || deposit.Token.Id.Int64() != 7 ||
+		deposit.Amount.Int64() != 1 || deposit.Expiration != 1700000000 || deposit.Fee != nil {
+		t.Fatalf("unexpected deposit %+v", deposit)
+	}
+	if *caller.call.To != claimLink.EscrowAddress {
+		t.Fatalf("getDeposit is called on %s", caller.call.To)
+	}
+	args, err := constants.EscrowNFTAbi.Methods["getDeposit"].Inputs.Unpack(caller.call.Data[4:])
+	if err != nil {
+		t.Fatal(err)
+	}
+	if args[0].(common.Address) != claimLink.Token.Address || args[1].(common.Address) != claimLink.Sender ||
+		args[2].(common.Address) != claimLink.TransferId {
+		t.Fatalf("unexpected getDeposit arguments %v", args)
+	}
+}
+
+func TestClaimLinkVerifyOnChain(t *testing.T) {
+	errNode := errors.New("node is unavailable")
+	tests := []struct {
+		name      string
+		tokenType types.TokenType
+		modify    func(deposit *onChainDeposit)
+		callErr   error
+		wantErr   string
+		wantIs    error
+	}{
+		{name: "ERC20 deposit with the fee in the token", tokenType: types.TokenTypeERC20},
+		{name: "ERC721 deposit", tokenType: types.TokenTypeERC721},
+		{
+			name:      "token address",
+			tokenType: types.TokenTypeERC20,
+			modify:    func(deposit *onChainDeposit) { deposit.token = common.HexToAddress("0x01") },
+			wantErr:   "deposited token doesn't match",
+		},
+		{
+			name:      "token type",
+			tokenType: types.TokenTypeERC721,
+			modify:    func(deposit *onChainDeposit) { deposit.tokenType = 3 },
+			wantErr:   "deposited token doesn't match",
+		},
+		{
+			name:      "token id",
+			tokenType: types.TokenTypeERC721,
+			modify:    func(deposit *onChainDeposit) { deposit.tokenId = 8 },
+			wantErr:   "deposited token id doesn't match",
+		},
+		{
+			name:      "amount without the fee",
+			tokenType: types.TokenTypeERC20,
+			modify:    func(deposit *onChainDeposit) { deposit.amount = 1000000 },
+			wantErr:   "deposited amount 1000000 doesn't match the claim link amount 1001000",
+		},
+		{
+			name:      "expiration",
+			tokenType: types.TokenTypeERC20,
+			modify:    func(deposit *onChainDeposit) { deposit.expiration = 1700000001 },
+			wantErr:   "deposit expiration 1700000001 doesn't match",
+		},
+		{
+			name:      "no deposit",
+			tokenType: types.TokenTypeERC20,
+			modify:    func(deposit *onChainDeposit) { deposit.amount = 0 },
+			wantIs:    ErrLinkNotFound,
+		},
+		{name: "call error", tokenType: types.TokenTypeERC20, callErr: errNode, wantIs: errNode},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			sdk := newTestSDK(t, func(w http.ResponseWriter, r *http.Request) {
+				t.Fatalf("unexpected request %s", r.URL)
+			})
+			claimLink, onChain := newVerifiedClaimLink(t, sdk, tt.tokenType)
+			if tt.modify != nil {
+				tt.modify(&onChain)
+			}
+
+			err := claimLink.VerifyOnChain(context.Background(), sdk.EscrowReader(&depositCaller{t: t, deposit: onChain, err: tt.callErr}))
+			switch {
+			case tt.wantIs != nil:
+				if !errors.Is(err, tt.wantIs) {
+					t.Fatalf("expected %v, got %v", tt.wantIs, err)
+				}
+			case tt.wantErr != "":
+				if err == nil || !strings.Contains(err.Error(), tt.wantErr) {
+					t.Fatalf("expected %q, got %v", tt.wantErr, err)
+				}
+			case err != nil:
+				t.Fatal(err)
+			}
+		})
+	}
+}
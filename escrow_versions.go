@@ -0,0 +1,220 @@
+package linkdrop
+
+import (
+	"errors"
+	"fmt"
+	"github.com/LinkdropHQ/linkdrop-go-sdk/constants"
+	"github.com/LinkdropHQ/linkdrop-go-sdk/helpers"
+	"github.com/LinkdropHQ/linkdrop-go-sdk/types"
+	"github.com/ethereum/go-ethereum/accounts/abi"
+	"github.com/ethereum/go-ethereum/common"
+	"github.com/ethereum/go-ethereum/common/math"
+	"github.com/ethereum/go-ethereum/signer/core/apitypes"
+	"slices"
+	"sync"
+)
+
+var ErrEscrowFeatureNotSupported = errors.New("escrow feature is not supported")
+
+// EscrowFeatures lists the optional functionality of the escrow contracts
+type EscrowFeatures struct {
+	Messages              bool // Messages - deposits carry the encrypted sender message
+	AuthorizationDeposits bool // AuthorizationDeposits - stable coins can be deposited with EIP-3009 authorization
+	RecoveredLinks        bool // RecoveredLinks - links can be redeemed with the sender signature, see ClaimLinkRecovered
+}
+
+// DetectEscrowFeatures derives the features from the token escrow ABI
+func DetectEscrowFeatures(tokenAbi abi.ABI) EscrowFeatures {
+	_, messages := tokenAbi.Events["SenderMessage"]
+	_, authorizationDeposits := tokenAbi.Methods["depositWithAuthorization"]
+	_, recoveredLinks := tokenAbi.Methods["redeemRecovered"]
+	return EscrowFeatures{
+		Messages:              messages,
+		AuthorizationDeposits: authorizationDeposits,
+		RecoveredLinks:        recoveredLinks,
+	}
+}
+
+// EscrowVersion describes a version of the token and the NFT escrow contracts
+type EscrowVersion struct {
+	Version   string
+	Addresses []common.Address // Addresses - the deployments of the version, see constants.Escrows
+	TokenAbi  abi.ABI
+	NFTAbi    abi.ABI
+
+	DomainName         string          // DomainName - EIP-712 domain name of the escrow, the domain version is Version
+	RecoveredLinkType  string          // RecoveredLinkType - the primary type of the recovered link typed data
+	RecoveredLinkTypes []apitypes.Type // RecoveredLinkTypes - the fields of RecoveredLinkType, named linkKeyId and transferId
+
+	Features EscrowFeatures
+}
+
+// NewEscrowVersion creates the version with the current typed data and the features detected from tokenAbi
+func NewEscrowVersion(version string, addresses []common.Address, tokenAbi abi.ABI, nftAbi abi.ABI) *EscrowVersion {
+	return &EscrowVersion{
+		Version:           version,
+		Addresses:         addresses,
+		TokenAbi:          tokenAbi,
+		NFTAbi:            nftAbi,
+		DomainName:        "LinkdropEscrow",
+		RecoveredLinkType: "Transfer",
+		RecoveredLinkTypes: []apitypes.Type{
+			{Name: "linkKeyId", Type: "address"},
+			{Name: "transferId", Type: "address"},
+		},
+		Features: DetectEscrowFeatures(tokenAbi),
+	}
+}
+
+// Abi returns ABI of the escrow matching the token type
+func (ev *EscrowVersion) Abi(tokenType types.TokenType) abi.ABI {
+	if tokenType == types.TokenTypeERC721 || tokenType == types.TokenTypeERC1155 {
+		return ev.NFTAbi
+	}
+	return ev.TokenAbi
+}
+
+// Domain returns EIP-712 domain of the escrow deployed at escrowAddress
+func (ev *EscrowVersion) Domain(chainId types.ChainId, escrowAddress common.Address) apitypes.TypedDataDomain {
+	return apitypes.TypedDataDomain{
+		Name:              ev.DomainName,
+		Version:           ev.Version,
+		ChainId:           math.NewHexOrDecimal256(int64(chainId)),
+		VerifyingContract: escrowAddress.Hex(),
+	}
+}
+
+// RecoveredLinkTypedData returns the data signed by the sender to allow redeeming the link with linkKeyId
+func (ev *EscrowVersion) RecoveredLinkTypedData(
+	linkKeyId common.Address,
+	transferId common.Address,
+	chainId types.ChainId,
+	escrowAddress common.Address,
+) (typedData *apitypes.TypedData, err error) {
+	if err = ev.require(ev.Features.RecoveredLinks, "recovered links"); err != nil {
+		return
+	}
+	return &apitypes.TypedData{
+		Domain:      ev.Domain(chainId, escrowAddress),
+		PrimaryType: ev.RecoveredLinkType,
+		Types: apitypes.Types{
+			"EIP712Domain": {
+				{Name: "name", Type: "string"},
+				{Name: "version", Type: "string"},
+				{Name: "chainId", Type: "uint256"},
+				{Name: "verifyingContract", Type: "address"},
+			},
+			ev.RecoveredLinkType: ev.RecoveredLinkTypes,
+		},
+		Message: map[string]any{
+			"linkKeyId":  linkKeyId.Hex(),
+			"transferId": transferId.Hex(),
+		},
+	}, nil
+}
+
+func (ev *EscrowVersion) require(supported bool, feature string) error {
+	if !supported {
+		return fmt.Errorf("%w: %s are not available in escrow version %s", ErrEscrowFeatureNotSupported, feature, ev.Version)
+	}
+	return nil
+}
+
+// embeddedEscrowVersion is the version of the escrow ABIs embedded in the SDK
+const embeddedEscrowVersion = "3.2"
+
+// legacyEscrowVersions are the 3.x versions before embeddedEscrowVersion.
+// They share the embedded ABIs and the Transfer typed data, but don't accept sender messages and authorization deposits.
+var legacyEscrowVersions = []string{"3", "3.1"}
+
+// EscrowVersionRegistry keeps the escrow versions the SDK can work with.
+// The default registry has the 3.x versions, the links of the older versions of constants.Escrows
+// fail with ErrEscrowFeatureNotSupported until the version is registered with its own ABI.
+type EscrowVersionRegistry struct {
+	mu       sync.RWMutex
+	versions map[string]*EscrowVersion
+}
+
+// NewEscrowVersionRegistry creates a registry of the versions.
+// Start with DefaultEscrowVersions to override some of the default versions.
+func NewEscrowVersionRegistry(versions ...*EscrowVersion) *EscrowVersionRegistry {
+	evr := &EscrowVersionRegistry{versions: make(map[string]*EscrowVersion, len(versions))}
+	for _, version := range versions {
+		evr.Register(version)
+	}
+	return evr
+}
+
+// DefaultEscrowVersions returns the 3.x versions with their deployments of constants.Escrows.
+// The versions before 3.2 keep working with the existing links: they're redeemed, refunded and recovered,
+// but new deposits can't carry messages or use authorization.
+func DefaultEscrowVersions() (versions []*EscrowVersion, err error) {
+	err = helpers.LoadABI()
+	if err != nil {
+		return
+	}
+	for _, version := range legacyEscrowVersions {
+		legacy := NewEscrowVersion(version, slices.Clone(constants.Escrows[version]), constants.EscrowTokenAbi, constants.EscrowNFTAbi)
+		legacy.Features = EscrowFeatures{RecoveredLinks: true}
+		versions = append(versions, legacy)
+	}
+	addresses := slices.Clone(constants.Escrows[embeddedEscrowVersion])
+	versions = append(versions, NewEscrowVersion(embeddedEscrowVersion, addresses, constants.EscrowTokenAbi, constants.EscrowNFTAbi))
+	return
+}
+
+// Register adds the version replacing the version with the same name
+func (evr *EscrowVersionRegistry) Register(version *EscrowVersion) {
+	evr.mu.Lock()
+	defer evr.mu.Unlock()
+	evr.versions[version.Version] = version
+}
+
+// Version returns the version by its name, ErrEscrowFeatureNotSupported is returned for unregistered versions
+func (evr *EscrowVersionRegistry) Version(version string) (*EscrowVersion, error) {
+	evr.mu.RLock()
+	defer evr.mu.RUnlock()
+	escrowVersion, ok := evr.versions[version]
+	if !ok {
+		return nil, fmt.Errorf("%w: escrow version %s is not registered", ErrEscrowFeatureNotSupported, version)
+	}
+	return escrowVersion, nil
+}
+
+// ByAddress returns the version deployed at escrowAddress
+func (evr *EscrowVersionRegistry) ByAddress(escrowAddress common.Address) (*EscrowVersion, bool) {
+	evr.mu.RLock()
+	defer evr.mu.RUnlock()
+	for _, version := range evr.versions {
+		if slices.Contains(version.Addresses, escrowAddress) {
+			return version, true
+		}
+	}
+	return nil, false
+}
+
+// EscrowVersion detects the version of the escrow deployed at escrowAddress.
+// The escrows of the chain registry are resolved by their EscrowVersion, see types.Chain.
+// The known escrows of the versions that are not registered return ErrEscrowFeatureNotSupported.
+func (sdk *SDK) EscrowVersion(escrowAddress common.Address) (*EscrowVersion, error) {
+	if version, ok := sdk.config.escrowVersions.ByAddress(escrowAddress); ok {
+		return version, nil
+	}
+	version, err := sdk.escrowVersionName(escrowAddress)
+	if err != nil {
+		return nil, err
+	}
+	return sdk.config.escrowVersions.Version(version)
+}
+
+// escrowVersionName returns the version of the escrow of the chain registry or constants.Escrows
+func (sdk *SDK) escrowVersionName(escrowAddress common.Address) (string, error) {
+	if version, ok := sdk.config.chains.EscrowVersion(escrowAddress); ok {
+		return version, nil
+	}
+	version, err := helpers.DefineEscrowVersion(escrowAddress)
+	if err != nil {
+		return "", fmt.Errorf("escrow %s is not found in escrows", escrowAddress.Hex())
+	}
+	return version, nil
+}
@@ -0,0 +1,126 @@
+package linkdrop
+
+import (
+	"bytes"
+	"errors"
+	"github.com/LinkdropHQ/linkdrop-go-sdk/constants"
+	"github.com/LinkdropHQ/linkdrop-go-sdk/crypto"
+	"github.com/LinkdropHQ/linkdrop-go-sdk/helpers"
+	"github.com/LinkdropHQ/linkdrop-go-sdk/types"
+	"github.com/ethereum/go-ethereum/common"
+	"github.com/ethereum/go-ethereum/signer/core/apitypes"
+	"net/http"
+	"testing"
+)
+
+func TestSDKEscrowVersion(t *testing.T) {
+	escrowV31 := constants.Escrows["3.1"][0]
+	tests := []struct {
+		name        string
+		address     common.Address
+		opts        func(t *testing.T) []Option
+		wantVersion string
+		wantErr     error
+	}{
+		{name: "embedded version", address: constants.Escrows["3.2"][0], wantVersion: "3.2"},
+		{name: "chain registry escrow", address: constants.CbwEscrowContractAddress, wantVersion: "3.2"},
+		{name: "pre-3.2 version", address: escrowV31, wantVersion: "3.1"},
+		{name: "3.0 version", address: constants.Escrows["3"][0], wantVersion: "3"},
+		{name: "unregistered version", address: constants.Escrows["2"][0], wantErr: ErrEscrowFeatureNotSupported},
+		{
+			name:    "registered version",
+			address: escrowV31,
+			opts: func(t *testing.T) []Option {
+				versions, err := DefaultEscrowVersions()
+				if err != nil {
+					t.Fatal(err)
+				}
+				v31 := NewEscrowVersion("3.1", constants.Escrows["3.1"], constants.EscrowTokenAbi, constants.EscrowNFTAbi)
+				return []Option{WithEscrowVersions(NewEscrowVersionRegistry(append(versions, v31)...))}
+			},
+			wantVersion: "3.1",
+		},
+		{name: "unknown escrow", address: common.HexToAddress("0x01")},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			var opts []Option
+			if tt.opts != nil {
+				opts = tt.opts(t)
+			}
+			sdk := newTestSDK(t, func(w http.ResponseWriter, r *http.Request) {
+				t.Fatalf("unexpected request %s", r.URL)
+			}, opts...)
+			escrowVersion, err := sdk.EscrowVersion(tt.address)
+			if tt.wantVersion == "" {
+				if err == nil || (tt.wantErr != nil && !errors.Is(err, tt.wantErr)) {
+					t.Fatalf("expected error %v, got %v", tt.wantErr, err)
+				}
+				return
+			}
+			if err != nil {
+				t.Fatal(err)
+			}
+			if escrowVersion.Version != tt.wantVersion {
+				t.Fatalf("got version %s, want %s", escrowVersion.Version, tt.wantVersion)
+			}
+		})
+	}
+}
+
+func TestGetVersionFromEscrowContract(t *testing.T) {
+	sdk := newTestSDK(t, func(w http.ResponseWriter, r *http.Request) {
+		t.Fatalf("unexpected request %s", r.URL)
+	})
+	version, err := sdk.GetVersionFromEscrowContract(constants.Escrows["3.1"][0])
+	if err != nil {
+		t.Fatal(err)
+	}
+	if version != "3.1" {
+		t.Fatalf("got version %s", version)
+	}
+}
+
+func TestLegacyEscrowRecoveredLinkTypedData(t *testing.T) {
+	linkKeyId := common.HexToAddress("0x06")
+	for _, version := range []string{"3", "3.1"} {
+		t.Run(version, func(t *testing.T) {
+			sdk := newTestSDK(t, func(w http.ResponseWriter, r *http.Request) {
+				t.Fatalf("unexpected request %s", r.URL)
+			})
+			claimLink := newDepositedClaimLink(t, sdk)
+			claimLink.EscrowAddress = constants.Escrows[version][0]
+			claimLink.Status = types.ClaimLinkStatusCreated
+			recovered := &ClaimLinkRecovered{
+				SDK:           sdk,
+				TransferId:    claimLink.TransferId,
+				Token:         claimLink.Token,
+				Sender:        claimLink.Sender,
+				EscrowAddress: claimLink.EscrowAddress,
+			}
+
+			typedData, err := recovered.GetTypedData(linkKeyId)
+			if err != nil {
+				t.Fatal(err)
+			}
+			want := helpers.RecoveredLinkTypedData(linkKeyId, recovered.TransferId, recovered.Token.ChainId, version, recovered.EscrowAddress)
+			gotHash, _, err := apitypes.TypedDataAndHash(*typedData)
+			if err != nil {
+				t.Fatal(err)
+			}
+			wantHash, _, err := apitypes.TypedDataAndHash(want)
+			if err != nil {
+				t.Fatal(err)
+			}
+			if !bytes.Equal(gotHash, wantHash) || typedData.Domain.Version != version {
+				t.Fatalf("unexpected typed data %+v", typedData)
+			}
+
+			var nonce [crypto.NonceLength]byte
+			err = claimLink.AddMessageWithInitialKey("message", 12, [crypto.KeyLength]byte{1}, nonce)
+			if !errors.Is(err, ErrEscrowFeatureNotSupported) {
+				t.Fatalf("expected messages not to be supported, got %v", err)
+			}
+		})
+	}
+}
@@ -0,0 +1,82 @@
+package main
+
+import (
+	"context"
+	"crypto/rand"
+	"github.com/LinkdropHQ/linkdrop-go-sdk"
+	"github.com/LinkdropHQ/linkdrop-go-sdk/signer"
+	"github.com/LinkdropHQ/linkdrop-go-sdk/types"
+	"github.com/LinkdropHQ/linkdrop-go-sdk/userop"
+	"github.com/ethereum/go-ethereum/common"
+	"github.com/ethereum/go-ethereum/ethclient"
+	"log"
+	"math/big"
+	"os"
+)
+
+func getRandomBytes(length int64) []byte {
+	b := make([]byte, length)
+	_, err := rand.Read(b)
+	if err != nil {
+		log.Fatalf("Failed to generate random bytes: %v", err)
+	}
+	return b
+}
+
+func main() {
+	ctx := context.Background()
+
+	sdk, err := linkdrop.Init(
+		"https://p2p.linkdrop.io",
+		os.Getenv("LINKDROP_API_KEY"),
+	)
+	if err != nil {
+		log.Fatalln(err)
+	}
+
+	// The sender is the smart account
+	account := common.HexToAddress(os.Getenv("SMART_ACCOUNT_ADDRESS"))
+	cl, err := sdk.ClaimLink(
+		ctx,
+		linkdrop.ClaimLinkCreationParams{
+			Token: types.Token{
+				Type:    types.TokenTypeNative,
+				ChainId: types.ChainIdBase,
+				Address: types.ZeroAddress,
+			},
+			Sender:     account,
+			Amount:     big.NewInt(100000000000000),
+			Expiration: 1773234550,
+		},
+		getRandomBytes,
+	)
+	if err != nil {
+		log.Fatalln(err)
+	}
+
+	client, err := ethclient.Dial(os.Getenv("RPC_URL"))
+	if err != nil {
+		log.Fatalf("Failed to connect to Ethereum client: %v", err)
+	}
+	bundler, err := userop.NewRPCBundler(ctx, os.Getenv("BUNDLER_URL"))
+	if err != nil {
+		log.Fatalf("Failed to connect to bundler: %v", err)
+	}
+	owner, err := signer.NewPrivateKeyFromHex(os.Getenv("PRIVATE_KEY"))
+	if err != nil {
+		log.Fatalln(err)
+	}
+	builder := userop.NewBuilder(types.ChainIdBase, userop.NewSimpleAccount(account, owner), bundler, client)
+
+	userOpHash, err := cl.DepositUserOp(ctx, builder)
+	if err != nil {
+		log.Fatalln(err)
+	}
+	log.Println("UserOp Hash: ", userOpHash)
+
+	url, err := cl.ClaimUrl()
+	if err != nil {
+		log.Fatalln(err)
+	}
+	log.Println(url)
+}
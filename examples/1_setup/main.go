@@ -3,6 +3,8 @@ package main
 import (
 	"crypto/rand"
 	"github.com/LinkdropHQ/linkdrop-go-sdk"
+	"github.com/LinkdropHQ/linkdrop-go-sdk/types"
+	"github.com/ethereum/go-ethereum/common"
 	"log"
 	"os"
 )
@@ -17,12 +19,34 @@ func getRandomBytes(length int64) []byte {
 }
 
 func main() {
+	// The default chains extended with Base Sepolia, set the escrows deployed to the testnet to enable it
+	chains, err := types.NewChainRegistry(types.DefaultChains()...)
+	if err != nil {
+		log.Fatalln(err)
+	}
+	if os.Getenv("SEPOLIA_ESCROW") != "" {
+		sepolia := types.BaseSepoliaChain(
+			common.HexToAddress(os.Getenv("SEPOLIA_ESCROW")),
+			common.HexToAddress(os.Getenv("SEPOLIA_ESCROW_NFT")),
+			"3.2",
+		)
+		sepolia.ApiPath = "base-sepolia"
+		sepolia.ApiURL = os.Getenv("SEPOLIA_API_URL")
+		if err = chains.Register(sepolia); err != nil {
+			log.Fatalln(err)
+		}
+	}
+
 	sdk, err := linkdrop.Init(
 		"https://p2p.linkdrop.io",
 		os.Getenv("LINKDROP_API_KEY"),
+		linkdrop.WithChainRegistry(chains),
 	)
 	if err != nil {
 		log.Fatalln(err)
 	}
 	log.Println(sdk)
+	for _, chain := range sdk.Chains().Chains() {
+		log.Println(chain.ChainId, chain.Name, chain.EscrowAddress.Hex())
+	}
 }
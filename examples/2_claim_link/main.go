@@ -1,6 +1,7 @@
 package main
 
 import (
+	"context"
 	"crypto/rand"
 	"github.com/LinkdropHQ/linkdrop-go-sdk"
 	"github.com/LinkdropHQ/linkdrop-go-sdk/helpers"
@@ -21,6 +22,8 @@ func getRandomBytes(length int64) []byte {
 }
 
 func main() {
+	ctx := context.Background()
+
 	sdk, err := linkdrop.Init(
 		"https://p2p.linkdrop.io",
 		os.Getenv("LINKDROP_API_KEY"),
@@ -31,6 +34,7 @@ func main() {
 
 	// Native
 	clNative, err := sdk.ClaimLink(
+		ctx,
 		linkdrop.ClaimLinkCreationParams{
 			Token: types.Token{
 				Type:    types.TokenTypeNative,
@@ -53,15 +57,22 @@ func main() {
 	if err != nil {
 		log.Fatalln(err)
 	}
+	usdc := types.Token{
+		Type:    types.TokenTypeERC20,
+		ChainId: types.ChainIdBase,
+		Address: common.HexToAddress("0x833589fcd6edb6e08f4c7c32d4f71b54bda02913"),
+	}
+	// The amount is converted to base units with the token decimals
+	amount, err := sdk.ParseAmount(ctx, usdc, "1000 USDC")
+	if err != nil {
+		log.Fatalln(err)
+	}
 	clERC20WithLinkKey, err := sdk.ClaimLinkWithLinkKey(
+		ctx,
 		linkdrop.ClaimLinkCreationParams{
-			Token: types.Token{
-				Type:    types.TokenTypeERC20,
-				ChainId: types.ChainIdBase,
-				Address: common.HexToAddress("0x833589fcd6edb6e08f4c7c32d4f71b54bda02913"),
-			},
+			Token:      usdc,
 			Sender:     common.HexToAddress(os.Getenv("SENDER_ADDRESS")),
-			Amount:     big.NewInt(1000000000),
+			Amount:     amount,
 			Expiration: 1775195026,
 		},
 		*linkKey,
@@ -70,10 +81,16 @@ func main() {
 		log.Fatalln(err)
 	}
 	log.Println(clERC20WithLinkKey)
+	amounts, err := clERC20WithLinkKey.FormatAmounts(ctx)
+	if err != nil {
+		log.Fatalln(err)
+	}
+	log.Printf("amount: %s, fee: %s, total: %s", amounts.Amount, amounts.Fee, amounts.TotalAmount)
 
 	// ERC20 claim link with transferId
 	transferId := common.HexToAddress("0xcc06431Bcb7E5BDf5632705db6Eb4e98123e3e78")
 	clERC20WithTransferId, err := sdk.ClaimLinkWithTransferId(
+		ctx,
 		linkdrop.ClaimLinkCreationParams{
 			Token: types.Token{
 				Type:    types.TokenTypeERC20,
@@ -1,6 +1,7 @@
 package main
 
 import (
+	"context"
 	"crypto/rand"
 	"github.com/LinkdropHQ/linkdrop-go-sdk"
 	"github.com/LinkdropHQ/linkdrop-go-sdk/types"
@@ -32,6 +33,8 @@ func getRandomBytes(length int64) []byte {
 }
 
 func main() {
+	ctx := context.Background()
+
 	sdk, err := linkdrop.Init(
 		"https://p2p.linkdrop.io",
 		os.Getenv("LINKDROP_API_KEY"),
@@ -43,6 +46,7 @@ func main() {
 
 	// ERC20
 	clERC20, err := sdk.ClaimLink(
+		ctx,
 		linkdrop.ClaimLinkCreationParams{
 			Token: types.Token{
 				Type:    types.TokenTypeERC20,
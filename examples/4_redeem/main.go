@@ -1,6 +1,7 @@
 package main
 
 import (
+	"context"
 	"github.com/LinkdropHQ/linkdrop-go-sdk"
 	"github.com/ethereum/go-ethereum/common"
 	"log"
@@ -8,6 +9,8 @@ import (
 )
 
 func main() {
+	ctx := context.Background()
+
 	sdk, err := linkdrop.Init(
 		"https://p2p.linkdrop.io",
 		os.Getenv("LINKDROP_API_KEY"),
@@ -17,12 +20,12 @@ func main() {
 		log.Fatalln(err)
 	}
 
-	link, err := sdk.GetClaimLink(os.Getenv("LINKDROP_LINK"))
+	link, err := sdk.GetClaimLink(ctx, os.Getenv("LINKDROP_LINK"))
 	if err != nil {
 		log.Fatalln(err)
 	}
 
-	txHash, err := link.Redeem(common.HexToAddress(os.Getenv("RECEIVER_ADDRESS")))
+	txHash, err := link.Redeem(ctx, common.HexToAddress(os.Getenv("RECEIVER_ADDRESS")))
 	if err != nil {
 		log.Fatalln(err)
 	}
@@ -1,14 +1,13 @@
 package main
 
 import (
+	"context"
 	"crypto/rand"
 	"github.com/LinkdropHQ/linkdrop-go-sdk"
 	linkdropCrypto "github.com/LinkdropHQ/linkdrop-go-sdk/crypto"
+	"github.com/LinkdropHQ/linkdrop-go-sdk/signer"
 	"github.com/LinkdropHQ/linkdrop-go-sdk/types"
-	"github.com/LinkdropHQ/linkdrop-go-sdk/utils"
 	"github.com/ethereum/go-ethereum/common"
-	"github.com/ethereum/go-ethereum/crypto"
-	"github.com/ethereum/go-ethereum/signer/core/apitypes"
 	"log"
 	"math/big"
 	"os"
@@ -23,16 +22,9 @@ func getRandomBytes(length int64) []byte {
 	return b
 }
 
-func signTypedData(data apitypes.TypedData) ([]byte, error) {
-	privateKey, err := crypto.ToECDSA(common.Hex2Bytes(os.Getenv("PRIVATE_KEY")))
-	if err != nil {
-		return nil, err
-	}
-
-	return utils.SignTypedData(data, privateKey)
-}
-
 func main() {
+	ctx := context.Background()
+
 	sdk, err := linkdrop.Init(
 		"https://p2p.linkdrop.io",
 		os.Getenv("LINKDROP_API_KEY"),
@@ -42,6 +34,7 @@ func main() {
 	}
 
 	link, err := sdk.ClaimLink(
+		ctx,
 		linkdrop.ClaimLinkCreationParams{
 			Token: types.Token{
 				Type:    types.TokenTypeERC20,
@@ -58,12 +51,17 @@ func main() {
 		log.Fatalln(err)
 	}
 
+	sender, err := signer.NewPrivateKeyFromHex(os.Getenv("PRIVATE_KEY"))
+	if err != nil {
+		log.Fatalln(err)
+	}
 	var nonce [linkdropCrypto.NonceLength]byte
 	copy(nonce[:], getRandomBytes(25))
 	err = link.AddMessage(
+		context.Background(),
 		"Stay Based!",
 		12,
-		signTypedData,
+		sender,
 		nonce,
 	)
 	if err != nil {
@@ -1,6 +1,7 @@
 package main
 
 import (
+	"context"
 	"github.com/LinkdropHQ/linkdrop-go-sdk"
 	"github.com/LinkdropHQ/linkdrop-go-sdk/types"
 	"log"
@@ -8,6 +9,8 @@ import (
 )
 
 func main() {
+	ctx := context.Background()
+
 	sdk, err := linkdrop.Init(
 		"https://p2p.linkdrop.io",
 		os.Getenv("LINKDROP_API_KEY"),
@@ -17,6 +20,7 @@ func main() {
 	}
 
 	limits, err := sdk.GetLimits(
+		ctx,
 		types.Token{
 			Type:    types.TokenTypeNative,
 			ChainId: types.ChainIdBase,
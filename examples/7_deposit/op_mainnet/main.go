@@ -1,12 +1,13 @@
 package main
 
 import (
+	"context"
 	"crypto/rand"
 	"github.com/LinkdropHQ/linkdrop-go-sdk"
+	"github.com/LinkdropHQ/linkdrop-go-sdk/signer"
 	"github.com/LinkdropHQ/linkdrop-go-sdk/types"
 	"github.com/LinkdropHQ/linkdrop-go-sdk/utils"
 	"github.com/ethereum/go-ethereum/common"
-	"github.com/ethereum/go-ethereum/crypto"
 	"github.com/ethereum/go-ethereum/ethclient"
 	"log"
 	"math/big"
@@ -22,19 +23,9 @@ func getRandomBytes(length int64) []byte {
 	return b
 }
 
-func sendTransaction(chainId *big.Int, to common.Address, value *big.Int, data []byte) (*types.Transaction, error) {
-	client, err := ethclient.Dial(os.Getenv("RPC_URL"))
-	if err != nil {
-		log.Fatalf("Failed to connect to Ethereum client: %v", err)
-	}
-	privateKey, err := crypto.ToECDSA(common.Hex2Bytes(os.Getenv("PRIVATE_KEY")))
-	if err != nil {
-		return nil, err
-	}
-	return utils.SendTransaction(chainId, to, value, data, client, privateKey)
-}
-
 func main() {
+	ctx := context.Background()
+
 	sdk, err := linkdrop.Init(
 		"https://p2p.linkdrop.io",
 		os.Getenv("LINKDROP_API_KEY"),
@@ -53,6 +44,7 @@ func main() {
 
 	// ERC20
 	clERC20, err := sdk.ClaimLink(
+		ctx,
 		linkdrop.ClaimLinkCreationParams{
 			Token: types.Token{
 				Type:    types.TokenTypeERC20,
@@ -76,7 +68,15 @@ func main() {
 	}
 	log.Println(url) // The link is valid, but can't be claimed since assets are were deposited
 
-	txHash, err := clERC20.Deposit(sendTransaction)
+	client, err := ethclient.Dial(os.Getenv("RPC_URL"))
+	if err != nil {
+		log.Fatalf("Failed to connect to Ethereum client: %v", err)
+	}
+	sender, err := signer.NewPrivateKeyFromHex(os.Getenv("PRIVATE_KEY"))
+	if err != nil {
+		log.Fatalln(err)
+	}
+	txHash, err := clERC20.DepositWithApproval(ctx, sender.WithBackend(types.ChainIdOptimism, client), client)
 	if err != nil {
 		log.Fatalln(err)
 	}
@@ -1,26 +1,16 @@
 package main
 
 import (
+	"context"
 	"crypto/rand"
 	"github.com/LinkdropHQ/linkdrop-go-sdk"
+	"github.com/LinkdropHQ/linkdrop-go-sdk/signer"
 	"github.com/LinkdropHQ/linkdrop-go-sdk/types"
-	"github.com/LinkdropHQ/linkdrop-go-sdk/utils"
 	"github.com/ethereum/go-ethereum/common"
-	"github.com/ethereum/go-ethereum/crypto"
-	"github.com/ethereum/go-ethereum/signer/core/apitypes"
 	"log"
 	"os"
 )
 
-func signTypedData(data apitypes.TypedData) ([]byte, error) {
-	privateKey, err := crypto.ToECDSA(common.Hex2Bytes(os.Getenv("PRIVATE_KEY")))
-	if err != nil {
-		return nil, err
-	}
-
-	return utils.SignTypedData(data, privateKey)
-}
-
 func getRandomBytes(length int64) []byte {
 	b := make([]byte, length)
 	_, err := rand.Read(b)
@@ -56,9 +46,25 @@ func main() {
 		log.Fatalln(err)
 	}
 	log.Println(clRecovered)
+	sender, err := signer.NewPrivateKeyFromHex(os.Getenv("PRIVATE_KEY"))
+	if err != nil {
+		log.Fatalln(err)
+	}
+	// The message key is derived from the sender's signature, so the new URL can decrypt the original message
+	if os.Getenv("WITH_MESSAGE") != "" {
+		err = clRecovered.LoadSenderMessage(context.Background())
+		if err != nil {
+			log.Fatalln(err)
+		}
+		err = clRecovered.AddMessageKey(context.Background(), 0, sender)
+		if err != nil {
+			log.Fatalln(err)
+		}
+	}
 	url, err := clRecovered.GenerateClaimUrl(
+		context.Background(),
 		getRandomBytes,
-		signTypedData,
+		sender,
 	)
 	if err != nil {
 		log.Fatalln(err)
@@ -1,6 +1,7 @@
 package main
 
 import (
+	"context"
 	"encoding/json"
 	"fmt"
 	"github.com/LinkdropHQ/linkdrop-go-sdk"
@@ -23,10 +24,13 @@ type Payload struct {
 		Amount     string         `json:"amount"`
 		Expiration int64          `json:"expiration"`
 	} `json:"claimLink"`
-	TxHash string `json:"txHash"`
+	TxHash   string                      `json:"txHash"`
+	Snapshot *linkdrop.ClaimLinkSnapshot `json:"snapshot"` // Snapshot - the link state returned by getDepositParams
 }
 
 func main() {
+	ctx := context.Background()
+
 	command := os.Args[1]
 	var payload Payload
 	err := json.Unmarshal([]byte(os.Args[2]), &payload)
@@ -42,35 +46,50 @@ func main() {
 		log.Fatalln(err)
 	}
 
-	//// ERC20
-	amount, _ := new(big.Int).SetString(payload.ClaimLink.Amount, 10)
-	claimLink, err := sdk.ClaimLinkWithTransferId(
-		linkdrop.ClaimLinkCreationParams{
-			Token: types.Token{
-				Type:    payload.ClaimLink.Token.Type,
-				ChainId: payload.ClaimLink.Token.ChainId,
-				Address: payload.ClaimLink.Token.Address,
-			},
-			Sender:     payload.ClaimLink.Sender,
-			Amount:     amount,
-			Expiration: payload.ClaimLink.Expiration,
-		},
-		payload.TransferId,
-	)
-	if err != nil {
-		log.Fatalln(err)
-	}
-
 	switch command {
 	case "getDepositParams":
+		//// ERC20
+		amount, _ := new(big.Int).SetString(payload.ClaimLink.Amount, 10)
+		claimLink, err := sdk.ClaimLinkWithTransferId(
+			ctx,
+			linkdrop.ClaimLinkCreationParams{
+				Token: types.Token{
+					Type:    payload.ClaimLink.Token.Type,
+					ChainId: payload.ClaimLink.Token.ChainId,
+					Address: payload.ClaimLink.Token.Address,
+				},
+				Sender:     payload.ClaimLink.Sender,
+				Amount:     amount,
+				Expiration: payload.ClaimLink.Expiration,
+			},
+			payload.TransferId,
+		)
+		if err != nil {
+			log.Fatalln(err)
+		}
 		params, err := claimLink.GetDepositParams()
 		if err != nil {
 			log.Fatalln(err)
 		}
-		resp, _ := json.Marshal(params)
+		// The snapshot keeps the fee fetched above, so the link doesn't have to be re-created on registration
+		snapshot, err := claimLink.Snapshot()
+		if err != nil {
+			log.Fatalln(err)
+		}
+		resp, _ := json.Marshal(map[string]any{
+			"params":   params,
+			"snapshot": snapshot,
+		})
 		fmt.Println(string(resp))
 	case "registerDeposit":
-		err = claimLink.DepositRegister(types.Transaction{
+		if payload.Snapshot == nil {
+			log.Fatalln("snapshot is required")
+		}
+		claimLink, err := sdk.RestoreClaimLink(payload.Snapshot)
+		if err != nil {
+			log.Fatalln(err)
+		}
+		err = claimLink.DepositRegister(ctx, types.Transaction{
 			Hash: common.HexToHash(payload.TxHash),
 			Type: types.TransactionTypeTx,
 		})
@@ -1,6 +1,7 @@
 package main
 
 import (
+	"context"
 	"fmt"
 	"github.com/LinkdropHQ/linkdrop-go-sdk"
 	"github.com/LinkdropHQ/linkdrop-go-sdk/types"
@@ -13,6 +14,8 @@ import (
 )
 
 func main() {
+	ctx := context.Background()
+
 	linkKey, err := crypto.HexToECDSA(strings.TrimPrefix(os.Args[1], "0x"))
 
 	sdk, err := linkdrop.Init(
@@ -25,6 +28,7 @@ func main() {
 
 	// ERC20
 	clERC20, err := sdk.ClaimLinkWithLinkKey(
+		ctx,
 		linkdrop.ClaimLinkCreationParams{
 			Token: types.Token{
 				Type:    types.TokenTypeERC20,
@@ -0,0 +1,128 @@
+package helpers
+
+import (
+	"errors"
+	"github.com/LinkdropHQ/linkdrop-go-sdk/constants"
+	"github.com/ethereum/go-ethereum/accounts/abi"
+	"github.com/ethereum/go-ethereum/common"
+	"github.com/ethereum/go-ethereum/common/hexutil"
+	"github.com/ethereum/go-ethereum/crypto"
+	"github.com/ethereum/go-ethereum/signer/core/apitypes"
+	"math/big"
+)
+
+// AuthorizationNonce returns a unique EIP-3009 nonce for the transfer
+func AuthorizationNonce(transferId common.Address) [32]byte {
+	return crypto.Keccak256Hash(transferId.Bytes())
+}
+
+// AuthorizationTypedData builds EIP-3009 typed data to be signed by the token owner
+//
+// Parameters:
+// - domain: EIP-712 domain of the token, see DefineDomain.
+// - selector: The authorization method of the token, see constants.SupportedStableCoins.
+// - from: The token owner.
+// - to: The spender (receiver of the authorization), the escrow contract.
+// - value: The authorized amount.
+// - validAfter, validBefore: The authorization validity window (unix timestamps).
+// - nonce: The unique authorization nonce, see AuthorizationNonce.
+func AuthorizationTypedData(
+	domain apitypes.TypedDataDomain,
+	selector constants.Selector,
+	from common.Address,
+	to common.Address,
+	value *big.Int,
+	validAfter *big.Int,
+	validBefore *big.Int,
+	nonce [32]byte,
+) (typedData apitypes.TypedData, err error) {
+	var primaryType, fromField, toField string
+	switch selector {
+	case constants.SelectorReceiveWithAuthorization, constants.SelectorReceiveWithAuthorizationEOA:
+		primaryType, fromField, toField = "ReceiveWithAuthorization", "from", "to"
+	case constants.SelectorApproveWithAuthorization:
+		primaryType, fromField, toField = "ApproveWithAuthorization", "owner", "spender"
+	default:
+		return typedData, errors.New("unsupported authorization selector")
+	}
+
+	if domain.Salt != "" {
+		// the chain is encoded in the salt for such domains
+		domain.ChainId = nil
+	}
+
+	return apitypes.TypedData{
+		Domain:      domain,
+		PrimaryType: primaryType,
+		Types: apitypes.Types{
+			"EIP712Domain": DomainTypes(domain),
+			primaryType: {
+				{Name: fromField, Type: "address"},
+				{Name: toField, Type: "address"},
+				{Name: "value", Type: "uint256"},
+				{Name: "validAfter", Type: "uint256"},
+				{Name: "validBefore", Type: "uint256"},
+				{Name: "nonce", Type: "bytes32"},
+			},
+		},
+		Message: apitypes.TypedDataMessage{
+			fromField:     from.Hex(),
+			toField:       to.Hex(),
+			"value":       value.String(),
+			"validAfter":  validAfter.String(),
+			"validBefore": validBefore.String(),
+			"nonce":       hexutil.Encode(nonce[:]),
+		},
+	}, nil
+}
+
+// EncodeAuthorization ABI-encodes the signed authorization in the format expected by the escrow contract
+// Authorizations with SelectorReceiveWithAuthorization carry the signature as bytes, other selectors use (v, r, s)
+func EncodeAuthorization(
+	selector constants.Selector,
+	from common.Address,
+	to common.Address,
+	value *big.Int,
+	validAfter *big.Int,
+	validBefore *big.Int,
+	nonce [32]byte,
+	signature []byte,
+) ([]byte, error) {
+	if len(signature) != 65 {
+		return nil, errors.New("invalid signature length")
+	}
+	signature = append([]byte{}, signature...)
+	if signature[64] < 27 {
+		signature[64] += 27
+	}
+	addressT, _ := abi.NewType("address", "", nil)
+	uint256T, _ := abi.NewType("uint256", "", nil)
+	bytes32T, _ := abi.NewType("bytes32", "", nil)
+	arguments := abi.Arguments{
+		{Type: addressT},
+		{Type: addressT},
+		{Type: uint256T},
+		{Type: uint256T},
+		{Type: uint256T},
+		{Type: bytes32T},
+	}
+	values := []any{from, to, value, validAfter, validBefore, nonce}
+
+	switch selector {
+	case constants.SelectorReceiveWithAuthorization:
+		bytesT, _ := abi.NewType("bytes", "", nil)
+		arguments = append(arguments, abi.Argument{Type: bytesT})
+		values = append(values, signature)
+	case constants.SelectorReceiveWithAuthorizationEOA, constants.SelectorApproveWithAuthorization:
+		uint8T, _ := abi.NewType("uint8", "", nil)
+		arguments = append(arguments, abi.Argument{Type: uint8T}, abi.Argument{Type: bytes32T}, abi.Argument{Type: bytes32T})
+		v := signature[64]
+		var r, s [32]byte
+		copy(r[:], signature[:32])
+		copy(s[:], signature[32:64])
+		values = append(values, v, r, s)
+	default:
+		return nil, errors.New("unsupported authorization selector")
+	}
+	return arguments.Pack(values...)
+}
@@ -0,0 +1,200 @@
+package helpers
+
+import (
+	"bytes"
+	"github.com/LinkdropHQ/linkdrop-go-sdk/constants"
+	"github.com/ethereum/go-ethereum/common"
+	"github.com/ethereum/go-ethereum/common/hexutil"
+	"github.com/ethereum/go-ethereum/crypto"
+	"github.com/ethereum/go-ethereum/signer/core/apitypes"
+	"math/big"
+	"strings"
+	"testing"
+)
+
+var (
+	testAuthorizationFrom   = common.HexToAddress("0x0000000000000000000000000000000000000004")
+	testAuthorizationNonce  = AuthorizationNonce(common.HexToAddress("0x0000000000000000000000000000000000000003"))
+	testEscrowBase          = common.HexToAddress("0x5badb0143f69015c5c86cbd9373474a9c8ab713b")
+	testEscrowPolygon       = common.HexToAddress("0xbe7b40eb3a9d85d3a76142cb637ab824f0d35ead")
+	testAuthorizationEncode = []string{
+		"0000000000000000000000000000000000000000000000000000000000000004",
+		"0000000000000000000000005badb0143f69015c5c86cbd9373474a9c8ab713b",
+		"00000000000000000000000000000000000000000000000000000000000f4628",
+		"0000000000000000000000000000000000000000000000000000000000000000",
+		"000000000000000000000000000000000000000000000000000000006553f100",
+		"5b70e80538acdabd6137353b0f9d8d149f4dba91e8be2e7946e409bfdbe685b9",
+	}
+)
+
+func TestAuthorizationNonce(t *testing.T) {
+	want := common.HexToHash("0x5b70e80538acdabd6137353b0f9d8d149f4dba91e8be2e7946e409bfdbe685b9")
+	if common.Hash(testAuthorizationNonce) != want {
+		t.Fatalf("got %s, want %s", common.Hash(testAuthorizationNonce), want)
+	}
+}
+
+func TestAuthorizationSelectors(t *testing.T) {
+	tests := map[constants.Selector]string{
+		constants.SelectorReceiveWithAuthorization:    "receiveWithAuthorization(address,address,uint256,uint256,uint256,bytes32,bytes)",
+		constants.SelectorReceiveWithAuthorizationEOA: "receiveWithAuthorization(address,address,uint256,uint256,uint256,bytes32,uint8,bytes32,bytes32)",
+		constants.SelectorApproveWithAuthorization:    "approveWithAuthorization(address,address,uint256,uint256,uint256,bytes32,uint8,bytes32,bytes32)",
+	}
+	for selector, method := range tests {
+		if got := hexutil.Encode(crypto.Keccak256([]byte(method))[:4]); got != string(selector) {
+			t.Fatalf("%s selector is %s, want %s", method, got, selector)
+		}
+	}
+}
+
+// TestAuthorizationTypedData checks the typed data hashes against the EIP-712 hashes computed independently
+func TestAuthorizationTypedData(t *testing.T) {
+	tests := []struct {
+		name        string
+		domain      apitypes.TypedDataDomain
+		selector    constants.Selector
+		to          common.Address
+		wantPrimary string
+		wantHash    common.Hash
+		wantErr     bool
+	}{
+		{
+			name:        "receiveWithAuthorization with version domain",
+			domain:      constants.DomainUsdcBase,
+			selector:    constants.SelectorReceiveWithAuthorization,
+			to:          testEscrowBase,
+			wantPrimary: "ReceiveWithAuthorization",
+			wantHash:    common.HexToHash("0xdb7680a4aaa5ed4fa9a986fa367d148cccc19d8637096354363ffb7d93be488e"),
+		},
+		{
+			name:        "EOA receiveWithAuthorization with version domain",
+			domain:      constants.DomainUsdcBase,
+			selector:    constants.SelectorReceiveWithAuthorizationEOA,
+			to:          testEscrowBase,
+			wantPrimary: "ReceiveWithAuthorization",
+			wantHash:    common.HexToHash("0xdb7680a4aaa5ed4fa9a986fa367d148cccc19d8637096354363ffb7d93be488e"),
+		},
+		{
+			name:        "approveWithAuthorization with version domain",
+			domain:      constants.DomainUsdcBase,
+			selector:    constants.SelectorApproveWithAuthorization,
+			to:          testEscrowBase,
+			wantPrimary: "ApproveWithAuthorization",
+			wantHash:    common.HexToHash("0x7d05c14ef8cc81739d1c8f5fb1f6fb13009270eab3734cff4a991379d433f6a1"),
+		},
+		{
+			name:        "approveWithAuthorization with salt domain",
+			domain:      constants.DomainUsdcBridgedPolygon,
+			selector:    constants.SelectorApproveWithAuthorization,
+			to:          testEscrowPolygon,
+			wantPrimary: "ApproveWithAuthorization",
+			wantHash:    common.HexToHash("0x6d51e131b993c18162d60fa1992a23deedbf886958a487dcc930c03ad009ccad"),
+		},
+		{name: "undefined selector", domain: constants.DomainUsdcBase, selector: constants.SelectorUndefined, wantErr: true},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			typedData, err := AuthorizationTypedData(
+				tt.domain,
+				tt.selector,
+				testAuthorizationFrom,
+				tt.to,
+				big.NewInt(1001000),
+				big.NewInt(0),
+				big.NewInt(1700000000),
+				testAuthorizationNonce,
+			)
+			if tt.wantErr {
+				if err == nil {
+					t.Fatal("expected error")
+				}
+				return
+			}
+			if err != nil {
+				t.Fatal(err)
+			}
+			if typedData.PrimaryType != tt.wantPrimary {
+				t.Fatalf("got primary type %s, want %s", typedData.PrimaryType, tt.wantPrimary)
+			}
+			hash, _, err := apitypes.TypedDataAndHash(typedData)
+			if err != nil {
+				t.Fatal(err)
+			}
+			if common.BytesToHash(hash) != tt.wantHash {
+				t.Fatalf("got %x, want %s", hash, tt.wantHash)
+			}
+		})
+	}
+}
+
+// TestEncodeAuthorization checks the encoded authorizations against the ABI encodings computed independently
+func TestEncodeAuthorization(t *testing.T) {
+	r := strings.Repeat("11", 32)
+	s := strings.Repeat("22", 32)
+	// v is 0 to check it's normalized to 27
+	signature := common.FromHex(r + s + "00")
+	tests := []struct {
+		name      string
+		selector  constants.Selector
+		signature []byte
+		want      []string // want - the words following the common authorization fields
+		wantErr   bool
+	}{
+		{
+			name:      "bytes signature",
+			selector:  constants.SelectorReceiveWithAuthorization,
+			signature: signature,
+			want: []string{
+				"00000000000000000000000000000000000000000000000000000000000000e0",
+				"0000000000000000000000000000000000000000000000000000000000000041",
+				r,
+				s,
+				"1b00000000000000000000000000000000000000000000000000000000000000",
+			},
+		},
+		{
+			name:      "EOA receiveWithAuthorization (v, r, s) signature",
+			selector:  constants.SelectorReceiveWithAuthorizationEOA,
+			signature: signature,
+			want:      []string{"000000000000000000000000000000000000000000000000000000000000001b", r, s},
+		},
+		{
+			name:      "approveWithAuthorization (v, r, s) signature",
+			selector:  constants.SelectorApproveWithAuthorization,
+			signature: signature,
+			want:      []string{"000000000000000000000000000000000000000000000000000000000000001b", r, s},
+		},
+		{name: "undefined selector", selector: constants.SelectorUndefined, signature: signature, wantErr: true},
+		{name: "short signature", selector: constants.SelectorReceiveWithAuthorization, signature: signature[:64], wantErr: true},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			encoded, err := EncodeAuthorization(
+				tt.selector,
+				testAuthorizationFrom,
+				testEscrowBase,
+				big.NewInt(1001000),
+				big.NewInt(0),
+				big.NewInt(1700000000),
+				testAuthorizationNonce,
+				tt.signature,
+			)
+			if tt.wantErr {
+				if err == nil {
+					t.Fatal("expected error")
+				}
+				return
+			}
+			if err != nil {
+				t.Fatal(err)
+			}
+			want := common.FromHex(strings.Join(append(append([]string{}, testAuthorizationEncode...), tt.want...), ""))
+			if !bytes.Equal(encoded, want) {
+				t.Fatalf("got %x, want %x", encoded, want)
+			}
+			if tt.signature[64] != 0 {
+				t.Fatal("signature is modified")
+			}
+		})
+	}
+}
@@ -0,0 +1,32 @@
+package helpers
+
+import (
+	"context"
+	"github.com/ethereum/go-ethereum"
+	"github.com/ethereum/go-ethereum/accounts/abi"
+	"github.com/ethereum/go-ethereum/accounts/abi/bind"
+	"github.com/ethereum/go-ethereum/common"
+)
+
+// CallContract calls a view method of the contract at the latest block and returns the unpacked outputs
+func CallContract(
+	ctx context.Context,
+	caller bind.ContractCaller,
+	contractAbi abi.ABI,
+	contract common.Address,
+	method string,
+	args ...any,
+) ([]any, error) {
+	data, err := contractAbi.Pack(method, args...)
+	if err != nil {
+		return nil, err
+	}
+	output, err := caller.CallContract(ctx, ethereum.CallMsg{
+		To:   &contract,
+		Data: data,
+	}, nil)
+	if err != nil {
+		return nil, err
+	}
+	return contractAbi.Unpack(method, output)
+}
@@ -1,21 +1,12 @@
 package helpers
 
 import (
-	"errors"
+	"github.com/LinkdropHQ/linkdrop-go-sdk/types"
 )
 
+// DefineApiHost returns the API URL of the chain of types.DefaultChains.
+//
+// Deprecated: the chains registered with linkdrop.WithChainRegistry are not resolved, use sdk.Chains().ApiHost
 func DefineApiHost(apiUrl string, chainId int64) (string, error) {
-	switch chainId {
-	case 137:
-		return apiUrl + "/polygon", nil
-	case 8453:
-		return apiUrl + "/base", nil
-	case 42161:
-		return apiUrl + "/arbitrum", nil
-	case 10:
-		return apiUrl + "/optimism", nil
-	case 43114:
-		return apiUrl + "/avalanche", nil
-	}
-	return "", errors.New("unsupported chain")
+	return types.DefaultChainRegistry().ApiHost(apiUrl, types.ChainId(chainId))
 }
@@ -2,26 +2,46 @@ package helpers
 
 import (
 	"errors"
-	"github.com/LinkdropHQ/linkdrop-go-sdk/constants"
 	"github.com/LinkdropHQ/linkdrop-go-sdk/types"
 	"github.com/ethereum/go-ethereum/signer/core/apitypes"
+	"math/big"
 )
 
+// DefineDomain returns EIP-712 domain of the stable coin of types.DefaultChains.
+//
+// Deprecated: the chains registered with linkdrop.WithChainRegistry are not resolved, use DefineStableCoinDomain
 func DefineDomain(token types.Token) (apitypes.TypedDataDomain, error) {
-	chainID := token.ChainId
-	address := token.Address
+	return DefineStableCoinDomain(token, types.DefaultChainRegistry())
+}
 
-	switch chainID {
-	case types.ChainIdBase: // Base
-		if address == constants.TAUsdcBase {
-			return constants.DomainUsdcBase, nil
-		}
-		if address == constants.TAEurcBase {
-			return constants.DomainEurcBase, nil
-		}
-		if address == constants.TACbBtcBase {
-			return constants.DomainCbBtcBase, nil
+// DefineStableCoinDomain returns EIP-712 domain of the stable coin registered in chains
+func DefineStableCoinDomain(token types.Token, chains *types.ChainRegistry) (apitypes.TypedDataDomain, error) {
+	stableCoin, ok := chains.StableCoin(token)
+	if !ok {
+		return apitypes.TypedDataDomain{}, errors.New("domain not found")
+	}
+	domain := stableCoin.Domain
+	if domain.ChainId == nil || (*big.Int)(domain.ChainId).Int64() != int64(token.ChainId) {
+		return apitypes.TypedDataDomain{}, errors.New("domain not found for chain")
+	}
+	return domain, nil
+}
+
+// DomainTypes returns EIP712Domain type matching the domain fields.
+// NOTE: domains with salt (e.g. bridged USDC on Polygon) don't include chainId in the type
+func DomainTypes(domain apitypes.TypedDataDomain) []apitypes.Type {
+	if domain.Salt != "" {
+		return []apitypes.Type{
+			{Name: "name", Type: "string"},
+			{Name: "version", Type: "string"},
+			{Name: "verifyingContract", Type: "address"},
+			{Name: "salt", Type: "bytes32"},
 		}
 	}
-	return apitypes.TypedDataDomain{}, errors.New("domain not found")
+	return []apitypes.Type{
+		{Name: "name", Type: "string"},
+		{Name: "version", Type: "string"},
+		{Name: "chainId", Type: "uint256"},
+		{Name: "verifyingContract", Type: "address"},
+	}
 }
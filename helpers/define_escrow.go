@@ -18,34 +18,20 @@ func DefineEscrowVersion(address common.Address) (version string, err error) {
 	return "", fmt.Errorf("address not found in escrows")
 }
 
+// EscrowAddressByChain returns the escrows of the chain of types.DefaultChains.
+//
+// Deprecated: the chains registered with linkdrop.WithChainRegistry are not resolved, use sdk.Chains().EscrowAddresses
 func EscrowAddressByChain(
 	chain types.ChainId,
 ) (escrow common.Address, escrowNFT common.Address, err error) {
-	switch chain {
-	case types.ChainIdBase:
-		escrow = constants.Escrows["3.2"][0]
-		escrowNFT = constants.Escrows["3.2"][1]
-	case types.ChainIdPolygon, types.ChainIdAvalanche, types.ChainIdOptimism, types.ChainIdArbitrum:
-		escrow = constants.Escrows["3.2"][2]
-		escrowNFT = constants.Escrows["3.2"][3]
-	default:
-		err = fmt.Errorf("chain not supported")
-	}
-	return
+	return types.DefaultChainRegistry().EscrowAddresses(chain)
 }
 
+// EscrowAddressByToken returns the escrow of the token on the chain of types.DefaultChains.
+//
+// Deprecated: the chains registered with linkdrop.WithChainRegistry are not resolved, use sdk.Chains().EscrowAddressByToken
 func EscrowAddressByToken(
 	token types.Token,
 ) (escrowAddress common.Address, err error) {
-	escrow, escrowNFT, err := EscrowAddressByChain(token.ChainId)
-	if err != nil {
-		return
-	}
-	switch token.Type {
-	case types.TokenTypeERC1155, types.TokenTypeERC721:
-		escrowAddress = escrowNFT
-	default:
-		escrowAddress = escrow
-	}
-	return
+	return types.DefaultChainRegistry().EscrowAddressByToken(token)
 }
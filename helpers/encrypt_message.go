@@ -1,6 +1,7 @@
 package helpers
 
 import (
+	"context"
 	"crypto/sha256"
 	"encoding/binary"
 	"errors"
@@ -18,6 +19,9 @@ func MessageDecrypt(
 	if message == nil {
 		return "", errors.New("message is nil")
 	}
+	if len(message.Data) < 2 {
+		return "", errors.New("message data is not set")
+	}
 	encryptionKey, err := message.LinkKey.MessageEncryptionKey()
 	if err != nil {
 		return "", err
@@ -57,11 +61,12 @@ func MessageEncrypt(
 // This function returns initial key which is passed as link parameter
 // Use MessageInitialKey.EncryptionKey() to retrieve Encryption Key from Initial Key
 func MessageInitialKeyCreate(
+	ctx context.Context,
 	transferId common.Address,
 	chainId types.ChainId,
-	signTypedData types.SignTypedDataCallback,
+	signer types.TypedDataSigner,
 ) (initialKey types.MessageInitialKey, err error) {
-	signature, err := signTypedData(MessageInitialKeyTypedData(transferId, chainId))
+	signature, err := signer.SignTypedData(ctx, MessageInitialKeyTypedData(transferId, chainId))
 	if err != nil {
 		return
 	}
@@ -1,6 +1,8 @@
 package helpers
 
 import (
+	"crypto/ecdsa"
+	"github.com/ethereum/go-ethereum/crypto"
 	"net/url"
 	"strings"
 )
@@ -15,7 +17,12 @@ func GetClaimCodeFromDashboardLink(claimUrl string) string {
 		if err != nil {
 			return ""
 		}
-		queryParams := parsedUrl.Query()
-		return queryParams.Get("k")
+		return claimUrlQuery(parsedUrl).Get("k")
 	}
 }
+
+// DashboardLinkKey derives the link key from the dashboard claim code
+// The link key is used to sign the receiver address, its address is the link identifier
+func DashboardLinkKey(claimCode string) (*ecdsa.PrivateKey, error) {
+	return crypto.ToECDSA(crypto.Keccak256([]byte(claimCode)))
+}
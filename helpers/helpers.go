@@ -5,13 +5,21 @@ import (
 	"math/big"
 )
 
+// DefineValue returns the native currency amount to be sent with the deposit transaction
 func DefineValue(
 	token types.Token,
 	fee types.ClaimLinkFee,
 	totalAmount *big.Int,
 ) (*big.Int, error) {
-	if token.Type == types.TokenTypeNative {
+	switch token.Type {
+	case types.TokenTypeNative:
 		return totalAmount, nil
+	case types.TokenTypeERC721, types.TokenTypeERC1155:
+		// NFT escrow charges the fee in the native currency only
+		if fee.Token.Address == types.ZeroAddress && fee.Amount != nil {
+			return fee.Amount, nil
+		}
+		return big.NewInt(0), nil
 	}
 	if fee.Token.Address == token.Address {
 		return big.NewInt(0), nil
@@ -0,0 +1,44 @@
+package helpers
+
+import (
+	"github.com/LinkdropHQ/linkdrop-go-sdk/constants"
+	"github.com/LinkdropHQ/linkdrop-go-sdk/types"
+	"github.com/ethereum/go-ethereum/common"
+	"math/big"
+	"testing"
+)
+
+func TestDefineValue(t *testing.T) {
+	native := types.Token{Type: types.TokenTypeNative, ChainId: types.ChainIdBase}
+	usdc := types.Token{Type: types.TokenTypeERC20, ChainId: types.ChainIdBase, Address: constants.TAUsdcBase}
+	eurc := types.Token{Type: types.TokenTypeERC20, ChainId: types.ChainIdBase, Address: constants.TAEurcBase}
+	nft := common.HexToAddress("0x0000000000000000000000000000000000000721")
+	erc721 := types.Token{Type: types.TokenTypeERC721, ChainId: types.ChainIdBase, Address: nft, Id: big.NewInt(7)}
+	erc1155 := types.Token{Type: types.TokenTypeERC1155, ChainId: types.ChainIdBase, Address: nft, Id: big.NewInt(7)}
+	tests := []struct {
+		name  string
+		token types.Token
+		fee   types.ClaimLinkFee
+		want  int64
+	}{
+		{name: "native", token: native, fee: types.ClaimLinkFee{Token: native, Amount: big.NewInt(1000)}, want: 1001000},
+		{name: "ERC20 with the fee in the token", token: usdc, fee: types.ClaimLinkFee{Token: usdc, Amount: big.NewInt(1000)}, want: 0},
+		{name: "ERC20 with the native fee", token: usdc, fee: types.ClaimLinkFee{Token: native, Amount: big.NewInt(1000)}, want: 1000},
+		{name: "ERC20 with the fee in another token", token: usdc, fee: types.ClaimLinkFee{Token: eurc, Amount: big.NewInt(1000)}, want: 1000},
+		{name: "ERC721 with the native fee", token: erc721, fee: types.ClaimLinkFee{Token: native, Amount: big.NewInt(1000)}, want: 1000},
+		{name: "ERC1155 with the native fee", token: erc1155, fee: types.ClaimLinkFee{Token: native, Amount: big.NewInt(1000)}, want: 1000},
+		{name: "ERC1155 with the ERC20 fee", token: erc1155, fee: types.ClaimLinkFee{Token: usdc, Amount: big.NewInt(1000)}, want: 0},
+		{name: "ERC721 without the fee", token: erc721, fee: types.ClaimLinkFee{Token: native}, want: 0},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			value, err := DefineValue(tt.token, tt.fee, big.NewInt(1001000))
+			if err != nil {
+				t.Fatal(err)
+			}
+			if value.Int64() != tt.want {
+				t.Fatalf("got %s, want %d", value, tt.want)
+			}
+		})
+	}
+}
@@ -2,12 +2,24 @@ package helpers
 
 import (
 	"bytes"
+	"context"
 	"fmt"
 	"io"
 	"net/http"
 	"net/url"
 )
 
+// StatusError is returned by Request when the response status code is not 200
+type StatusError struct {
+	StatusCode int
+	Header     http.Header
+	Body       []byte
+}
+
+func (e *StatusError) Error() string {
+	return fmt.Sprintf("unexpected status code: %d, response body: %s", e.StatusCode, string(e.Body))
+}
+
 func DefineHeaders(apiKey string) http.Header {
 	headers := http.Header{}
 	headers.Set("Content-Type", "application/json")
@@ -23,9 +35,17 @@ func CreateQueryString(params map[string]string) string {
 	return query.Encode()
 }
 
-func Request(url string, method string, headers http.Header, body []byte) ([]byte, error) {
-	client := &http.Client{}
-	req, err := http.NewRequest(method, url, bytes.NewBuffer(body))
+// Request performs an HTTP request bound to ctx using the provided client and returns the response body
+// NOTE: the request is aborted as soon as ctx is cancelled or its deadline is exceeded
+func Request(
+	ctx context.Context,
+	client *http.Client,
+	url string,
+	method string,
+	headers http.Header,
+	body []byte,
+) ([]byte, error) {
+	req, err := http.NewRequestWithContext(ctx, method, url, bytes.NewBuffer(body))
 	if err != nil {
 		return nil, err
 	}
@@ -43,7 +63,11 @@ func Request(url string, method string, headers http.Header, body []byte) ([]byt
 	}
 
 	if resp.StatusCode != 200 {
-		return nil, fmt.Errorf("unexpected status code: %d, response body: %s", resp.StatusCode, string(respBody))
+		return nil, &StatusError{
+			StatusCode: resp.StatusCode,
+			Header:     resp.Header,
+			Body:       respBody,
+		}
 	}
 
 	return respBody, nil
@@ -3,6 +3,7 @@ package helpers
 import (
 	"github.com/LinkdropHQ/linkdrop-go-sdk/types"
 	"net/url"
+	"strings"
 )
 
 func LinkSourceFromClaimUrl(claimUrl string) (types.LinkSource, error) {
@@ -10,9 +11,24 @@ func LinkSourceFromClaimUrl(claimUrl string) (types.LinkSource, error) {
 	if err != nil {
 		return types.LinkSourceUndefined, err
 	}
-	src := types.LinkSource(parsedUrl.Query().Get("src"))
+	src := types.LinkSource(strings.ToUpper(claimUrlQuery(parsedUrl).Get("src")))
 	if src == types.LinkSourceUndefined {
 		return types.LinkSourceP2P, nil
 	}
 	return src, nil
 }
+
+// claimUrlQuery returns query parameters of the claim URL.
+// Parameters of hash-routed URLs (e.g. https://p2p.linkdrop.io/#/code?k=...) are taken from the fragment.
+func claimUrlQuery(parsedUrl *url.URL) url.Values {
+	query := parsedUrl.Query()
+	if _, fragmentQuery, found := strings.Cut(parsedUrl.Fragment, "?"); found {
+		fragmentValues, err := url.ParseQuery(fragmentQuery)
+		if err == nil {
+			for key, values := range fragmentValues {
+				query[key] = values
+			}
+		}
+	}
+	return query
+}
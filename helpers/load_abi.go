@@ -5,6 +5,7 @@ import (
 	"github.com/LinkdropHQ/linkdrop-go-sdk/constants"
 	"github.com/ethereum/go-ethereum/accounts/abi"
 	"strings"
+	"sync"
 )
 
 //go:embed abi/LinkdropEscrowNFT.json
@@ -13,7 +14,33 @@ var escrowNFTJson []byte
 //go:embed abi/LinkdropEscrowToken.json
 var escrowTokenJson []byte
 
-func LoadABI() (err error) {
+//go:embed abi/ERC20.json
+var erc20Json []byte
+
+//go:embed abi/ERC721.json
+var erc721Json []byte
+
+//go:embed abi/ERC1155.json
+var erc1155Json []byte
+
+//go:embed abi/EntryPoint.json
+var entryPointJson []byte
+
+//go:embed abi/SimpleAccount.json
+var simpleAccountJson []byte
+
+var loadABIOnce sync.Once
+var loadABIErr error
+
+// LoadABI parses the embedded ABIs into constants. It's safe to call it multiple times, the ABIs are parsed once.
+func LoadABI() error {
+	loadABIOnce.Do(func() {
+		loadABIErr = loadABI()
+	})
+	return loadABIErr
+}
+
+func loadABI() (err error) {
 	abiRaw := strings.NewReader(string(escrowNFTJson))
 	constants.EscrowNFTAbi, err = abi.JSON(abiRaw)
 	if err != nil {
@@ -25,5 +52,30 @@ func LoadABI() (err error) {
 		return err
 	}
 
+	constants.ERC20Abi, err = abi.JSON(strings.NewReader(string(erc20Json)))
+	if err != nil {
+		return err
+	}
+
+	constants.ERC721Abi, err = abi.JSON(strings.NewReader(string(erc721Json)))
+	if err != nil {
+		return err
+	}
+
+	constants.ERC1155Abi, err = abi.JSON(strings.NewReader(string(erc1155Json)))
+	if err != nil {
+		return err
+	}
+
+	constants.EntryPointAbi, err = abi.JSON(strings.NewReader(string(entryPointJson)))
+	if err != nil {
+		return err
+	}
+
+	constants.SimpleAccountAbi, err = abi.JSON(strings.NewReader(string(simpleAccountJson)))
+	if err != nil {
+		return err
+	}
+
 	return
 }
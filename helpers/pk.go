@@ -38,3 +38,13 @@ func PrivateKey(
 
 	return privateKey, nil
 }
+
+// PrivateKeyBytes returns the 32-byte representation of the private key
+func PrivateKeyBytes(privateKey *ecdsa.PrivateKey) []byte {
+	return crypto.FromECDSA(privateKey)
+}
+
+// PrivateKeyFromBytes restores the private key from its 32-byte representation
+func PrivateKeyFromBytes(privateKey []byte) (*ecdsa.PrivateKey, error) {
+	return crypto.ToECDSA(privateKey)
+}
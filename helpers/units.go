@@ -0,0 +1,68 @@
+package helpers
+
+import (
+	"errors"
+	"fmt"
+	"math/big"
+	"strings"
+)
+
+// FormatUnits converts the amount in base units to the decimal string, e.g. 12500000 with 6 decimals is "12.5"
+func FormatUnits(amount *big.Int, decimals uint8) string {
+	if amount == nil {
+		return "0"
+	}
+	digits := new(big.Int).Abs(amount).String()
+	sign := ""
+	if amount.Sign() < 0 {
+		sign = "-"
+	}
+	if decimals == 0 {
+		return sign + digits
+	}
+	if len(digits) <= int(decimals) {
+		digits = strings.Repeat("0", int(decimals)-len(digits)+1) + digits
+	}
+	integer := digits[:len(digits)-int(decimals)]
+	fraction := strings.TrimRight(digits[len(digits)-int(decimals):], "0")
+	if fraction == "" {
+		return sign + integer
+	}
+	return sign + integer + "." + fraction
+}
+
+// ParseUnits converts the decimal string to the amount in base units, e.g. "12.50" with 6 decimals is 12500000.
+// An error is returned if the value has more fractional digits than decimals.
+func ParseUnits(value string, decimals uint8) (*big.Int, error) {
+	value = strings.TrimSpace(value)
+	sign := ""
+	if strings.HasPrefix(value, "-") {
+		sign = "-"
+		value = value[1:]
+	}
+	integer, fraction, _ := strings.Cut(value, ".")
+	if integer == "" && fraction == "" {
+		return nil, errors.New("amount is empty")
+	}
+	if !isDigits(integer) || !isDigits(fraction) {
+		return nil, fmt.Errorf("invalid amount %q", value)
+	}
+	if len(fraction) > int(decimals) {
+		return nil, fmt.Errorf("amount %q has more than %d decimals", value, decimals)
+	}
+	fraction += strings.Repeat("0", int(decimals)-len(fraction))
+	amount, ok := new(big.Int).SetString(sign+integer+fraction, 10)
+	if !ok {
+		return nil, fmt.Errorf("invalid amount %q", value)
+	}
+	return amount, nil
+}
+
+func isDigits(s string) bool {
+	for _, c := range s {
+		if c < '0' || c > '9' {
+			return false
+		}
+	}
+	return true
+}
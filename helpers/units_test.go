@@ -0,0 +1,97 @@
+package helpers
+
+import (
+	"math/big"
+	"testing"
+)
+
+func TestFormatUnits(t *testing.T) {
+	tests := []struct {
+		name     string
+		amount   *big.Int
+		decimals uint8
+		want     string
+	}{
+		{name: "nil", amount: nil, decimals: 6, want: "0"},
+		{name: "zero", amount: big.NewInt(0), decimals: 6, want: "0"},
+		{name: "whole", amount: big.NewInt(12000000), decimals: 6, want: "12"},
+		{name: "fraction", amount: big.NewInt(12500000), decimals: 6, want: "12.5"},
+		{name: "smallest unit", amount: big.NewInt(1), decimals: 6, want: "0.000001"},
+		{name: "below one", amount: big.NewInt(500000), decimals: 6, want: "0.5"},
+		{name: "no decimals", amount: big.NewInt(42), decimals: 0, want: "42"},
+		{name: "negative", amount: big.NewInt(-1500000), decimals: 6, want: "-1.5"},
+		{name: "negative below one", amount: big.NewInt(-1), decimals: 2, want: "-0.01"},
+		{
+			name:     "18 decimals",
+			amount:   new(big.Int).Mul(big.NewInt(123), new(big.Int).Exp(big.NewInt(10), big.NewInt(16), nil)),
+			decimals: 18,
+			want:     "1.23",
+		},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			if got := FormatUnits(tt.amount, tt.decimals); got != tt.want {
+				t.Fatalf("got %s, want %s", got, tt.want)
+			}
+		})
+	}
+}
+
+func TestParseUnits(t *testing.T) {
+	tests := []struct {
+		name     string
+		value    string
+		decimals uint8
+		want     string
+		wantErr  bool
+	}{
+		{name: "whole", value: "12", decimals: 6, want: "12000000"},
+		{name: "fraction", value: "12.50", decimals: 6, want: "12500000"},
+		{name: "all decimals", value: "0.000001", decimals: 6, want: "1"},
+		{name: "leading dot", value: ".5", decimals: 6, want: "500000"},
+		{name: "trailing dot", value: "1.", decimals: 6, want: "1000000"},
+		{name: "spaces", value: " 1.5 ", decimals: 2, want: "150"},
+		{name: "no decimals", value: "42", decimals: 0, want: "42"},
+		{name: "negative", value: "-1.5", decimals: 6, want: "-1500000"},
+		{name: "18 decimals", value: "1.23", decimals: 18, want: "1230000000000000000"},
+		{name: "empty", value: "", decimals: 6, wantErr: true},
+		{name: "dot only", value: ".", decimals: 6, wantErr: true},
+		{name: "sign only", value: "-", decimals: 6, wantErr: true},
+		{name: "too many decimals", value: "0.0000001", decimals: 6, wantErr: true},
+		{name: "fraction without decimals", value: "1.5", decimals: 0, wantErr: true},
+		{name: "plus sign", value: "+1", decimals: 6, wantErr: true},
+		{name: "double sign", value: "--1", decimals: 6, wantErr: true},
+		{name: "exponent", value: "1e6", decimals: 6, wantErr: true},
+		{name: "two dots", value: "1.2.3", decimals: 6, wantErr: true},
+		{name: "comma", value: "1,5", decimals: 6, wantErr: true},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			got, err := ParseUnits(tt.value, tt.decimals)
+			if tt.wantErr {
+				if err == nil {
+					t.Fatalf("expected error, got %s", got)
+				}
+				return
+			}
+			if err != nil {
+				t.Fatal(err)
+			}
+			if got.String() != tt.want {
+				t.Fatalf("got %s, want %s", got, tt.want)
+			}
+		})
+	}
+}
+
+func TestUnitsRoundTrip(t *testing.T) {
+	for _, value := range []string{"0", "1", "0.000001", "12.5", "-3.25", "1000000.123456"} {
+		amount, err := ParseUnits(value, 6)
+		if err != nil {
+			t.Fatal(err)
+		}
+		if got := FormatUnits(amount, 6); got != value {
+			t.Fatalf("got %s, want %s", got, value)
+		}
+	}
+}
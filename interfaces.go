@@ -1,7 +1,10 @@
 package linkdrop
 
-import "github.com/ethereum/go-ethereum/common"
+import (
+	"context"
+	"github.com/ethereum/go-ethereum/common"
+)
 
 type IClaimLinkRedeemable interface {
-	Redeem(receiver common.Address) (txHash common.Hash, err error)
+	Redeem(ctx context.Context, receiver common.Address) (txHash common.Hash, err error)
 }
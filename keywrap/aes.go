@@ -0,0 +1,110 @@
+package keywrap
+
+import (
+	"crypto/aes"
+	"crypto/cipher"
+	"crypto/rand"
+	"errors"
+	"fmt"
+	"sync"
+)
+
+// AESGCM is a KeyWrapper keeping 256-bit KEKs in memory and wrapping DEKs with AES-GCM.
+// Wrapped keys have the format [nonce(12 bytes), sealed(...)], the key id is used as additional data.
+// It keeps the retired KEKs to unwrap the keys wrapped before the rotation. It's safe for concurrent use.
+type AESGCM struct {
+	mu           sync.RWMutex
+	keys         map[string]cipher.AEAD
+	currentKeyId string
+}
+
+// NewAESGCM creates the wrapper with the current KEK
+func NewAESGCM(keyId string, key [32]byte) (*AESGCM, error) {
+	w := &AESGCM{keys: make(map[string]cipher.AEAD)}
+	if err := w.AddKey(keyId, key); err != nil {
+		return nil, err
+	}
+	w.currentKeyId = keyId
+	return w, nil
+}
+
+// AddKey adds the KEK that can be used for unwrapping or made current with Rotate
+func (w *AESGCM) AddKey(keyId string, key [32]byte) error {
+	if keyId == "" {
+		return errors.New("key id is required")
+	}
+	block, err := aes.NewCipher(key[:])
+	if err != nil {
+		return err
+	}
+	aead, err := cipher.NewGCM(block)
+	if err != nil {
+		return err
+	}
+	w.mu.Lock()
+	defer w.mu.Unlock()
+	if _, ok := w.keys[keyId]; ok {
+		return fmt.Errorf("key %s already exists", keyId)
+	}
+	w.keys[keyId] = aead
+	return nil
+}
+
+// Rotate makes the added KEK current. New keys are wrapped with it, see Rewrap to update the existing envelopes.
+func (w *AESGCM) Rotate(keyId string) error {
+	w.mu.Lock()
+	defer w.mu.Unlock()
+	if _, ok := w.keys[keyId]; !ok {
+		return fmt.Errorf("key %s is not found", keyId)
+	}
+	w.currentKeyId = keyId
+	return nil
+}
+
+// RemoveKey removes the retired KEK. The current KEK can't be removed.
+func (w *AESGCM) RemoveKey(keyId string) error {
+	w.mu.Lock()
+	defer w.mu.Unlock()
+	if keyId == w.currentKeyId {
+		return errors.New("current key can't be removed")
+	}
+	delete(w.keys, keyId)
+	return nil
+}
+
+func (w *AESGCM) KeyId() string {
+	w.mu.RLock()
+	defer w.mu.RUnlock()
+	return w.currentKeyId
+}
+
+func (w *AESGCM) WrapKey(dek []byte) (keyId string, wrappedKey []byte, err error) {
+	w.mu.RLock()
+	keyId = w.currentKeyId
+	aead := w.keys[keyId]
+	w.mu.RUnlock()
+
+	nonce := make([]byte, aead.NonceSize())
+	if _, err = rand.Read(nonce); err != nil {
+		return
+	}
+	return keyId, aead.Seal(nonce, nonce, dek, []byte(keyId)), nil
+}
+
+func (w *AESGCM) UnwrapKey(keyId string, wrappedKey []byte) (dek []byte, err error) {
+	w.mu.RLock()
+	aead, ok := w.keys[keyId]
+	w.mu.RUnlock()
+	if !ok {
+		return nil, fmt.Errorf("key %s is not found", keyId)
+	}
+	if len(wrappedKey) < aead.NonceSize() {
+		return nil, errors.New("invalid wrapped key format")
+	}
+	nonce, sealed := wrappedKey[:aead.NonceSize()], wrappedKey[aead.NonceSize():]
+	dek, err = aead.Open(nil, nonce, sealed, []byte(keyId))
+	if err != nil {
+		return nil, errors.New("failed to unwrap key")
+	}
+	return
+}
@@ -0,0 +1,137 @@
+// Package keywrap provides encryption at rest for link keys using envelope encryption.
+// Every key is encrypted with a random data encryption key (DEK) using crypto.Encrypt,
+// the DEK is wrapped with a key encryption key (KEK) managed by a KeyWrapper.
+package keywrap
+
+import (
+	"crypto/ecdsa"
+	"crypto/rand"
+	"errors"
+	"github.com/LinkdropHQ/linkdrop-go-sdk/crypto"
+	"github.com/LinkdropHQ/linkdrop-go-sdk/helpers"
+	"github.com/LinkdropHQ/linkdrop-go-sdk/types"
+	"github.com/ethereum/go-ethereum/common/hexutil"
+)
+
+// KeyWrapper wraps data encryption keys with a key encryption key.
+// Implement it to keep KEKs in a KMS or HSM, see AESGCM for the local implementation.
+type KeyWrapper interface {
+	// KeyId returns the identifier of the current KEK
+	KeyId() string
+	// WrapKey encrypts the DEK with the current KEK and returns its identifier
+	WrapKey(dek []byte) (keyId string, wrappedKey []byte, err error)
+	// UnwrapKey decrypts the DEK with the KEK identified by keyId
+	UnwrapKey(keyId string, wrappedKey []byte) (dek []byte, err error)
+}
+
+// Envelope is a sealed secret with its wrapped DEK
+type Envelope struct {
+	KeyId      string        `json:"keyId"`      // KeyId - the KEK identifier the DEK is wrapped with
+	WrappedKey hexutil.Bytes `json:"wrappedKey"` // WrappedKey - the DEK wrapped with the KEK
+	Ciphertext hexutil.Bytes `json:"ciphertext"` // Ciphertext - the secret encrypted with the DEK, see crypto.Encrypt
+}
+
+// Seal encrypts the plaintext with a random DEK and wraps the DEK with the current KEK of wrapper
+func Seal(wrapper KeyWrapper, plaintext []byte) (envelope *Envelope, err error) {
+	var dek [crypto.KeyLength]byte
+	if _, err = rand.Read(dek[:]); err != nil {
+		return
+	}
+	var iv [crypto.NonceLength]byte
+	if _, err = rand.Read(iv[:]); err != nil {
+		return
+	}
+	ciphertext, err := crypto.Encrypt(plaintext, dek, iv)
+	if err != nil {
+		return
+	}
+	keyId, wrappedKey, err := wrapper.WrapKey(dek[:])
+	if err != nil {
+		return
+	}
+	return &Envelope{
+		KeyId:      keyId,
+		WrappedKey: wrappedKey,
+		Ciphertext: ciphertext,
+	}, nil
+}
+
+// Open unwraps the DEK and decrypts the envelope
+func Open(wrapper KeyWrapper, envelope *Envelope) (plaintext []byte, err error) {
+	dek, err := unwrapDEK(wrapper, envelope)
+	if err != nil {
+		return
+	}
+	decrypted, err := crypto.Decrypt(envelope.Ciphertext, dek)
+	if err != nil {
+		return
+	}
+	return []byte(decrypted), nil
+}
+
+// Rewrap wraps the envelope DEK with the current KEK of wrapper, the ciphertext stays untouched.
+// Use it to rotate KEKs: add the new key to the wrapper, make it current and rewrap the stored envelopes.
+func Rewrap(wrapper KeyWrapper, envelope *Envelope) (rewrapped *Envelope, err error) {
+	if envelope.KeyId == wrapper.KeyId() {
+		return envelope, nil
+	}
+	dek, err := unwrapDEK(wrapper, envelope)
+	if err != nil {
+		return
+	}
+	keyId, wrappedKey, err := wrapper.WrapKey(dek[:])
+	if err != nil {
+		return
+	}
+	return &Envelope{
+		KeyId:      keyId,
+		WrappedKey: wrappedKey,
+		Ciphertext: envelope.Ciphertext,
+	}, nil
+}
+
+// SealLinkKey seals the claim link key
+func SealLinkKey(wrapper KeyWrapper, linkKey *ecdsa.PrivateKey) (*Envelope, error) {
+	if linkKey == nil {
+		return nil, errors.New("link key is not set")
+	}
+	return Seal(wrapper, helpers.PrivateKeyBytes(linkKey))
+}
+
+// OpenLinkKey restores the claim link key sealed with SealLinkKey
+func OpenLinkKey(wrapper KeyWrapper, envelope *Envelope) (*ecdsa.PrivateKey, error) {
+	plaintext, err := Open(wrapper, envelope)
+	if err != nil {
+		return nil, err
+	}
+	return helpers.PrivateKeyFromBytes(plaintext)
+}
+
+// SealMessageLinkKey seals the key of the encrypted sender message
+func SealMessageLinkKey(wrapper KeyWrapper, messageLinkKey types.MessageLinkKey) (*Envelope, error) {
+	return Seal(wrapper, []byte(messageLinkKey))
+}
+
+// OpenMessageLinkKey restores the message key sealed with SealMessageLinkKey
+func OpenMessageLinkKey(wrapper KeyWrapper, envelope *Envelope) (types.MessageLinkKey, error) {
+	plaintext, err := Open(wrapper, envelope)
+	if err != nil {
+		return "", err
+	}
+	return types.MessageLinkKey(plaintext), nil
+}
+
+func unwrapDEK(wrapper KeyWrapper, envelope *Envelope) (dek [crypto.KeyLength]byte, err error) {
+	if envelope == nil {
+		return dek, errors.New("envelope is not set")
+	}
+	unwrapped, err := wrapper.UnwrapKey(envelope.KeyId, envelope.WrappedKey)
+	if err != nil {
+		return
+	}
+	if len(unwrapped) != crypto.KeyLength {
+		return dek, errors.New("invalid data encryption key length")
+	}
+	copy(dek[:], unwrapped)
+	return
+}
@@ -0,0 +1,234 @@
+package keywrap
+
+import (
+	"bytes"
+	"github.com/LinkdropHQ/linkdrop-go-sdk/types"
+	"github.com/ethereum/go-ethereum/crypto"
+	"testing"
+)
+
+func newTestWrapper(t *testing.T) *AESGCM {
+	t.Helper()
+	w, err := NewAESGCM("kek-1", [32]byte{1})
+	if err != nil {
+		t.Fatal(err)
+	}
+	return w
+}
+
+func TestSealOpen(t *testing.T) {
+	plaintext := []byte("link key bytes")
+	tests := []struct {
+		name    string
+		tamper  func(w *AESGCM, envelope *Envelope)
+		wantErr bool
+	}{
+		{name: "round trip", tamper: func(w *AESGCM, envelope *Envelope) {}},
+		{
+			name: "tampered wrapped key",
+			tamper: func(w *AESGCM, envelope *Envelope) {
+				envelope.WrappedKey[len(envelope.WrappedKey)-1] ^= 1
+			},
+			wantErr: true,
+		},
+		{
+			name: "truncated wrapped key",
+			tamper: func(w *AESGCM, envelope *Envelope) {
+				envelope.WrappedKey = envelope.WrappedKey[:4]
+			},
+			wantErr: true,
+		},
+		{
+			name: "tampered ciphertext",
+			tamper: func(w *AESGCM, envelope *Envelope) {
+				envelope.Ciphertext[len(envelope.Ciphertext)-1] ^= 1
+			},
+			wantErr: true,
+		},
+		{
+			name: "unknown key id",
+			tamper: func(w *AESGCM, envelope *Envelope) {
+				envelope.KeyId = "kek-unknown"
+			},
+			wantErr: true,
+		},
+		{
+			// the key id is authenticated, the DEK can't be unwrapped with another KEK id
+			name: "swapped key id",
+			tamper: func(w *AESGCM, envelope *Envelope) {
+				if err := w.AddKey("kek-2", [32]byte{1}); err != nil {
+					t.Fatal(err)
+				}
+				envelope.KeyId = "kek-2"
+			},
+			wantErr: true,
+		},
+		{
+			name: "removed key",
+			tamper: func(w *AESGCM, envelope *Envelope) {
+				if err := w.AddKey("kek-2", [32]byte{2}); err != nil {
+					t.Fatal(err)
+				}
+				if err := w.Rotate("kek-2"); err != nil {
+					t.Fatal(err)
+				}
+				if err := w.RemoveKey("kek-1"); err != nil {
+					t.Fatal(err)
+				}
+			},
+			wantErr: true,
+		},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			w := newTestWrapper(t)
+			envelope, err := Seal(w, plaintext)
+			if err != nil {
+				t.Fatal(err)
+			}
+			if envelope.KeyId != "kek-1" || bytes.Contains(envelope.Ciphertext, plaintext) {
+				t.Fatalf("unexpected envelope %+v", envelope)
+			}
+			tt.tamper(w, envelope)
+			opened, err := Open(w, envelope)
+			if tt.wantErr {
+				if err == nil {
+					t.Fatalf("expected error, got %q", opened)
+				}
+				return
+			}
+			if err != nil {
+				t.Fatal(err)
+			}
+			if !bytes.Equal(opened, plaintext) {
+				t.Fatalf("got %q, want %q", opened, plaintext)
+			}
+		})
+	}
+}
+
+func TestSealUsesRandomKeys(t *testing.T) {
+	w := newTestWrapper(t)
+	a, err := Seal(w, []byte("secret"))
+	if err != nil {
+		t.Fatal(err)
+	}
+	b, err := Seal(w, []byte("secret"))
+	if err != nil {
+		t.Fatal(err)
+	}
+	if bytes.Equal(a.WrappedKey, b.WrappedKey) || bytes.Equal(a.Ciphertext, b.Ciphertext) {
+		t.Fatal("envelopes of the same secret are equal")
+	}
+}
+
+func TestRewrap(t *testing.T) {
+	w := newTestWrapper(t)
+	envelope, err := Seal(w, []byte("secret"))
+	if err != nil {
+		t.Fatal(err)
+	}
+	same, err := Rewrap(w, envelope)
+	if err != nil {
+		t.Fatal(err)
+	}
+	if same != envelope {
+		t.Fatal("envelope wrapped with the current KEK is rewrapped")
+	}
+
+	if err = w.AddKey("kek-2", [32]byte{2}); err != nil {
+		t.Fatal(err)
+	}
+	if err = w.Rotate("kek-2"); err != nil {
+		t.Fatal(err)
+	}
+	rewrapped, err := Rewrap(w, envelope)
+	if err != nil {
+		t.Fatal(err)
+	}
+	if rewrapped.KeyId != "kek-2" || !bytes.Equal(rewrapped.Ciphertext, envelope.Ciphertext) {
+		t.Fatalf("unexpected rewrapped envelope %+v", rewrapped)
+	}
+	if envelope.KeyId != "kek-1" {
+		t.Fatal("original envelope is modified")
+	}
+	if err = w.RemoveKey("kek-1"); err != nil {
+		t.Fatal(err)
+	}
+	opened, err := Open(w, rewrapped)
+	if err != nil {
+		t.Fatal(err)
+	}
+	if string(opened) != "secret" {
+		t.Fatalf("got %q", opened)
+	}
+	if _, err = Open(w, envelope); err == nil {
+		t.Fatal("expected the envelope of the removed KEK not to open")
+	}
+}
+
+func TestAESGCMKeys(t *testing.T) {
+	w := newTestWrapper(t)
+	tests := []struct {
+		name    string
+		run     func() error
+		wantErr bool
+	}{
+		{name: "empty key id", run: func() error { return w.AddKey("", [32]byte{2}) }, wantErr: true},
+		{name: "duplicate key id", run: func() error { return w.AddKey("kek-1", [32]byte{2}) }, wantErr: true},
+		{name: "rotate to unknown key", run: func() error { return w.Rotate("kek-unknown") }, wantErr: true},
+		{name: "remove current key", run: func() error { return w.RemoveKey("kek-1") }, wantErr: true},
+		{name: "add key", run: func() error { return w.AddKey("kek-2", [32]byte{2}) }},
+		{name: "rotate", run: func() error { return w.Rotate("kek-2") }},
+		{name: "remove retired key", run: func() error { return w.RemoveKey("kek-1") }},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			err := tt.run()
+			if (err != nil) != tt.wantErr {
+				t.Fatalf("unexpected error %v", err)
+			}
+		})
+	}
+	if w.KeyId() != "kek-2" {
+		t.Fatalf("unexpected current key %s", w.KeyId())
+	}
+}
+
+func TestSealLinkKeys(t *testing.T) {
+	w := newTestWrapper(t)
+	linkKey, err := crypto.GenerateKey()
+	if err != nil {
+		t.Fatal(err)
+	}
+	envelope, err := SealLinkKey(w, linkKey)
+	if err != nil {
+		t.Fatal(err)
+	}
+	opened, err := OpenLinkKey(w, envelope)
+	if err != nil {
+		t.Fatal(err)
+	}
+	if !opened.Equal(linkKey) {
+		t.Fatal("opened link key differs")
+	}
+	if _, err = SealLinkKey(w, nil); err == nil {
+		t.Fatal("expected error for nil link key")
+	}
+	if _, err = OpenLinkKey(w, nil); err == nil {
+		t.Fatal("expected error for nil envelope")
+	}
+
+	messageKey := types.MessageLinkKey("3yZe7d")
+	envelope, err = SealMessageLinkKey(w, messageKey)
+	if err != nil {
+		t.Fatal(err)
+	}
+	openedMessageKey, err := OpenMessageLinkKey(w, envelope)
+	if err != nil {
+		t.Fatal(err)
+	}
+	if openedMessageKey != messageKey {
+		t.Fatalf("got %q, want %q", openedMessageKey, messageKey)
+	}
+}
@@ -0,0 +1,32 @@
+package linkdrop
+
+import "net/http"
+
+// Middleware wraps the transport used by the API Client.
+// It can modify outgoing requests (headers, signing) and inspect or replace responses.
+// NOTE: middlewares are applied in the order they're provided, the first one being the outermost
+type Middleware func(next http.RoundTripper) http.RoundTripper
+
+// RoundTripperFunc is an adapter allowing to use an ordinary function as http.RoundTripper
+type RoundTripperFunc func(req *http.Request) (*http.Response, error)
+
+func (f RoundTripperFunc) RoundTrip(req *http.Request) (*http.Response, error) {
+	return f(req)
+}
+
+// HeadersMiddleware sets the provided headers on every request sent by the API Client
+func HeadersMiddleware(headers http.Header) Middleware {
+	return func(next http.RoundTripper) http.RoundTripper {
+		return RoundTripperFunc(func(req *http.Request) (*http.Response, error) {
+			// RoundTripper should not modify the original request
+			req = req.Clone(req.Context())
+			for key, values := range headers {
+				req.Header.Del(key)
+				for _, value := range values {
+					req.Header.Add(key, value)
+				}
+			}
+			return next.RoundTrip(req)
+		})
+	}
+}
@@ -0,0 +1,76 @@
+package linkdrop
+
+import (
+	"context"
+	"github.com/LinkdropHQ/linkdrop-go-sdk/types"
+	"github.com/ethereum/go-ethereum/common"
+	"net/http"
+	"net/http/httptest"
+	"reflect"
+	"testing"
+)
+
+// orderMiddleware appends name to the X-Order header of the request and to calls of the response
+func orderMiddleware(name string, calls *[]string) Middleware {
+	return func(next http.RoundTripper) http.RoundTripper {
+		return RoundTripperFunc(func(req *http.Request) (*http.Response, error) {
+			req = req.Clone(req.Context())
+			req.Header.Add("X-Order", name)
+			resp, err := next.RoundTrip(req)
+			*calls = append(*calls, name)
+			return resp, err
+		})
+	}
+}
+
+func TestClientMiddleware(t *testing.T) {
+	var received http.Header
+	server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		received = r.Header.Clone()
+		_, _ = w.Write([]byte(`{"success": true, "claim_link": {}}`))
+	}))
+	t.Cleanup(server.Close)
+
+	var transportRequests int
+	serverTransport := server.Client().Transport
+	httpClient := &http.Client{Transport: RoundTripperFunc(func(req *http.Request) (*http.Response, error) {
+		transportRequests++
+		return serverTransport.RoundTrip(req)
+	})}
+	userTransport := httpClient.Transport
+
+	var responses []string
+	sdk, err := Init("https://p2p.linkdrop.io", "test-key",
+		WithApiUrl(server.URL),
+		WithHTTPClient(httpClient),
+		WithMiddleware(
+			HeadersMiddleware(http.Header{"X-Client": {"test"}, "X-Order": {"headers"}}),
+			orderMiddleware("first", &responses),
+		),
+		WithMiddleware(orderMiddleware("second", &responses)),
+	)
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	_, err = sdk.Client.GetTransferStatus(context.Background(), types.ChainIdBase, common.HexToAddress("0x01"))
+	if err != nil {
+		t.Fatal(err)
+	}
+	if received.Get("X-Client") != "test" || received.Get("Authorization") != "Bearer test-key" {
+		t.Fatalf("headers aren't applied: %v", received)
+	}
+	// the first middleware is the outermost, it modifies the request first and gets the response last
+	if order := received.Values("X-Order"); !reflect.DeepEqual(order, []string{"headers", "first", "second"}) {
+		t.Fatalf("unexpected request order %v", order)
+	}
+	if !reflect.DeepEqual(responses, []string{"second", "first"}) {
+		t.Fatalf("unexpected response order %v", responses)
+	}
+	if transportRequests != 1 {
+		t.Fatalf("the HTTP client transport is used %d times", transportRequests)
+	}
+	if reflect.ValueOf(httpClient.Transport).Pointer() != reflect.ValueOf(userTransport).Pointer() {
+		t.Fatal("the HTTP client is modified")
+	}
+}
@@ -1,5 +1,12 @@
 package linkdrop
 
+import (
+	"github.com/LinkdropHQ/linkdrop-go-sdk/store"
+	"github.com/LinkdropHQ/linkdrop-go-sdk/types"
+	"github.com/ethereum/go-ethereum/accounts/abi/bind"
+	"net/http"
+)
+
 type Option func(*SDKConfig, *ClientConfig)
 
 func WithEnvironmentTag(tag string) Option {
@@ -14,12 +21,102 @@ func WithMessageConfig(messageConfig MessageConfig) Option {
 	}
 }
 
+func WithPollingConfig(pollingConfig PollingConfig) Option {
+	return func(sdkc *SDKConfig, cc *ClientConfig) {
+		sdkc.pollingConfig = pollingConfig
+	}
+}
+
+// WithLinkStore persists the claim link state transitions in the store.
+// The link key isn't saved by default, the options define how it's saved, e.g. use SnapshotWithEncryptedLinkKey
+// or SnapshotWithKeyWrapper to keep it encrypted. SDK.StoredClaimLink restores the links with the same options.
+func WithLinkStore(linkStore store.LinkStore, opts ...SnapshotOption) Option {
+	return func(sdkc *SDKConfig, cc *ClientConfig) {
+		sdkc.linkStore = linkStore
+		sdkc.snapshotOptions = opts
+	}
+}
+
+// WithReceiptReader sets the client of the chain used to read the sender message from the deposit transaction
+// when the API doesn't return it, e.g. *ethclient.Client
+func WithReceiptReader(chainId types.ChainId, reader ReceiptReader) Option {
+	return func(sdkc *SDKConfig, cc *ClientConfig) {
+		if sdkc.receiptReaders == nil {
+			sdkc.receiptReaders = make(map[types.ChainId]ReceiptReader)
+		}
+		sdkc.receiptReaders[chainId] = reader
+	}
+}
+
+// WithChainRegistry sets the chains the SDK works with, a new types.DefaultChainRegistry is used by default.
+// Use it to add testnets, e.g. Base Sepolia or Polygon Amoy, and local forks.
+func WithChainRegistry(chains *types.ChainRegistry) Option {
+	return func(sdkc *SDKConfig, cc *ClientConfig) {
+		if chains != nil {
+			sdkc.chains = chains
+			cc.chains = chains
+		}
+	}
+}
+
+// WithEscrowVersions sets the escrow versions used to select ABI, typed data and features by the link escrow address
+func WithEscrowVersions(escrowVersions *EscrowVersionRegistry) Option {
+	return func(sdkc *SDKConfig, cc *ClientConfig) {
+		sdkc.escrowVersions = escrowVersions
+	}
+}
+
+// WithTokenInfoCaller sets the client of the chain used to read ERC20 decimals and symbol, e.g. *ethclient.Client.
+// The stable coins of the chain registry are resolved without it.
+func WithTokenInfoCaller(chainId types.ChainId, caller bind.ContractCaller) Option {
+	return func(sdkc *SDKConfig, cc *ClientConfig) {
+		if sdkc.tokenCallers == nil {
+			sdkc.tokenCallers = make(map[types.ChainId]bind.ContractCaller)
+		}
+		sdkc.tokenCallers[chainId] = caller
+	}
+}
+
 func WithApiUrl(apiUrl string) Option {
 	return func(sdkc *SDKConfig, cc *ClientConfig) {
 		cc.apiURL = apiUrl
 	}
 }
 
+// WithDashboardApi sets the API used to resolve and redeem the links created in the Linkdrop Dashboard.
+// NOTE: the SDK doesn't implement the Dashboard API, without it ClaimLinkDashboard fails with ErrDashboardApiNotSet
+func WithDashboardApi(dashboardApi DashboardApi) Option {
+	return func(sdkc *SDKConfig, cc *ClientConfig) {
+		sdkc.dashboardApi = dashboardApi
+	}
+}
+
+// WithHTTPClient sets the HTTP client used by the API Client.
+// Use it to configure timeouts, proxies, mTLS or a test transport.
+// NOTE: the client is copied, the provided instance is not modified by the middleware chain
+func WithHTTPClient(httpClient *http.Client) Option {
+	return func(sdkc *SDKConfig, cc *ClientConfig) {
+		if httpClient != nil {
+			cc.httpClient = httpClient
+		}
+	}
+}
+
+// WithMiddleware appends middlewares to the API Client transport chain
+func WithMiddleware(middlewares ...Middleware) Option {
+	return func(sdkc *SDKConfig, cc *ClientConfig) {
+		cc.middlewares = append(cc.middlewares, middlewares...)
+	}
+}
+
+// WithRetryPolicy sets the policy used to retry API requests failed with transient errors.
+// Use NoRetryPolicy to disable retries.
+func WithRetryPolicy(retryPolicy RetryPolicy) Option {
+	return func(sdkc *SDKConfig, cc *ClientConfig) {
+		cc.retryPolicy = retryPolicy
+	}
+}
+
 // Presets
 
 func WithDefaultMessageConfig() Option {
@@ -0,0 +1,251 @@
+package linkdrop
+
+import (
+	"encoding/hex"
+	"encoding/json"
+	"errors"
+	"github.com/LinkdropHQ/linkdrop-go-sdk/types"
+	"github.com/ethereum/go-ethereum/common"
+	"math/big"
+	"strings"
+)
+
+// apiEnvelope is the part shared by all API responses
+type apiEnvelope struct {
+	Success *bool `json:"success"`
+}
+
+// FeeResponse is a response of Client.GetFee
+type FeeResponse struct {
+	Amount               *big.Int       `json:"amount"`
+	TotalAmount          *big.Int       `json:"total_amount"`
+	FeeAmount            *big.Int       `json:"fee_amount"`
+	FeeToken             common.Address `json:"fee_token"`
+	FeeAuthorization     []byte         `json:"fee_authorization"`
+	MinTransferAmount    *big.Int       `json:"min_transfer_amount"`
+	MaxTransferAmount    *big.Int       `json:"max_transfer_amount"`
+	MinTransferAmountUsd string         `json:"min_transfer_amount_usd"` // MinTransferAmountUsd - decimal string, e.g. "10.5"
+	MaxTransferAmountUsd string         `json:"max_transfer_amount_usd"` // MaxTransferAmountUsd - decimal string, e.g. "10.5"
+}
+
+func (fr *FeeResponse) UnmarshalJSON(data []byte) error {
+	type alias FeeResponse
+	return json.Unmarshal(data, &struct {
+		*alias
+		Amount               bigIntRef  `json:"amount"`
+		TotalAmount          bigIntRef  `json:"total_amount"`
+		FeeAmount            bigIntRef  `json:"fee_amount"`
+		MinTransferAmount    bigIntRef  `json:"min_transfer_amount"`
+		MaxTransferAmount    bigIntRef  `json:"max_transfer_amount"`
+		MinTransferAmountUsd decimalRef `json:"min_transfer_amount_usd"`
+		MaxTransferAmountUsd decimalRef `json:"max_transfer_amount_usd"`
+		FeeAuthorization     bytesRef   `json:"fee_authorization"`
+	}{
+		alias:                (*alias)(fr),
+		Amount:               bigIntRef{&fr.Amount},
+		TotalAmount:          bigIntRef{&fr.TotalAmount},
+		FeeAmount:            bigIntRef{&fr.FeeAmount},
+		MinTransferAmount:    bigIntRef{&fr.MinTransferAmount},
+		MaxTransferAmount:    bigIntRef{&fr.MaxTransferAmount},
+		MinTransferAmountUsd: decimalRef{&fr.MinTransferAmountUsd},
+		MaxTransferAmountUsd: decimalRef{&fr.MaxTransferAmountUsd},
+		FeeAuthorization:     bytesRef{&fr.FeeAuthorization},
+	})
+}
+
+// ClaimLinkFee returns the fee details in the form expected by ClaimLink
+func (fr *FeeResponse) ClaimLinkFee(chainId types.ChainId) types.ClaimLinkFee {
+	tokenType := types.TokenTypeERC20
+	if fr.FeeToken == types.ZeroAddress {
+		tokenType = types.TokenTypeNative
+	}
+	return types.ClaimLinkFee{
+		Token: types.Token{
+			Type:    tokenType,
+			ChainId: chainId,
+			Address: fr.FeeToken,
+		},
+		Amount:        fr.FeeAmount,
+		Authorization: fr.FeeAuthorization,
+	}
+}
+
+// LimitsResponse is a response of Client.GetLimits
+type LimitsResponse struct {
+	MinTransferAmount    *big.Int `json:"min_transfer_amount"`
+	MaxTransferAmount    *big.Int `json:"max_transfer_amount"`
+	MinTransferAmountUsd string   `json:"min_transfer_amount_usd"` // MinTransferAmountUsd - decimal string, e.g. "10.5"
+	MaxTransferAmountUsd string   `json:"max_transfer_amount_usd"` // MaxTransferAmountUsd - decimal string, e.g. "10.5"
+}
+
+func (lr *LimitsResponse) UnmarshalJSON(data []byte) error {
+	return json.Unmarshal(data, &struct {
+		MinTransferAmount    bigIntRef  `json:"min_transfer_amount"`
+		MaxTransferAmount    bigIntRef  `json:"max_transfer_amount"`
+		MinTransferAmountUsd decimalRef `json:"min_transfer_amount_usd"`
+		MaxTransferAmountUsd decimalRef `json:"max_transfer_amount_usd"`
+	}{
+		MinTransferAmount:    bigIntRef{&lr.MinTransferAmount},
+		MaxTransferAmount:    bigIntRef{&lr.MaxTransferAmount},
+		MinTransferAmountUsd: decimalRef{&lr.MinTransferAmountUsd},
+		MaxTransferAmountUsd: decimalRef{&lr.MaxTransferAmountUsd},
+	})
+}
+
+// ClaimLinkData is the claim link representation returned by the API
+type ClaimLinkData struct {
+	TransferId  common.Address             `json:"transfer_id"`
+	ChainId     types.ChainId              `json:"chain_id"`
+	Sender      common.Address             `json:"sender"`
+	Escrow      common.Address             `json:"escrow"`
+	Token       common.Address             `json:"token"`
+	TokenType   types.TokenType            `json:"token_type"`
+	TokenId     *big.Int                   `json:"token_id"`
+	Amount      *big.Int                   `json:"amount"`
+	TotalAmount *big.Int                   `json:"total_amount"`
+	FeeAmount   *big.Int                   `json:"fee_amount"`
+	FeeToken    common.Address             `json:"fee_token"`
+	Expiration  int64                      `json:"expiration"`
+	Status      types.ClaimLinkStatus      `json:"status"`
+	Operations  []types.ClaimLinkOperation `json:"operations"`
+	// EncryptedSenderMessage - the encrypted sender message, see types.EncryptedMessage.Data
+	EncryptedSenderMessage []byte `json:"encrypted_sender_message"`
+}
+
+func (cld *ClaimLinkData) UnmarshalJSON(data []byte) error {
+	type alias ClaimLinkData
+	return json.Unmarshal(data, &struct {
+		*alias
+		TokenId                bigIntRef `json:"token_id"`
+		Amount                 bigIntRef `json:"amount"`
+		TotalAmount            bigIntRef `json:"total_amount"`
+		FeeAmount              bigIntRef `json:"fee_amount"`
+		EncryptedSenderMessage bytesRef  `json:"encrypted_sender_message"`
+	}{
+		alias:                  (*alias)(cld),
+		TokenId:                bigIntRef{&cld.TokenId},
+		Amount:                 bigIntRef{&cld.Amount},
+		TotalAmount:            bigIntRef{&cld.TotalAmount},
+		FeeAmount:              bigIntRef{&cld.FeeAmount},
+		EncryptedSenderMessage: bytesRef{&cld.EncryptedSenderMessage},
+	})
+}
+
+// TokenInfo returns the claim link token
+func (cld *ClaimLinkData) TokenInfo() types.Token {
+	token := types.Token{
+		Type:    cld.TokenType,
+		ChainId: cld.ChainId,
+		Address: cld.Token,
+	}
+	if cld.TokenType == types.TokenTypeERC721 || cld.TokenType == types.TokenTypeERC1155 {
+		token.Id = cld.TokenId
+	}
+	return token
+}
+
+// Fee returns the claim link fee
+func (cld *ClaimLinkData) Fee() types.ClaimLinkFee {
+	feeTokenType := types.TokenTypeERC20
+	if cld.FeeToken == types.ZeroAddress {
+		feeTokenType = types.TokenTypeNative
+	}
+	return types.ClaimLinkFee{
+		Token: types.Token{
+			Type:    feeTokenType,
+			ChainId: cld.ChainId,
+			Address: cld.FeeToken,
+		},
+		Amount: cld.FeeAmount,
+	}
+}
+
+// TransferStatusResponse is a response of Client.GetTransferStatus and Client.GetTransferStatusByTxHash
+type TransferStatusResponse struct {
+	ClaimLink ClaimLinkData `json:"claim_link"`
+}
+
+// RedeemResponse is a response of Client.RedeemLink and Client.RedeemRecoveredLink
+type RedeemResponse struct {
+	TxHash common.Hash `json:"tx_hash"`
+}
+
+// DepositResponse is a response of Client.Deposit and Client.DepositWithAuthorization
+type DepositResponse struct {
+	TxHash    common.Hash    `json:"tx_hash"`
+	ClaimLink *ClaimLinkData `json:"claim_link"`
+}
+
+// HistoryResponse is a response of Client.GetHistory
+type HistoryResponse struct {
+	ClaimLinks []ClaimLinkData `json:"claimLinks"`
+	ResultSet  struct {
+		Total  int64 `json:"total"`
+		Limit  int64 `json:"limit"`
+		Offset int64 `json:"offset"`
+	} `json:"resultSet"`
+}
+
+// bigIntRef decodes a big number provided either as a decimal string, a hex string with 0x prefix, or as a JSON number
+type bigIntRef struct {
+	dst **big.Int
+}
+
+func (bir bigIntRef) UnmarshalJSON(data []byte) error {
+	raw := strings.Trim(string(data), `"`)
+	if raw == "" || raw == "null" {
+		*bir.dst = nil
+		return nil
+	}
+	base := 10
+	if strings.HasPrefix(raw, "0x") || strings.HasPrefix(raw, "0X") {
+		raw, base = raw[2:], 16
+	}
+	value, ok := new(big.Int).SetString(raw, base)
+	if !ok {
+		return errors.New("invalid big number: " + raw)
+	}
+	*bir.dst = value
+	return nil
+}
+
+// decimalRef decodes a decimal number provided either as a string or as a JSON number, e.g. the USD amounts
+type decimalRef struct {
+	dst *string
+}
+
+func (dr decimalRef) UnmarshalJSON(data []byte) error {
+	raw := strings.Trim(string(data), `"`)
+	if raw == "" || raw == "null" {
+		*dr.dst = ""
+		return nil
+	}
+	if _, ok := new(big.Rat).SetString(raw); !ok || strings.Contains(raw, "/") {
+		return errors.New("invalid decimal number: " + raw)
+	}
+	*dr.dst = raw
+	return nil
+}
+
+// bytesRef decodes a hex string with or without 0x prefix
+type bytesRef struct {
+	dst *[]byte
+}
+
+func (br bytesRef) UnmarshalJSON(data []byte) error {
+	var raw string
+	if err := json.Unmarshal(data, &raw); err != nil {
+		return err
+	}
+	raw = strings.TrimPrefix(raw, "0x")
+	if raw == "" {
+		*br.dst = nil
+		return nil
+	}
+	value, err := hex.DecodeString(raw)
+	if err != nil {
+		return err
+	}
+	*br.dst = value
+	return nil
+}
@@ -0,0 +1,121 @@
+package linkdrop
+
+import (
+	"encoding/json"
+	"math/big"
+	"testing"
+)
+
+func TestBigIntRef(t *testing.T) {
+	tests := []struct {
+		name    string
+		data    string
+		want    *big.Int
+		wantErr bool
+	}{
+		{name: "decimal string", data: `"1000"`, want: big.NewInt(1000)},
+		{name: "decimal with leading zero", data: `"010"`, want: big.NewInt(10)},
+		{name: "hex string", data: `"0x10"`, want: big.NewInt(16)},
+		{name: "upper hex prefix", data: `"0XfF"`, want: big.NewInt(255)},
+		{name: "json number", data: `42`, want: big.NewInt(42)},
+		{name: "null", data: `null`},
+		{name: "empty string", data: `""`},
+		{name: "binary prefix", data: `"0b1"`, wantErr: true},
+		{name: "octal prefix", data: `"0o7"`, wantErr: true},
+		{name: "hex without prefix", data: `"ff"`, wantErr: true},
+		{name: "fraction", data: `"10.5"`, wantErr: true},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			value := big.NewInt(-1)
+			err := bigIntRef{&value}.UnmarshalJSON([]byte(tt.data))
+			if tt.wantErr {
+				if err == nil {
+					t.Fatalf("expected error, got %s", value)
+				}
+				return
+			}
+			if err != nil {
+				t.Fatal(err)
+			}
+			if (value == nil) != (tt.want == nil) || (value != nil && value.Cmp(tt.want) != 0) {
+				t.Fatalf("got %v, want %v", value, tt.want)
+			}
+		})
+	}
+}
+
+func TestDecimalRef(t *testing.T) {
+	tests := []struct {
+		name    string
+		data    string
+		want    string
+		wantErr bool
+	}{
+		{name: "integer string", data: `"10"`, want: "10"},
+		{name: "fraction string", data: `"10.5"`, want: "10.5"},
+		{name: "json number", data: `0.25`, want: "0.25"},
+		{name: "null", data: `null`},
+		{name: "not a number", data: `"ten"`, wantErr: true},
+		{name: "ratio", data: `"1/2"`, wantErr: true},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			value := "unset"
+			err := decimalRef{&value}.UnmarshalJSON([]byte(tt.data))
+			if tt.wantErr {
+				if err == nil {
+					t.Fatalf("expected error, got %s", value)
+				}
+				return
+			}
+			if err != nil {
+				t.Fatal(err)
+			}
+			if value != tt.want {
+				t.Fatalf("got %q, want %q", value, tt.want)
+			}
+		})
+	}
+}
+
+func TestLimitsResponseDecimalUsd(t *testing.T) {
+	var limits LimitsResponse
+	err := json.Unmarshal([]byte(`{
+		"min_transfer_amount": "1000000",
+		"max_transfer_amount": "0x3b9aca00",
+		"min_transfer_amount_usd": "10.5",
+		"max_transfer_amount_usd": 1000.75
+	}`), &limits)
+	if err != nil {
+		t.Fatal(err)
+	}
+	if limits.MinTransferAmount.Cmp(big.NewInt(1000000)) != 0 || limits.MaxTransferAmount.Cmp(big.NewInt(1000000000)) != 0 {
+		t.Fatalf("unexpected amounts %s, %s", limits.MinTransferAmount, limits.MaxTransferAmount)
+	}
+	if limits.MinTransferAmountUsd != "10.5" || limits.MaxTransferAmountUsd != "1000.75" {
+		t.Fatalf("unexpected USD amounts %q, %q", limits.MinTransferAmountUsd, limits.MaxTransferAmountUsd)
+	}
+}
+
+func TestFeeResponseDecimalUsd(t *testing.T) {
+	var fee FeeResponse
+	err := json.Unmarshal([]byte(`{
+		"amount": "100",
+		"total_amount": "110",
+		"fee_amount": "10",
+		"fee_token": "0x0000000000000000000000000000000000000001",
+		"fee_authorization": "0x0102",
+		"min_transfer_amount_usd": "0.5",
+		"max_transfer_amount_usd": "25000"
+	}`), &fee)
+	if err != nil {
+		t.Fatal(err)
+	}
+	if fee.TotalAmount.Cmp(big.NewInt(110)) != 0 || len(fee.FeeAuthorization) != 2 {
+		t.Fatalf("unexpected fee %+v", fee)
+	}
+	if fee.MinTransferAmountUsd != "0.5" || fee.MaxTransferAmountUsd != "25000" {
+		t.Fatalf("unexpected USD amounts %q, %q", fee.MinTransferAmountUsd, fee.MaxTransferAmountUsd)
+	}
+}
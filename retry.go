@@ -0,0 +1,126 @@
+package linkdrop
+
+import (
+	"context"
+	"errors"
+	"github.com/LinkdropHQ/linkdrop-go-sdk/helpers"
+	"math"
+	"math/rand/v2"
+	"net/http"
+	"strconv"
+	"time"
+)
+
+const IdempotencyKeyHeader = "Idempotency-Key"
+
+// RetryPolicy configures retries of the API requests failed with transient errors (429, 5xx or network errors).
+// Safe requests (GetFee, GetTransferStatus, GetHistory, GetLimits) are always retried according to the policy.
+// Deposit and redeem requests are retried only when an idempotency key is attached, see WithIdempotencyKey.
+type RetryPolicy struct {
+	MaxAttempts    int           // MaxAttempts - total number of attempts including the first one, retries are disabled if <= 1
+	InitialBackoff time.Duration // InitialBackoff - delay before the first retry
+	MaxBackoff     time.Duration // MaxBackoff - upper bound for the backoff, longer Retry-After fails the request
+	Multiplier     float64       // Multiplier - backoff growth factor between attempts
+	Jitter         float64       // Jitter - randomization factor in [0, 1] applied to every delay
+}
+
+// DefaultRetryPolicy returns the retry policy used by the SDK if no other is provided
+func DefaultRetryPolicy() RetryPolicy {
+	return RetryPolicy{
+		MaxAttempts:    3,
+		InitialBackoff: 500 * time.Millisecond,
+		MaxBackoff:     10 * time.Second,
+		Multiplier:     2,
+		Jitter:         0.2,
+	}
+}
+
+// NoRetryPolicy returns a policy that disables retries
+func NoRetryPolicy() RetryPolicy {
+	return RetryPolicy{MaxAttempts: 1}
+}
+
+// backoff returns the delay before the next attempt.
+// Retry-After header of the failed response takes precedence over the exponential backoff,
+// ok is false if it asks to wait longer than MaxBackoff, the request isn't retried then.
+func (rp *RetryPolicy) backoff(attempt int, err error) (delay time.Duration, ok bool) {
+	var statusErr *helpers.StatusError
+	if errors.As(err, &statusErr) {
+		if delay, found := retryAfter(statusErr.Header); found {
+			return delay, rp.MaxBackoff <= 0 || delay <= rp.MaxBackoff
+		}
+	}
+	multiplier := rp.Multiplier
+	if multiplier < 1 {
+		multiplier = 1
+	}
+	exponential := float64(rp.InitialBackoff) * math.Pow(multiplier, float64(attempt-1))
+	if rp.MaxBackoff > 0 && exponential > float64(rp.MaxBackoff) {
+		exponential = float64(rp.MaxBackoff)
+	}
+	if rp.Jitter > 0 {
+		exponential += exponential * rp.Jitter * (2*rand.Float64() - 1)
+	}
+	return time.Duration(exponential), true
+}
+
+// isRetryable reports whether the request failed with a transient error
+func isRetryable(err error) bool {
+	if errors.Is(err, context.Canceled) || errors.Is(err, context.DeadlineExceeded) {
+		return false
+	}
+	var statusErr *helpers.StatusError
+	if errors.As(err, &statusErr) {
+		return statusErr.StatusCode == http.StatusTooManyRequests || statusErr.StatusCode >= 500
+	}
+	// Transport level errors
+	return true
+}
+
+// isTransient reports whether the failed API call can succeed later, e.g. when polling the status.
+// Unlike isRetryable it classifies the errors returned by the Client.
+func isTransient(err error) bool {
+	if errors.Is(err, ErrUnsupportedChain) || errors.Is(err, ErrInvalidToken) {
+		return false
+	}
+	var apiErr *Error
+	if errors.As(err, &apiErr) {
+		return apiErr.StatusCode == http.StatusTooManyRequests || apiErr.StatusCode >= 500
+	}
+	return isRetryable(err)
+}
+
+// retryAfter parses Retry-After header provided either in seconds or as HTTP date
+func retryAfter(header http.Header) (time.Duration, bool) {
+	value := header.Get("Retry-After")
+	if value == "" {
+		return 0, false
+	}
+	if seconds, err := strconv.Atoi(value); err == nil && seconds >= 0 {
+		return time.Duration(seconds) * time.Second, true
+	}
+	if date, err := http.ParseTime(value); err == nil {
+		delay := time.Until(date)
+		if delay < 0 {
+			delay = 0
+		}
+		return delay, true
+	}
+	return 0, false
+}
+
+type idempotencyKeyCtx struct{}
+
+// WithIdempotencyKey attaches an idempotency key to the context.
+// The key is sent with the API requests made with this context and allows retrying deposit and redeem requests
+// without the risk of performing the operation twice.
+// NOTE: use a unique key per operation, e.g. derived from the transferId and receiver
+func WithIdempotencyKey(ctx context.Context, key string) context.Context {
+	return context.WithValue(ctx, idempotencyKeyCtx{}, key)
+}
+
+// IdempotencyKeyFromContext returns the idempotency key attached with WithIdempotencyKey
+func IdempotencyKeyFromContext(ctx context.Context) (key string, ok bool) {
+	key, ok = ctx.Value(idempotencyKeyCtx{}).(string)
+	return key, ok && key != ""
+}
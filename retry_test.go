@@ -0,0 +1,148 @@
+package linkdrop
+
+import (
+	"context"
+	"errors"
+	"github.com/LinkdropHQ/linkdrop-go-sdk/helpers"
+	"github.com/LinkdropHQ/linkdrop-go-sdk/types"
+	"github.com/ethereum/go-ethereum/common"
+	"net/http"
+	"sync/atomic"
+	"testing"
+	"time"
+)
+
+func TestRetryPolicyBackoff(t *testing.T) {
+	policy := RetryPolicy{
+		MaxAttempts:    5,
+		InitialBackoff: 100 * time.Millisecond,
+		MaxBackoff:     time.Second,
+		Multiplier:     2,
+	}
+	retryAfterErr := func(value string) error {
+		return &helpers.StatusError{StatusCode: http.StatusTooManyRequests, Header: http.Header{"Retry-After": []string{value}}}
+	}
+	tests := []struct {
+		name    string
+		attempt int
+		err     error
+		delay   time.Duration
+		ok      bool
+	}{
+		{"first retry", 1, errors.New("network error"), 100 * time.Millisecond, true},
+		{"exponential", 3, errors.New("network error"), 400 * time.Millisecond, true},
+		{"capped by MaxBackoff", 10, errors.New("network error"), time.Second, true},
+		{"Retry-After seconds", 1, retryAfterErr("1"), time.Second, true},
+		{"Retry-After zero", 4, retryAfterErr("0"), 0, true},
+		{"Retry-After over MaxBackoff", 1, retryAfterErr("3600"), time.Hour, false},
+		{"invalid Retry-After", 2, retryAfterErr("soon"), 200 * time.Millisecond, true},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			delay, ok := policy.backoff(tt.attempt, tt.err)
+			if delay != tt.delay || ok != tt.ok {
+				t.Errorf("backoff() = %s, %t, want %s, %t", delay, ok, tt.delay, tt.ok)
+			}
+		})
+	}
+}
+
+func TestRetryPolicyJitter(t *testing.T) {
+	policy := RetryPolicy{InitialBackoff: time.Second, Multiplier: 2, Jitter: 0.2}
+	for range 100 {
+		delay, _ := policy.backoff(1, errors.New("network error"))
+		if delay < 800*time.Millisecond || delay > 1200*time.Millisecond {
+			t.Fatalf("backoff() = %s, want within 20%% of 1s", delay)
+		}
+	}
+}
+
+func TestIsRetryable(t *testing.T) {
+	tests := []struct {
+		name string
+		err  error
+		want bool
+	}{
+		{"network error", errors.New("connection reset"), true},
+		{"canceled", context.Canceled, false},
+		{"deadline", context.DeadlineExceeded, false},
+		{"rate limited", &helpers.StatusError{StatusCode: http.StatusTooManyRequests}, true},
+		{"server error", &helpers.StatusError{StatusCode: http.StatusBadGateway}, true},
+		{"bad request", &helpers.StatusError{StatusCode: http.StatusBadRequest}, false},
+		{"not found", &helpers.StatusError{StatusCode: http.StatusNotFound}, false},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			if got := isRetryable(tt.err); got != tt.want {
+				t.Errorf("isRetryable() = %t, want %t", got, tt.want)
+			}
+		})
+	}
+}
+
+func TestClientRetries(t *testing.T) {
+	fastRetries := WithRetryPolicy(RetryPolicy{MaxAttempts: 3, InitialBackoff: time.Millisecond, MaxBackoff: time.Second, Multiplier: 2})
+	transferId := common.HexToAddress("0x01")
+	token := types.Token{Type: types.TokenTypeNative, ChainId: types.ChainIdBase}
+	getStatus := func(ctx context.Context, sdk *SDK) error {
+		_, err := sdk.Client.GetTransferStatus(ctx, types.ChainIdBase, transferId)
+		return err
+	}
+	redeem := func(ctx context.Context, sdk *SDK) error {
+		_, err := sdk.Client.RedeemLink(ctx, transferId, token, common.Address{}, common.Address{}, common.Address{}, nil, nil)
+		return err
+	}
+	tests := []struct {
+		name       string
+		status     int
+		retryAfter string
+		call       func(ctx context.Context, sdk *SDK) error
+		ctx        context.Context
+		attempts   int32
+	}{
+		{"GET is retried", http.StatusServiceUnavailable, "", getStatus, context.Background(), 3},
+		{"client error isn't retried", http.StatusBadRequest, "", getStatus, context.Background(), 1},
+		{"POST without idempotency key isn't retried", http.StatusServiceUnavailable, "", redeem, context.Background(), 1},
+		{"POST with idempotency key is retried", http.StatusServiceUnavailable, "", redeem, WithIdempotencyKey(context.Background(), "key"), 3},
+		{"long Retry-After isn't waited", http.StatusTooManyRequests, "3600", getStatus, context.Background(), 1},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			var attempts atomic.Int32
+			sdk := newTestSDK(t, func(w http.ResponseWriter, r *http.Request) {
+				attempts.Add(1)
+				if tt.retryAfter != "" {
+					w.Header().Set("Retry-After", tt.retryAfter)
+				}
+				w.WriteHeader(tt.status)
+			}, fastRetries)
+			if err := tt.call(tt.ctx, sdk); err == nil {
+				t.Fatal("expected an error")
+			}
+			if got := attempts.Load(); got != tt.attempts {
+				t.Errorf("attempts = %d, want %d", got, tt.attempts)
+			}
+		})
+	}
+}
+
+func TestClientRetrySucceeds(t *testing.T) {
+	var attempts atomic.Int32
+	sdk := newTestSDK(t, func(w http.ResponseWriter, r *http.Request) {
+		if attempts.Add(1) < 2 {
+			w.WriteHeader(http.StatusBadGateway)
+			return
+		}
+		if r.Header.Get("Authorization") != "Bearer test-key" {
+			t.Errorf("missing Authorization header")
+		}
+		_, _ = w.Write([]byte(`{"success": true, "claim_link": {}}`))
+	}, WithRetryPolicy(RetryPolicy{MaxAttempts: 3, InitialBackoff: time.Millisecond}))
+	_, err := sdk.Client.GetTransferStatus(context.Background(), types.ChainIdBase, common.HexToAddress("0x01"))
+	if err != nil {
+		t.Fatal(err)
+	}
+	if got := attempts.Load(); got != 2 {
+		t.Errorf("attempts = %d, want 2", got)
+	}
+}
@@ -1,15 +1,15 @@
 package linkdrop
 
 import (
+	"context"
 	"crypto/ecdsa"
-	"encoding/json"
 	"errors"
 	"github.com/LinkdropHQ/linkdrop-go-sdk/constants"
 	"github.com/LinkdropHQ/linkdrop-go-sdk/helpers"
 	"github.com/LinkdropHQ/linkdrop-go-sdk/types"
 	"github.com/ethereum/go-ethereum/common"
 	"math/big"
-	"strings"
+	"net/http"
 )
 
 type SenderHistory struct {
@@ -22,8 +22,9 @@ type SenderHistory struct {
 }
 
 type SDK struct {
-	config SDKConfig
-	Client *Client
+	config    SDKConfig
+	tokenInfo *TokenInfoResolver
+	Client    *Client
 }
 
 func Init(baseUrl string, apiKey string, opts ...Option) (*SDK, error) {
@@ -39,19 +40,37 @@ func Init(baseUrl string, apiKey string, opts ...Option) (*SDK, error) {
 	var sdkConfig SDKConfig
 	sdkConfig.applyDefaults()
 	clientConfig := &ClientConfig{
-		apiKey: apiKey,
-		apiURL: constants.ApiURL,
+		apiKey:      apiKey,
+		apiURL:      constants.ApiURL,
+		httpClient:  &http.Client{Timeout: constants.DefaultRequestTimeout},
+		retryPolicy: DefaultRetryPolicy(),
 	}
 	for _, opt := range opts {
 		opt(&sdkConfig, clientConfig)
 	}
+	if sdkConfig.chains == nil {
+		// every SDK gets its own registry, the chains registered in it don't leak to other instances
+		sdkConfig.chains = types.DefaultChainRegistry()
+		clientConfig.chains = sdkConfig.chains
+	}
+	if sdkConfig.escrowVersions == nil {
+		escrowVersions, err := DefaultEscrowVersions()
+		if err != nil {
+			return nil, err
+		}
+		sdkConfig.escrowVersions = NewEscrowVersionRegistry(escrowVersions...)
+	}
 	sdkConfig.baseURL = baseUrl
 
+	tokenInfo := NewTokenInfoResolver(sdkConfig.chains)
+	for chainId, caller := range sdkConfig.tokenCallers {
+		tokenInfo.SetCaller(chainId, caller)
+	}
+
 	return &SDK{
-		config: sdkConfig,
-		Client: &Client{
-			config: clientConfig,
-		},
+		config:    sdkConfig,
+		tokenInfo: tokenInfo,
+		Client:    newClient(clientConfig),
 	}, nil
 }
 
@@ -63,12 +82,22 @@ func (sdk *SDK) GetVersionFromClaimUrl(claimUrl string) (string, error) {
 	return helpers.VersionFromClaimUrl(claimUrl)
 }
 
+// GetVersionFromEscrowContract returns the version of the escrow, including the versions that are not registered
 func (sdk *SDK) GetVersionFromEscrowContract(escrowAddress common.Address) (string, error) {
-	return helpers.DefineEscrowVersion(escrowAddress)
+	if escrowVersion, ok := sdk.config.escrowVersions.ByAddress(escrowAddress); ok {
+		return escrowVersion.Version, nil
+	}
+	return sdk.escrowVersionName(escrowAddress)
+}
+
+// Chains returns the chains the SDK works with, see WithChainRegistry
+func (sdk *SDK) Chains() *types.ChainRegistry {
+	return sdk.config.chains
 }
 
 // ClaimLink creates a new ClaimLink generating linkKey using randomBytesCallback
 func (sdk *SDK) ClaimLink(
+	ctx context.Context,
 	params ClaimLinkCreationParams,
 	randomBytesCallback types.RandomBytesCallback,
 ) (claimLink *ClaimLink, err error) {
@@ -77,23 +106,25 @@ func (sdk *SDK) ClaimLink(
 		return
 	}
 	claimLink = new(ClaimLink)
-	return sdk.ClaimLinkWithLinkKey(params, *linkKey)
+	return sdk.ClaimLinkWithLinkKey(ctx, params, *linkKey)
 }
 
 // ClaimLinkWithTransferId creates a new ClaimLink setting with provided transferId
 // NOTE: the generated link will be created without linkKey and will lack some functionality
 // NOTE: this method creates ClaimLink and not ClaimLinkRecovered. This type supports deposit functionality.
 func (sdk *SDK) ClaimLinkWithTransferId(
+	ctx context.Context,
 	params ClaimLinkCreationParams,
 	transferId common.Address,
 ) (claimLink *ClaimLink, err error) {
 	claimLink = new(ClaimLink)
-	err = claimLink.new(sdk, &params, nil, transferId)
+	err = claimLink.new(ctx, sdk, &params, nil, transferId)
 	return
 }
 
 // ClaimLinkWithLinkKey creates a new ClaimLink with pre-generated linkKey
 func (sdk *SDK) ClaimLinkWithLinkKey(
+	ctx context.Context,
 	params ClaimLinkCreationParams,
 	linkKey ecdsa.PrivateKey,
 ) (claimLink *ClaimLink, err error) {
@@ -102,7 +133,7 @@ func (sdk *SDK) ClaimLinkWithLinkKey(
 		return
 	}
 	claimLink = new(ClaimLink)
-	err = claimLink.new(sdk, &params, &linkKey, transferId)
+	err = claimLink.new(ctx, sdk, &params, &linkKey, transferId)
 	return
 }
 
@@ -116,12 +147,12 @@ func (sdk *SDK) ClaimLinkRecovered(
 	senderSignature []byte,
 	escrowAddress *common.Address,
 ) (claimLinkRecovered *ClaimLinkRecovered, err error) {
-	err = token.Validate()
+	err = token.ValidateWith(sdk.config.chains)
 	if err != nil {
 		return
 	}
 	if escrowAddress == nil {
-		ea, err := helpers.EscrowAddressByToken(token)
+		ea, err := sdk.config.chains.EscrowAddressByToken(token)
 		if err != nil {
 			return nil, err
 		}
@@ -138,30 +169,89 @@ func (sdk *SDK) ClaimLinkRecovered(
 	return
 }
 
+// RestoreClaimLink re-creates the ClaimLink from the snapshot attaching the SDK.
+// Provide SnapshotWithEncryptedLinkKey if the link key was encrypted on the snapshot creation.
+func (sdk *SDK) RestoreClaimLink(
+	snapshot *ClaimLinkSnapshot,
+	opts ...SnapshotOption,
+) (claimLink *ClaimLink, err error) {
+	claimLink = &ClaimLink{SDK: sdk}
+	err = claimLink.restore(snapshot, newSnapshotConfig(opts))
+	if err != nil {
+		return nil, err
+	}
+	return
+}
+
+// RestoreClaimLinkRecovered re-creates the ClaimLinkRecovered from the snapshot attaching the SDK, see RestoreClaimLink
+func (sdk *SDK) RestoreClaimLinkRecovered(
+	snapshot *ClaimLinkRecoveredSnapshot,
+	opts ...SnapshotOption,
+) (claimLinkRecovered *ClaimLinkRecovered, err error) {
+	claimLinkRecovered = &ClaimLinkRecovered{SDK: sdk}
+	err = claimLinkRecovered.restore(snapshot, newSnapshotConfig(opts))
+	if err != nil {
+		return nil, err
+	}
+	return
+}
+
+// ClaimLinkDashboard resolves a link created in the Linkdrop Dashboard by its claim code
+func (sdk *SDK) ClaimLinkDashboard(
+	ctx context.Context,
+	claimCode string,
+) (claimLinkDashboard *ClaimLinkDashboard, err error) {
+	linkKey, err := helpers.DashboardLinkKey(claimCode)
+	if err != nil {
+		return
+	}
+	linkId, err := helpers.AddressFromPrivateKey(linkKey)
+	if err != nil {
+		return
+	}
+	claimLinkDashboard = &ClaimLinkDashboard{
+		SDK:       sdk,
+		ClaimCode: claimCode,
+		LinkKey:   linkKey,
+		LinkId:    linkId,
+	}
+	err = claimLinkDashboard.Refresh(ctx)
+	if err != nil {
+		return nil, err
+	}
+	return
+}
+
 func (sdk *SDK) GetSenderHistory(
+	ctx context.Context,
 	token types.Token,
 	sender common.Address,
 	onlyActive bool,
 	offset int64,
 	limit int64,
 ) (history *SenderHistory, err error) {
-	err = token.Validate()
+	err = token.ValidateWith(sdk.config.chains)
 	if err != nil {
 		return
 	}
 
-	apiResponse, err := sdk.Client.GetHistory(token, sender, onlyActive, offset, limit)
+	apiResponse, err := sdk.Client.GetHistory(ctx, token, sender, onlyActive, offset, limit)
 	if err != nil {
 		return
 	}
 
-	history = new(SenderHistory)
-	err = json.Unmarshal(apiResponse, history)
+	history = &SenderHistory{
+		ClaimLinks: make([]ClaimLink, 0, len(apiResponse.ClaimLinks)),
+		ResultSet:  apiResponse.ResultSet,
+	}
+	for i := range apiResponse.ClaimLinks {
+		history.ClaimLinks = append(history.ClaimLinks, *sdk.claimLinkFromData(&apiResponse.ClaimLinks[i], nil))
+	}
 	return
 }
 
-func (sdk *SDK) GetLimits(token types.Token) (limits *types.TransferLimits, err error) {
-	err = token.Validate()
+func (sdk *SDK) GetLimits(ctx context.Context, token types.Token) (limits *types.TransferLimits, err error) {
+	err = token.ValidateWith(sdk.config.chains)
 	if err != nil {
 		return
 	}
@@ -170,43 +260,29 @@ func (sdk *SDK) GetLimits(token types.Token) (limits *types.TransferLimits, err
 		return nil, errors.New("limits are not available for ERC721 and ERC1155 tokens")
 	}
 
-	apiResponse, err := sdk.Client.GetLimits(token)
+	apiResponse, err := sdk.Client.GetLimits(ctx, token)
 	if err != nil {
 		return
 	}
 
-	apiResponseModel := struct {
-		Success      bool   `json:"success"`
-		Error        string `json:"error"`
-		MinAmount    string `json:"min_transfer_amount"`
-		MaxAmount    string `json:"max_transfer_amount"`
-		MinAmountUSD string `json:"min_transfer_amount_usd"`
-		MaxAmountUSD string `json:"max_transfer_amount_usd"`
-	}{}
-	err = json.Unmarshal(apiResponse, &apiResponseModel)
-	if !apiResponseModel.Success {
-		return nil, errors.New("error fetching limits: " + apiResponseModel.Error)
-	}
-	minTransferAmount, _ := new(big.Int).SetString(apiResponseModel.MinAmount, 10)
-	maxTransferAmount, _ := new(big.Int).SetString(apiResponseModel.MaxAmount, 10)
-	minTransferAmountUsd, _ := new(big.Int).SetString(apiResponseModel.MinAmountUSD, 10)
-	maxTransferAmountUsd, _ := new(big.Int).SetString(apiResponseModel.MaxAmountUSD, 10)
 	return &types.TransferLimits{
-		MinAmount:    minTransferAmount,
-		MaxAmount:    maxTransferAmount,
-		MinAmountUSD: minTransferAmountUsd,
-		MaxAmountUSD: maxTransferAmountUsd,
+		MinAmount:    apiResponse.MinTransferAmount,
+		MaxAmount:    apiResponse.MaxTransferAmount,
+		MinAmountUSD: apiResponse.MinTransferAmountUsd,
+		MaxAmountUSD: apiResponse.MaxTransferAmountUsd,
 	}, nil
 }
 
 func (sdk *SDK) GetCurrentFee(
+	ctx context.Context,
 	token types.Token,
 	sender common.Address,
 	transferId common.Address,
 	expiration int64,
 	amount *big.Int,
 ) (fee *types.ClaimLinkFee, totalAmount *big.Int, err error) {
-	feeB, err := sdk.Client.GetFee(
+	feeResp, err := sdk.Client.GetFee(
+		ctx,
 		token,
 		sender,
 		transferId,
@@ -216,49 +292,28 @@ func (sdk *SDK) GetCurrentFee(
 	if err != nil {
 		return
 	}
-	getFeeResp := &struct {
-		Success              bool           `json:"success"`
-		Error                string         `json:"string"`
-		FeeAmount            string         `json:"fee_amount"`
-		TotalAmount          string         `json:"total_amount"`
-		FeeAuthorization     string         `json:"fee_authorization"`
-		FeeToken             common.Address `json:"fee_token"`
-		MinTransferAmount    string         `json:"min_transfer_amount"`
-		MaxTransferAmount    string         `json:"max_transfer_amount"`
-		MinTransferAmountUsd string         `json:"min_transfer_amount_usd"`
-		MaxTransferAmountUsd string         `json:"max_transfer_amount_usd"`
-	}{}
-	err = json.Unmarshal(feeB, getFeeResp)
-	if !getFeeResp.Success {
-		return nil, nil, errors.New("error fetching fee: " + getFeeResp.Error)
-	}
-
-	feeAmount, _ := (&big.Int{}).SetString(getFeeResp.FeeAmount, 10)
-	totalAmount, _ = (&big.Int{}).SetString(getFeeResp.TotalAmount, 10)
-	tokenType := types.TokenTypeERC20
-	if getFeeResp.FeeToken == types.ZeroAddress {
-		tokenType = types.TokenTypeNative
-	}
-	return &types.ClaimLinkFee{
-		Token: types.Token{
-			Type:    tokenType,
-			ChainId: token.ChainId,
-			Address: getFeeResp.FeeToken,
-		},
-		Amount:        feeAmount,
-		Authorization: common.Hex2Bytes(strings.TrimPrefix(getFeeResp.FeeAuthorization, "0x")),
-	}, totalAmount, err
+	if feeResp.FeeAmount == nil || feeResp.TotalAmount == nil {
+		return nil, nil, errors.New("invalid fee response: fee_amount and total_amount are required")
+	}
+
+	claimLinkFee := feeResp.ClaimLinkFee(token.ChainId)
+	return &claimLinkFee, feeResp.TotalAmount, nil
 }
 
-// GetClaimLink creates a ClaimLink or ClaimLinkRecovered from url and returns them as IClaimLinkRedeemable
-func (sdk *SDK) GetClaimLink(claimUrl string) (redeemableClaimLink IClaimLinkRedeemable, err error) {
+// GetClaimLink creates a ClaimLink or ClaimLinkRecovered from url and returns them as IClaimLinkRedeemable.
+// The link is returned if the encrypted sender message can't be loaded, DecryptSenderMessage returns the error then
+// and LoadSenderMessage loads the message again.
+func (sdk *SDK) GetClaimLink(ctx context.Context, claimUrl string) (redeemableClaimLink IClaimLinkRedeemable, err error) {
 	linkSource, err := helpers.LinkSourceFromClaimUrl(claimUrl)
 	if err != nil {
 		return
 	}
 	if linkSource == types.LinkSourceDashboard {
-		// TODO handle
-		return nil, errors.New("not implemented yet")
+		claimCode := helpers.GetClaimCodeFromDashboardLink(claimUrl)
+		if claimCode == "" {
+			return nil, errors.New("claim code is not found in the dashboard link")
+		}
+		return sdk.ClaimLinkDashboard(ctx, claimCode)
 	}
 
 	decodedLink, err := helpers.DecodeLink(claimUrl)
@@ -266,90 +321,57 @@ func (sdk *SDK) GetClaimLink(claimUrl string) (redeemableClaimLink IClaimLinkRed
 		return
 	}
 
-	apiResp, err := sdk.Client.GetTransferStatus(decodedLink.ChainId, decodedLink.TransferId)
+	apiResp, err := sdk.Client.GetTransferStatus(ctx, decodedLink.ChainId, decodedLink.TransferId)
 	if err != nil {
 		return
 	}
+	cl := &apiResp.ClaimLink
 
-	respModel := struct {
-		ClaimLink map[string]any `json:"claim_link"`
-	}{}
-	err = json.Unmarshal(apiResp, &respModel)
-	if err != nil {
-		return
-	}
-
-	cl := respModel.ClaimLink
-	tokenType := types.TokenType(cl["token_type"].(string))
-
-	tokenId, ok := new(big.Int).SetString(cl["token_id"].(string), 10)
-	if !ok {
-		return nil, errors.New("invalid token_id")
-	}
-	if !(tokenType == types.TokenTypeERC721 || tokenType == types.TokenTypeERC1155) {
-		tokenId = nil
-	}
-
-	amount, ok := new(big.Int).SetString(cl["amount"].(string), 10)
-	if !ok {
-		return nil, errors.New("invalid amount")
-	}
-	totalAmount, ok := new(big.Int).SetString(cl["total_amount"].(string), 10)
-	if !ok {
-		return nil, errors.New("invalid total_amount")
-	}
-	feeAmount, ok := new(big.Int).SetString(cl["fee_amount"].(string), 10)
-	if !ok {
-		return nil, errors.New("invalid total_amount")
-	}
-	feeTokenAddress := common.HexToAddress(cl["sender"].(string))
-	feeTokenType := types.TokenTypeERC20
-	if feeTokenAddress == types.ZeroAddress {
-		feeTokenType = types.TokenTypeNative
+	// the URL carries only the message key, the ciphertext is stored by the API and in the escrow event
+	message := decodedLink.Message
+	var messageErr error
+	if message != nil {
+		message.Data, messageErr = sdk.senderMessage(ctx, cl)
 	}
 
 	if decodedLink.SenderSignature != nil {
 		redeemableClaimLink = &ClaimLinkRecovered{
-			SDK:        sdk,
-			TransferId: common.HexToAddress(cl["transfer_id"].(string)),
-			Sender:     common.HexToAddress(cl["sender"].(string)),
-			Token: types.Token{
-				Type:    tokenType,
-				ChainId: types.ChainId(int64(cl["chain_id"].(float64))),
-				Address: common.HexToAddress(cl["token"].(string)),
-				Id:      tokenId,
-			},
-			EscrowAddress:   common.HexToAddress(cl["escrow"].(string)),
+			SDK:             sdk,
+			TransferId:      cl.TransferId,
+			Sender:          cl.Sender,
+			Token:           cl.TokenInfo(),
+			EscrowAddress:   cl.Escrow,
+			Message:         message,
 			LinkKey:         &decodedLink.LinkKey,
 			SenderSignature: decodedLink.SenderSignature,
+			Operations:      cl.Operations,
+			Status:          cl.Status,
+			messageErr:      messageErr,
 		}
 		return
 	}
 
-	redeemableClaimLink = &ClaimLink{
-		SDK:    sdk,
-		Sender: common.HexToAddress(cl["sender"].(string)),
-		Token: types.Token{
-			Type:    tokenType,
-			ChainId: types.ChainId(int64(cl["chain_id"].(float64))),
-			Address: common.HexToAddress(cl["token"].(string)),
-			Id:      tokenId,
-		},
-		Amount:      amount,
-		TotalAmount: totalAmount,
-		Fee: &types.ClaimLinkFee{
-			Token: types.Token{
-				Type:    feeTokenType,
-				ChainId: types.ChainId(int64(cl["chain_id"].(float64))),
-				Address: common.HexToAddress(cl["sender"].(string)),
-			},
-			Amount: feeAmount,
-		},
-		Expiration:    int64(cl["expiration"].(float64)),
-		TransferId:    common.HexToAddress(cl["transfer_id"].(string)),
-		EscrowAddress: common.HexToAddress(cl["escrow"].(string)),
-		LinkKey:       &decodedLink.LinkKey,
-		Status:        types.ClaimLinkStatusFromString(cl["status"].(string)),
+	claimLink := sdk.claimLinkFromData(cl, &decodedLink.LinkKey)
+	claimLink.Message = message
+	claimLink.messageErr = messageErr
+	return claimLink, nil
+}
+
+// claimLinkFromData creates a ClaimLink from the API representation
+func (sdk *SDK) claimLinkFromData(data *ClaimLinkData, linkKey *ecdsa.PrivateKey) *ClaimLink {
+	fee := data.Fee()
+	return &ClaimLink{
+		SDK:           sdk,
+		LinkKey:       linkKey,
+		TransferId:    data.TransferId,
+		Token:         data.TokenInfo(),
+		Amount:        data.Amount,
+		Sender:        data.Sender,
+		Fee:           &fee,
+		TotalAmount:   data.TotalAmount,
+		EscrowAddress: data.Escrow,
+		Expiration:    data.Expiration,
+		Operations:    data.Operations,
+		Status:        data.Status,
 	}
-	return
 }
@@ -0,0 +1,154 @@
+package linkdrop
+
+import (
+	"bytes"
+	"context"
+	"errors"
+	"github.com/LinkdropHQ/linkdrop-go-sdk/helpers"
+	"github.com/LinkdropHQ/linkdrop-go-sdk/types"
+	"github.com/ethereum/go-ethereum/common"
+	gethtypes "github.com/ethereum/go-ethereum/core/types"
+	gethcrypto "github.com/ethereum/go-ethereum/crypto"
+	"math/big"
+	"net/http"
+	"net/http/httptest"
+	"sync/atomic"
+	"testing"
+)
+
+// newTestSDK returns the SDK calling the API served by handler
+func newTestSDK(t *testing.T, handler http.HandlerFunc, opts ...Option) *SDK {
+	t.Helper()
+	server := httptest.NewServer(handler)
+	t.Cleanup(server.Close)
+	sdk, err := Init("https://p2p.linkdrop.io", "test-key", append([]Option{
+		WithApiUrl(server.URL),
+		WithHTTPClient(server.Client()),
+	}, opts...)...)
+	if err != nil {
+		t.Fatal(err)
+	}
+	return sdk
+}
+
+// failingReceiptReader fails to read the receipts
+type failingReceiptReader struct{}
+
+func (failingReceiptReader) TransactionReceipt(ctx context.Context, txHash common.Hash) (*gethtypes.Receipt, error) {
+	return nil, errReceiptUnavailable
+}
+
+var errReceiptUnavailable = errors.New("receipt is unavailable")
+
+func TestGetClaimLinkMessageLookupFails(t *testing.T) {
+	linkKey, err := gethcrypto.GenerateKey()
+	if err != nil {
+		t.Fatal(err)
+	}
+	transferId := gethcrypto.PubkeyToAddress(linkKey.PublicKey)
+	claimLink := `"transfer_id": "` + transferId.Hex() + `", "chain_id": 8453, "token_type": "ERC20",
+		"token": "0x833589fCD6eDb6E08f4c7C32D4f71b54bdA02913", "amount": "1000", "status": "deposited",
+		"operations": [{"type": "deposit", "status": "completed", "txHash": "0x0000000000000000000000000000000000000000000000000000000000000001"}]`
+	var requests atomic.Int32
+	sdk := newTestSDK(t, func(w http.ResponseWriter, r *http.Request) {
+		if requests.Add(1) == 1 {
+			_, _ = w.Write([]byte(`{"success": true, "claim_link": {` + claimLink + `}}`))
+			return
+		}
+		_, _ = w.Write([]byte(`{"success": true, "claim_link": {` + claimLink + `, "encrypted_sender_message": "0x0102"}}`))
+	}, WithReceiptReader(types.ChainIdBase, failingReceiptReader{}))
+	claimUrl := helpers.EncodeLink("https://p2p.linkdrop.io", types.Link{
+		LinkKey:    *linkKey,
+		TransferId: transferId,
+		ChainId:    types.ChainIdBase,
+		Message:    &types.EncryptedMessage{LinkKey: "message-key"},
+	})
+
+	ctx := context.Background()
+	redeemable, err := sdk.GetClaimLink(ctx, claimUrl)
+	if err != nil {
+		t.Fatal(err)
+	}
+	link, ok := redeemable.(*ClaimLink)
+	if !ok || link.TransferId != transferId || link.Message == nil || len(link.Message.Data) != 0 {
+		t.Fatalf("unexpected link %+v", redeemable)
+	}
+	_, err = link.DecryptSenderMessage()
+	if !errors.Is(err, ErrMessageNotLoaded) || !errors.Is(err, errReceiptUnavailable) {
+		t.Fatalf("expected the message lookup error, got %v", err)
+	}
+	if err = link.LoadSenderMessage(ctx); err != nil {
+		t.Fatal(err)
+	}
+	if !bytes.Equal(link.Message.Data, []byte{1, 2}) || link.Message.LinkKey != "message-key" {
+		t.Fatalf("unexpected message %+v", link.Message)
+	}
+	if _, err = link.DecryptSenderMessage(); errors.Is(err, ErrMessageNotLoaded) {
+		t.Fatalf("unexpected error %v", err)
+	}
+}
+
+func TestSDKChainRegistryIsolated(t *testing.T) {
+	handler := func(w http.ResponseWriter, r *http.Request) {
+		t.Fatalf("unexpected request %s", r.URL)
+	}
+	sdk := newTestSDK(t, handler)
+	other := newTestSDK(t, handler)
+
+	anvil, err := types.AnvilChain(types.ChainIdBase)
+	if err != nil {
+		t.Fatal(err)
+	}
+	if err = sdk.Chains().Register(anvil); err != nil {
+		t.Fatal(err)
+	}
+	if !sdk.Chains().IsSupported(types.ChainIdAnvil) {
+		t.Fatal("expected the registered chain to be supported")
+	}
+	if other.Chains().IsSupported(types.ChainIdAnvil) || types.DefaultChainRegistry().IsSupported(types.ChainIdAnvil) {
+		t.Fatal("chain registered in one SDK is visible to others")
+	}
+	base, err := sdk.Chains().Chain(types.ChainIdBase)
+	if err != nil {
+		t.Fatal(err)
+	}
+	if anvil.EscrowAddress != base.EscrowAddress || anvil.ApiPath != "" || len(anvil.StableCoins) != 0 || !anvil.Testnet {
+		t.Fatalf("unexpected fork chain %+v", anvil)
+	}
+	if _, err = sdk.Chains().ApiHost("https://api", types.ChainIdAnvil); !errors.Is(err, ErrUnsupportedChain) {
+		t.Fatalf("expected the fork not to be served by the API, got %v", err)
+	}
+	if _, err = types.AnvilChain(types.ChainIdBaseSepolia); !errors.Is(err, ErrUnsupportedChain) {
+		t.Fatalf("expected ErrUnsupportedChain for the fork of unknown chain, got %v", err)
+	}
+}
+
+func TestClientDepositUnsupportedChain(t *testing.T) {
+	sdk := newTestSDK(t, func(w http.ResponseWriter, r *http.Request) {
+		t.Fatalf("unexpected request %s", r.URL)
+	})
+	anvil, err := types.AnvilChain(types.ChainIdBase)
+	if err != nil {
+		t.Fatal(err)
+	}
+	if err = sdk.Chains().Register(anvil); err != nil {
+		t.Fatal(err)
+	}
+	token := types.Token{Type: types.TokenTypeNative, ChainId: types.ChainIdAnvil}
+	_, err = sdk.Client.Deposit(
+		context.Background(),
+		token,
+		common.HexToAddress("0x04"),
+		anvil.EscrowAddress,
+		common.HexToAddress("0x03"),
+		0,
+		types.Transaction{},
+		types.ClaimLinkFee{},
+		big.NewInt(1),
+		big.NewInt(1),
+		nil,
+	)
+	if !errors.Is(err, ErrUnsupportedChain) {
+		t.Fatalf("expected ErrUnsupportedChain, got %v", err)
+	}
+}
@@ -0,0 +1,90 @@
+package linkdrop
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"github.com/LinkdropHQ/linkdrop-go-sdk/helpers"
+	"github.com/LinkdropHQ/linkdrop-go-sdk/types"
+	"github.com/ethereum/go-ethereum/common"
+	gethtypes "github.com/ethereum/go-ethereum/core/types"
+)
+
+// ReceiptReader is the part of *ethclient.Client used to read the SenderMessage event of the deposit transaction
+type ReceiptReader interface {
+	TransactionReceipt(ctx context.Context, txHash common.Hash) (*gethtypes.Receipt, error)
+}
+
+// senderMessage returns the encrypted sender message of the claim link.
+// The message returned by the API is used if available, otherwise it's read from the SenderMessage event
+// of the deposit transaction using the ReceiptReader of the chain. Nil is returned if the message isn't found.
+func (sdk *SDK) senderMessage(ctx context.Context, data *ClaimLinkData) ([]byte, error) {
+	if len(data.EncryptedSenderMessage) > 0 {
+		return data.EncryptedSenderMessage, nil
+	}
+	reader := sdk.config.receiptReaders[data.ChainId]
+	if reader == nil {
+		return nil, nil
+	}
+	for _, operation := range data.Operations {
+		if operation.Type != "deposit" || operation.TxHash == nil {
+			continue
+		}
+		receipt, err := reader.TransactionReceipt(ctx, *operation.TxHash)
+		if err != nil {
+			return nil, err
+		}
+		for _, log := range receipt.Logs {
+			if log == nil || log.Address != data.Escrow {
+				continue
+			}
+			event, err := DecodeEscrowEvent(*log)
+			if err != nil {
+				continue
+			}
+			if messageEvent, ok := event.(*SenderMessageEvent); ok && messageEvent.TransferId == data.TransferId {
+				return messageEvent.Message, nil
+			}
+		}
+	}
+	return nil, nil
+}
+
+// loadSenderMessage fetches the link from the API and returns message with the encrypted sender message data
+func (sdk *SDK) loadSenderMessage(
+	ctx context.Context,
+	chainId types.ChainId,
+	transferId common.Address,
+	message *types.EncryptedMessage,
+) (*types.EncryptedMessage, error) {
+	statusResp, err := sdk.Client.GetTransferStatus(ctx, chainId, transferId)
+	if err != nil {
+		return message, err
+	}
+	data, err := sdk.senderMessage(ctx, &statusResp.ClaimLink)
+	if err != nil {
+		return message, err
+	}
+	if data == nil {
+		return message, errors.New("sender message is not found")
+	}
+	if message == nil {
+		message = &types.EncryptedMessage{}
+	}
+	message.Data = data
+	return message, nil
+}
+
+// decryptSenderMessage decrypts the message, messageErr is the error of the message lookup if it failed
+func decryptSenderMessage(message *types.EncryptedMessage, messageErr error) (string, error) {
+	if message == nil {
+		return "", errors.New("message is not set")
+	}
+	if message.LinkKey == "" {
+		return "", errors.New("message link key is not set")
+	}
+	if len(message.Data) == 0 && messageErr != nil {
+		return "", fmt.Errorf("%w: %w", ErrMessageNotLoaded, messageErr)
+	}
+	return helpers.MessageDecrypt(message)
+}
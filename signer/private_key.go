@@ -0,0 +1,132 @@
+package signer
+
+import (
+	"context"
+	"crypto/ecdsa"
+	"fmt"
+	"github.com/LinkdropHQ/linkdrop-go-sdk/txmanager"
+	"github.com/LinkdropHQ/linkdrop-go-sdk/types"
+	"github.com/ethereum/go-ethereum/accounts/keystore"
+	"github.com/ethereum/go-ethereum/common"
+	gethtypes "github.com/ethereum/go-ethereum/core/types"
+	"github.com/ethereum/go-ethereum/crypto"
+	"github.com/ethereum/go-ethereum/signer/core/apitypes"
+	"math/big"
+	"os"
+	"sync"
+)
+
+// PrivateKey signs with the raw private key. Transactions are sent with the manager of the chain, see WithBackend.
+type PrivateKey struct {
+	key     *ecdsa.PrivateKey
+	address common.Address
+
+	mu       sync.RWMutex
+	managers map[types.ChainId]*txmanager.Manager
+}
+
+// NewPrivateKey creates the signer of the key
+func NewPrivateKey(key *ecdsa.PrivateKey) *PrivateKey {
+	return &PrivateKey{
+		key:      key,
+		address:  crypto.PubkeyToAddress(key.PublicKey),
+		managers: make(map[types.ChainId]*txmanager.Manager),
+	}
+}
+
+// NewPrivateKeyFromHex creates the signer of the hex encoded key, e.g. read from PRIVATE_KEY variable
+func NewPrivateKeyFromHex(hexKey string) (*PrivateKey, error) {
+	key, err := crypto.HexToECDSA(trimHexPrefix(hexKey))
+	if err != nil {
+		return nil, err
+	}
+	return NewPrivateKey(key), nil
+}
+
+// FromKeystoreFile decrypts go-ethereum keystore file with the passphrase
+func FromKeystoreFile(path string, passphrase string) (*PrivateKey, error) {
+	keyJson, err := os.ReadFile(path)
+	if err != nil {
+		return nil, err
+	}
+	key, err := keystore.DecryptKey(keyJson, passphrase)
+	if err != nil {
+		return nil, fmt.Errorf("failed to decrypt keystore: %w", err)
+	}
+	return NewPrivateKey(key.PrivateKey), nil
+}
+
+// WithBackend sets the client of the chain used to send transactions, e.g. *ethclient.Client.
+// The transactions are sent by txmanager.Manager with the default config, use WithManager to configure it.
+func (pk *PrivateKey) WithBackend(chainId types.ChainId, backend txmanager.Backend) *PrivateKey {
+	// NewWithKey fails only without the chain ID
+	manager, _ := txmanager.NewWithKey(backend, big.NewInt(int64(chainId)), pk.key, txmanager.Config{})
+	return pk.WithManager(chainId, manager)
+}
+
+// WithManager sets the transaction manager of the chain, it must be created for the key of the signer
+func (pk *PrivateKey) WithManager(chainId types.ChainId, manager *txmanager.Manager) *PrivateKey {
+	pk.mu.Lock()
+	defer pk.mu.Unlock()
+	pk.managers[chainId] = manager
+	return pk
+}
+
+// Manager returns the transaction manager of the chain, nil if the backend is not set
+func (pk *PrivateKey) Manager(chainId types.ChainId) *txmanager.Manager {
+	pk.mu.RLock()
+	defer pk.mu.RUnlock()
+	return pk.managers[chainId]
+}
+
+func (pk *PrivateKey) Address() common.Address {
+	return pk.address
+}
+
+func (pk *PrivateKey) SignTypedData(ctx context.Context, typedData apitypes.TypedData) ([]byte, error) {
+	return SignTypedData(typedData, pk.key)
+}
+
+func (pk *PrivateKey) SignMessage(ctx context.Context, message []byte) ([]byte, error) {
+	return SignMessage(message, pk.key)
+}
+
+// SendTransaction sends EIP-1559 transaction, or the legacy one if the chain doesn't support it.
+// The nonces of the concurrent transactions are assigned by the manager of the chain.
+func (pk *PrivateKey) SendTransaction(
+	ctx context.Context,
+	chainId *big.Int,
+	to common.Address,
+	value *big.Int,
+	data []byte,
+) (transaction *types.Transaction, err error) {
+	manager, err := pk.manager(chainId)
+	if err != nil {
+		return
+	}
+	return manager.SendTransaction(ctx, chainId, to, value, data)
+}
+
+// WaitForReceipt implements types.ReceiptWaiter, see txmanager.Manager.WaitForReceipt
+func (pk *PrivateKey) WaitForReceipt(ctx context.Context, chainId *big.Int, txHash common.Hash) (*gethtypes.Receipt, error) {
+	manager, err := pk.manager(chainId)
+	if err != nil {
+		return nil, err
+	}
+	return manager.WaitForReceipt(ctx, chainId, txHash)
+}
+
+func (pk *PrivateKey) manager(chainId *big.Int) (*txmanager.Manager, error) {
+	manager := pk.Manager(types.ChainId(chainId.Int64()))
+	if manager == nil {
+		return nil, fmt.Errorf("backend is not set for chain %s", chainId)
+	}
+	return manager, nil
+}
+
+func trimHexPrefix(s string) string {
+	if len(s) >= 2 && s[0] == '0' && (s[1] == 'x' || s[1] == 'X') {
+		return s[2:]
+	}
+	return s
+}
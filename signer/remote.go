@@ -0,0 +1,138 @@
+package signer
+
+import (
+	"context"
+	"encoding/json"
+	"errors"
+	"github.com/LinkdropHQ/linkdrop-go-sdk/types"
+	"github.com/ethereum/go-ethereum/common"
+	"github.com/ethereum/go-ethereum/common/hexutil"
+	"github.com/ethereum/go-ethereum/rpc"
+	"github.com/ethereum/go-ethereum/signer/core/apitypes"
+	"math/big"
+	"time"
+)
+
+// Remote signs with the account of a JSON-RPC signer, e.g. Clef, a node with unlocked accounts or a custody service.
+// The typed data is signed with eth_signTypedData_v4, messages with personal_sign,
+// the transactions are signed and sent with eth_sendTransaction.
+type Remote struct {
+	client  *rpc.Client
+	address common.Address
+	timeout time.Duration
+}
+
+// NewRemote connects to the signer at url. If address is zero, the first account of eth_accounts is used.
+func NewRemote(ctx context.Context, url string, address common.Address) (*Remote, error) {
+	client, err := rpc.DialContext(ctx, url)
+	if err != nil {
+		return nil, err
+	}
+	remote, err := NewRemoteWithClient(ctx, client, address)
+	if err != nil {
+		client.Close()
+		return nil, err
+	}
+	return remote, nil
+}
+
+// NewRemoteWithClient creates the signer using the connected client, see NewRemote
+func NewRemoteWithClient(ctx context.Context, client *rpc.Client, address common.Address) (*Remote, error) {
+	if address == types.ZeroAddress {
+		var accounts []common.Address
+		if err := client.CallContext(ctx, &accounts, "eth_accounts"); err != nil {
+			return nil, err
+		}
+		if len(accounts) == 0 {
+			return nil, errors.New("remote signer has no accounts")
+		}
+		address = accounts[0]
+	}
+	return &Remote{
+		client:  client,
+		address: address,
+		timeout: time.Minute,
+	}, nil
+}
+
+// WithTimeout sets the timeout of the signer requests, 1 minute by default.
+// Increase it if the requests are confirmed manually.
+func (r *Remote) WithTimeout(timeout time.Duration) *Remote {
+	r.timeout = timeout
+	return r
+}
+
+// Close closes the connection to the signer
+func (r *Remote) Close() {
+	r.client.Close()
+}
+
+func (r *Remote) Address() common.Address {
+	return r.address
+}
+
+func (r *Remote) SignTypedData(ctx context.Context, typedData apitypes.TypedData) ([]byte, error) {
+	// eth_signTypedData_v4 takes typed data as an object or its JSON string, the string is accepted more widely
+	typedDataJson, err := json.Marshal(typedData)
+	if err != nil {
+		return nil, err
+	}
+	var signature hexutil.Bytes
+	err = r.call(ctx, &signature, "eth_signTypedData_v4", r.address, string(typedDataJson))
+	if err != nil {
+		return nil, err
+	}
+	return normalizeV(signature)
+}
+
+func (r *Remote) SignMessage(ctx context.Context, message []byte) ([]byte, error) {
+	var signature hexutil.Bytes
+	err := r.call(ctx, &signature, "personal_sign", hexutil.Bytes(message), r.address)
+	if err != nil {
+		return nil, err
+	}
+	return normalizeV(signature)
+}
+
+type remoteTransaction struct {
+	From    common.Address `json:"from"`
+	To      common.Address `json:"to"`
+	Value   *hexutil.Big   `json:"value"`
+	Data    hexutil.Bytes  `json:"data"`
+	ChainId *hexutil.Big   `json:"chainId"`
+}
+
+// SendTransaction sends the transaction with eth_sendTransaction, the gas and the nonce are set by the signer
+func (r *Remote) SendTransaction(
+	ctx context.Context,
+	chainId *big.Int,
+	to common.Address,
+	value *big.Int,
+	data []byte,
+) (transaction *types.Transaction, err error) {
+	if value == nil {
+		value = new(big.Int)
+	}
+	var hash common.Hash
+	err = r.call(ctx, &hash, "eth_sendTransaction", remoteTransaction{
+		From:    r.address,
+		To:      to,
+		Value:   (*hexutil.Big)(value),
+		Data:    data,
+		ChainId: (*hexutil.Big)(chainId),
+	})
+	if err != nil {
+		return
+	}
+	return &types.Transaction{
+		Hash: hash,
+		Type: types.TransactionTypeTx,
+	}, nil
+}
+
+// call calls the signer within the timeout of the signer requests
+func (r *Remote) call(ctx context.Context, result any, method string, args ...any) error {
+	ctx, cancel := context.WithTimeout(ctx, r.timeout)
+	defer cancel()
+	return r.client.CallContext(ctx, result, method, args...)
+}
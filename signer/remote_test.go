@@ -0,0 +1,150 @@
+package signer
+
+import (
+	"bytes"
+	"context"
+	"encoding/json"
+	"errors"
+	"github.com/ethereum/go-ethereum/common"
+	"github.com/ethereum/go-ethereum/common/hexutil"
+	"github.com/ethereum/go-ethereum/signer/core/apitypes"
+	"math/big"
+	"net/http"
+	"net/http/httptest"
+	"testing"
+	"time"
+)
+
+var testRemoteAccount = common.HexToAddress("0x00000000000000000000000000000000000000a1")
+
+type rpcRequest struct {
+	Id     json.RawMessage   `json:"id"`
+	Method string            `json:"method"`
+	Params []json.RawMessage `json:"params"`
+}
+
+// newRemoteServer serves the JSON-RPC signer, handle returns the result of the request
+func newRemoteServer(t *testing.T, handle func(r *http.Request, request rpcRequest) any) string {
+	t.Helper()
+	server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		var request rpcRequest
+		if err := json.NewDecoder(r.Body).Decode(&request); err != nil {
+			t.Errorf("failed to decode request: %v", err)
+			return
+		}
+		result, err := json.Marshal(handle(r, request))
+		if err != nil {
+			t.Errorf("failed to encode result: %v", err)
+			return
+		}
+		w.Header().Set("Content-Type", "application/json")
+		_ = json.NewEncoder(w).Encode(map[string]any{
+			"jsonrpc": "2.0",
+			"id":      request.Id,
+			"result":  json.RawMessage(result),
+		})
+	}))
+	t.Cleanup(server.Close)
+	return server.URL
+}
+
+func TestRemote(t *testing.T) {
+	signature := make([]byte, 65)
+	signature[64] = 1
+	txHash := common.HexToHash("0x01")
+	var requests []rpcRequest
+	url := newRemoteServer(t, func(r *http.Request, request rpcRequest) any {
+		requests = append(requests, request)
+		switch request.Method {
+		case "eth_accounts":
+			return []common.Address{testRemoteAccount}
+		case "eth_signTypedData_v4", "personal_sign":
+			return hexutil.Bytes(signature)
+		case "eth_sendTransaction":
+			return txHash
+		}
+		t.Errorf("unexpected method %s", request.Method)
+		return nil
+	})
+
+	ctx := context.Background()
+	remote, err := NewRemote(ctx, url, common.Address{})
+	if err != nil {
+		t.Fatal(err)
+	}
+	defer remote.Close()
+	if remote.Address() != testRemoteAccount {
+		t.Fatalf("expected the first account, got %s", remote.Address())
+	}
+
+	typedData := apitypes.TypedData{
+		Types:       apitypes.Types{"EIP712Domain": {{Name: "name", Type: "string"}}},
+		PrimaryType: "EIP712Domain",
+		Domain:      apitypes.TypedDataDomain{Name: "Test"},
+		Message:     map[string]any{},
+	}
+	got, err := remote.SignTypedData(ctx, typedData)
+	if err != nil {
+		t.Fatal(err)
+	}
+	if got[64] != 28 {
+		t.Fatalf("expected V to be normalized, got %d", got[64])
+	}
+	got, err = remote.SignMessage(ctx, []byte("message"))
+	if err != nil {
+		t.Fatal(err)
+	}
+	if got[64] != 28 {
+		t.Fatalf("expected V to be normalized, got %d", got[64])
+	}
+	transaction, err := remote.SendTransaction(ctx, big.NewInt(8453), common.HexToAddress("0x02"), nil, []byte{1})
+	if err != nil {
+		t.Fatal(err)
+	}
+	if transaction.Hash != txHash {
+		t.Fatalf("unexpected transaction %+v", transaction)
+	}
+
+	if len(requests) != 4 {
+		t.Fatalf("unexpected requests %+v", requests)
+	}
+	var typedDataJson string
+	if err = json.Unmarshal(requests[1].Params[1], &typedDataJson); err != nil {
+		t.Fatalf("expected the typed data as JSON string: %v", err)
+	}
+	var messageParam hexutil.Bytes
+	if err = json.Unmarshal(requests[2].Params[0], &messageParam); err != nil || !bytes.Equal(messageParam, []byte("message")) {
+		t.Fatalf("unexpected personal_sign params %s", requests[2].Params)
+	}
+	var tx remoteTransaction
+	if err = json.Unmarshal(requests[3].Params[0], &tx); err != nil {
+		t.Fatal(err)
+	}
+	if tx.From != testRemoteAccount || tx.ChainId.ToInt().Int64() != 8453 || tx.Value.ToInt().Sign() != 0 {
+		t.Fatalf("unexpected transaction params %+v", tx)
+	}
+}
+
+func TestRemoteUsesCallerContext(t *testing.T) {
+	url := newRemoteServer(t, func(r *http.Request, request rpcRequest) any {
+		// the request is answered only after the client gives up
+		<-r.Context().Done()
+		return nil
+	})
+	remote, err := NewRemote(context.Background(), url, testRemoteAccount)
+	if err != nil {
+		t.Fatal(err)
+	}
+	defer remote.Close()
+
+	ctx, cancel := context.WithTimeout(context.Background(), 50*time.Millisecond)
+	defer cancel()
+	start := time.Now()
+	_, err = remote.SignMessage(ctx, []byte("message"))
+	if !errors.Is(err, context.DeadlineExceeded) {
+		t.Fatalf("expected the caller deadline, got %v", err)
+	}
+	if time.Since(start) > 5*time.Second {
+		t.Fatal("the request ignored the caller context")
+	}
+}
@@ -0,0 +1,54 @@
+// Package signer provides types.Signer implementations: a raw private key (PrivateKey),
+// a go-ethereum keystore file (FromKeystoreFile) and a remote JSON-RPC signer (Remote)
+package signer
+
+import (
+	"crypto/ecdsa"
+	"fmt"
+	"github.com/ethereum/go-ethereum/accounts"
+	"github.com/ethereum/go-ethereum/crypto"
+	"github.com/ethereum/go-ethereum/signer/core/apitypes"
+)
+
+// TypedDataHash returns EIP-712 hash of the typed data
+func TypedDataHash(typedData apitypes.TypedData) ([]byte, error) {
+	hash, _, err := apitypes.TypedDataAndHash(typedData)
+	if err != nil {
+		return nil, fmt.Errorf("failed to hash typed data: %w", err)
+	}
+	return hash, nil
+}
+
+// SignTypedData signs EIP-712 hash of the typed data with the key, V is 27 or 28
+func SignTypedData(typedData apitypes.TypedData, privateKey *ecdsa.PrivateKey) ([]byte, error) {
+	hash, err := TypedDataHash(typedData)
+	if err != nil {
+		return nil, err
+	}
+	return signHash(hash, privateKey)
+}
+
+// SignMessage signs the message with EIP-191 prefix, V is 27 or 28
+func SignMessage(message []byte, privateKey *ecdsa.PrivateKey) ([]byte, error) {
+	return signHash(accounts.TextHash(message), privateKey)
+}
+
+func signHash(hash []byte, privateKey *ecdsa.PrivateKey) ([]byte, error) {
+	signature, err := crypto.Sign(hash, privateKey)
+	if err != nil {
+		return nil, fmt.Errorf("failed to sign: %w", err)
+	}
+	signature[64] += 27
+	return signature, nil
+}
+
+// normalizeV converts V of the signature returned by a remote signer to 27 or 28
+func normalizeV(signature []byte) ([]byte, error) {
+	if len(signature) != crypto.SignatureLength {
+		return nil, fmt.Errorf("invalid signature length %d", len(signature))
+	}
+	if signature[64] < 27 {
+		signature[64] += 27
+	}
+	return signature, nil
+}
@@ -0,0 +1,107 @@
+package store
+
+import (
+	"context"
+	"encoding/json"
+	"errors"
+	"github.com/LinkdropHQ/linkdrop-go-sdk/types"
+	"github.com/ethereum/go-ethereum/common"
+	"maps"
+	"os"
+	"path/filepath"
+)
+
+// File is a LinkStore keeping the records in a JSON file.
+// The whole file is rewritten atomically on every change, so it fits for a moderate number of links.
+// It's safe for concurrent use within a single process.
+type File struct {
+	memory *Memory
+	path   string
+}
+
+// NewFile opens the store at path, the file is created on the first change if it doesn't exist
+func NewFile(path string) (*File, error) {
+	f := &File{memory: NewMemory(), path: path}
+	data, err := os.ReadFile(path)
+	if errors.Is(err, os.ErrNotExist) {
+		return f, nil
+	}
+	if err != nil {
+		return nil, err
+	}
+	if len(data) == 0 {
+		return f, nil
+	}
+	var records []*Record
+	if err = json.Unmarshal(data, &records); err != nil {
+		return nil, err
+	}
+	for _, record := range records {
+		f.memory.records[record.TransferId] = record
+	}
+	return f, nil
+}
+
+func (f *File) Put(ctx context.Context, record *Record) error {
+	f.memory.mu.Lock()
+	defer f.memory.mu.Unlock()
+	return f.update(func(next *Memory) error {
+		next.put(record)
+		return nil
+	})
+}
+
+func (f *File) Get(ctx context.Context, transferId common.Address) (*Record, error) {
+	return f.memory.Get(ctx, transferId)
+}
+
+func (f *File) List(ctx context.Context, filter Filter) ([]*Record, error) {
+	return f.memory.List(ctx, filter)
+}
+
+func (f *File) UpdateStatus(ctx context.Context, transferId common.Address, status types.ClaimLinkStatus) error {
+	f.memory.mu.Lock()
+	defer f.memory.mu.Unlock()
+	return f.update(func(next *Memory) error {
+		return next.updateStatus(transferId, status)
+	})
+}
+
+// update applies change to a copy of the records and keeps the copy only if it's saved, so a failed write
+// doesn't leave the changes visible in memory. The caller must hold the lock.
+func (f *File) update(change func(next *Memory) error) error {
+	next := &Memory{records: maps.Clone(f.memory.records)}
+	if err := change(next); err != nil {
+		return err
+	}
+	if err := f.save(next); err != nil {
+		return err
+	}
+	f.memory.records = next.records
+	return nil
+}
+
+// save writes all records to a temporary file and renames it over the store file
+func (f *File) save(records *Memory) error {
+	data, err := json.MarshalIndent(records.list(Filter{}), "", "  ")
+	if err != nil {
+		return err
+	}
+	tmp, err := os.CreateTemp(filepath.Dir(f.path), filepath.Base(f.path)+".*.tmp")
+	if err != nil {
+		return err
+	}
+	defer os.Remove(tmp.Name())
+	if _, err = tmp.Write(data); err != nil {
+		tmp.Close()
+		return err
+	}
+	if err = tmp.Sync(); err != nil {
+		tmp.Close()
+		return err
+	}
+	if err = tmp.Close(); err != nil {
+		return err
+	}
+	return os.Rename(tmp.Name(), f.path)
+}
@@ -0,0 +1,97 @@
+package store
+
+import (
+	"context"
+	"github.com/LinkdropHQ/linkdrop-go-sdk/types"
+	"github.com/ethereum/go-ethereum/common"
+	"slices"
+	"sync"
+	"time"
+)
+
+// Memory is a LinkStore keeping the records in memory. It's safe for concurrent use.
+type Memory struct {
+	mu      sync.RWMutex
+	records map[common.Address]*Record
+}
+
+func NewMemory() *Memory {
+	return &Memory{records: make(map[common.Address]*Record)}
+}
+
+func (m *Memory) Put(ctx context.Context, record *Record) error {
+	m.mu.Lock()
+	defer m.mu.Unlock()
+	m.put(record)
+	return nil
+}
+
+func (m *Memory) Get(ctx context.Context, transferId common.Address) (*Record, error) {
+	m.mu.RLock()
+	defer m.mu.RUnlock()
+	record, ok := m.records[transferId]
+	if !ok {
+		return nil, ErrNotFound
+	}
+	return record.clone(), nil
+}
+
+func (m *Memory) List(ctx context.Context, filter Filter) ([]*Record, error) {
+	m.mu.RLock()
+	defer m.mu.RUnlock()
+	return m.list(filter), nil
+}
+
+func (m *Memory) UpdateStatus(ctx context.Context, transferId common.Address, status types.ClaimLinkStatus) error {
+	m.mu.Lock()
+	defer m.mu.Unlock()
+	return m.updateStatus(transferId, status)
+}
+
+// put stores a copy of the record keeping CreatedAt of the replaced one. The caller must hold the lock.
+func (m *Memory) put(record *Record) {
+	stored := record.clone()
+	now := time.Now()
+	if existing, ok := m.records[record.TransferId]; ok {
+		stored.CreatedAt = existing.CreatedAt
+	} else if stored.CreatedAt.IsZero() {
+		stored.CreatedAt = now
+	}
+	stored.UpdatedAt = now
+	m.records[record.TransferId] = stored
+}
+
+func (m *Memory) list(filter Filter) []*Record {
+	records := make([]*Record, 0)
+	for _, record := range m.records {
+		if filter.Match(record) {
+			records = append(records, record.clone())
+		}
+	}
+	slices.SortFunc(records, func(a, b *Record) int {
+		if c := a.CreatedAt.Compare(b.CreatedAt); c != 0 {
+			return c
+		}
+		return a.TransferId.Cmp(b.TransferId)
+	})
+	if filter.Offset > 0 {
+		records = records[min(filter.Offset, len(records)):]
+	}
+	if filter.Limit > 0 && len(records) > filter.Limit {
+		records = records[:filter.Limit]
+	}
+	return records
+}
+
+// updateStatus replaces the record with the updated copy. The caller must hold the lock.
+func (m *Memory) updateStatus(transferId common.Address, status types.ClaimLinkStatus) error {
+	record, ok := m.records[transferId]
+	if !ok {
+		return ErrNotFound
+	}
+	updated := record.clone()
+	updated.Status = status
+	updated.UpdatedAt = time.Now()
+	m.records[transferId] = updated
+	return nil
+}
@@ -0,0 +1,224 @@
+package store
+
+import (
+	"context"
+	"database/sql"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"github.com/LinkdropHQ/linkdrop-go-sdk/types"
+	"github.com/ethereum/go-ethereum/common"
+	"math"
+	"math/big"
+	"strings"
+	"time"
+)
+
+// Placeholder formats the n-th (starting from 1) query parameter placeholder of the SQL dialect
+type Placeholder func(n int) string
+
+// QuestionPlaceholder is used by SQLite drivers
+func QuestionPlaceholder(n int) string {
+	return "?"
+}
+
+// DollarPlaceholder is used by PostgreSQL drivers
+func DollarPlaceholder(n int) string {
+	return fmt.Sprintf("$%d", n)
+}
+
+// SQL is a LinkStore backed by database/sql.
+// The upsert relies on INSERT ... ON CONFLICT supported by SQLite 3.24+ and PostgreSQL.
+// Use CreateTable to create the table or create it with the same columns manually.
+type SQL struct {
+	db          *sql.DB
+	table       string
+	placeholder Placeholder
+}
+
+// NewSQL creates the store using the table in db. QuestionPlaceholder is used if placeholder is nil.
+func NewSQL(db *sql.DB, table string, placeholder Placeholder) *SQL {
+	if placeholder == nil {
+		placeholder = QuestionPlaceholder
+	}
+	return &SQL{db: db, table: table, placeholder: placeholder}
+}
+
+// CreateTable creates the store table if it doesn't exist
+func (s *SQL) CreateTable(ctx context.Context) error {
+	_, err := s.db.ExecContext(ctx, `CREATE TABLE IF NOT EXISTS `+s.table+` (
+	transfer_id TEXT PRIMARY KEY,
+	chain_id    BIGINT NOT NULL,
+	sender      TEXT NOT NULL,
+	token       TEXT NOT NULL,
+	token_type  TEXT NOT NULL,
+	amount      TEXT NOT NULL,
+	status      TEXT NOT NULL,
+	snapshot    TEXT NOT NULL,
+	created_at  BIGINT NOT NULL,
+	updated_at  BIGINT NOT NULL
+)`)
+	return err
+}
+
+func (s *SQL) Put(ctx context.Context, record *Record) error {
+	now := time.Now()
+	createdAt := record.CreatedAt
+	if createdAt.IsZero() {
+		createdAt = now
+	}
+	amount := ""
+	if record.Amount != nil {
+		amount = record.Amount.String()
+	}
+	_, err := s.db.ExecContext(
+		ctx,
+		`INSERT INTO `+s.table+` (transfer_id, chain_id, sender, token, token_type, amount, status, snapshot, created_at, updated_at)
+VALUES (`+s.placeholders(1, 10)+`)
+ON CONFLICT (transfer_id) DO UPDATE SET
+	chain_id = excluded.chain_id,
+	sender = excluded.sender,
+	token = excluded.token,
+	token_type = excluded.token_type,
+	amount = excluded.amount,
+	status = excluded.status,
+	snapshot = excluded.snapshot,
+	updated_at = excluded.updated_at`,
+		record.TransferId.Hex(),
+		int64(record.ChainId),
+		record.Sender.Hex(),
+		record.Token.Hex(),
+		string(record.TokenType),
+		amount,
+		record.Status.String(),
+		string(record.Snapshot),
+		createdAt.UnixMilli(),
+		now.UnixMilli(),
+	)
+	return err
+}
+
+func (s *SQL) Get(ctx context.Context, transferId common.Address) (*Record, error) {
+	row := s.db.QueryRowContext(
+		ctx,
+		`SELECT `+sqlColumns+` FROM `+s.table+` WHERE transfer_id = `+s.placeholder(1),
+		transferId.Hex(),
+	)
+	record, err := scanRecord(row)
+	if errors.Is(err, sql.ErrNoRows) {
+		return nil, ErrNotFound
+	}
+	return record, err
+}
+
+func (s *SQL) List(ctx context.Context, filter Filter) ([]*Record, error) {
+	var conditions []string
+	var args []any
+	if filter.ChainId != 0 {
+		args = append(args, int64(filter.ChainId))
+		conditions = append(conditions, "chain_id = "+s.placeholder(len(args)))
+	}
+	if filter.Sender != nil {
+		args = append(args, filter.Sender.Hex())
+		conditions = append(conditions, "sender = "+s.placeholder(len(args)))
+	}
+	if len(filter.Statuses) > 0 {
+		for _, status := range filter.Statuses {
+			args = append(args, status.String())
+		}
+		conditions = append(conditions, "status IN ("+s.placeholders(len(args)-len(filter.Statuses)+1, len(filter.Statuses))+")")
+	}
+
+	query := `SELECT ` + sqlColumns + ` FROM ` + s.table
+	if len(conditions) > 0 {
+		query += " WHERE " + strings.Join(conditions, " AND ")
+	}
+	query += " ORDER BY created_at, transfer_id"
+	if filter.Limit > 0 {
+		query += fmt.Sprintf(" LIMIT %d", filter.Limit)
+	}
+	if filter.Offset > 0 {
+		if filter.Limit <= 0 {
+			// SQLite doesn't accept OFFSET without LIMIT
+			query += fmt.Sprintf(" LIMIT %d", math.MaxInt64)
+		}
+		query += fmt.Sprintf(" OFFSET %d", filter.Offset)
+	}
+
+	rows, err := s.db.QueryContext(ctx, query, args...)
+	if err != nil {
+		return nil, err
+	}
+	defer rows.Close()
+	records := make([]*Record, 0)
+	for rows.Next() {
+		record, err := scanRecord(rows)
+		if err != nil {
+			return nil, err
+		}
+		records = append(records, record)
+	}
+	return records, rows.Err()
+}
+
+func (s *SQL) UpdateStatus(ctx context.Context, transferId common.Address, status types.ClaimLinkStatus) error {
+	result, err := s.db.ExecContext(
+		ctx,
+		`UPDATE `+s.table+` SET status = `+s.placeholder(1)+`, updated_at = `+s.placeholder(2)+` WHERE transfer_id = `+s.placeholder(3),
+		status.String(),
+		time.Now().UnixMilli(),
+		transferId.Hex(),
+	)
+	if err != nil {
+		return err
+	}
+	affected, err := result.RowsAffected()
+	if err != nil {
+		return err
+	}
+	if affected == 0 {
+		return ErrNotFound
+	}
+	return nil
+}
+
+// placeholders returns count comma separated placeholders starting from the from-th parameter
+func (s *SQL) placeholders(from int, count int) string {
+	placeholders := make([]string, count)
+	for i := range placeholders {
+		placeholders[i] = s.placeholder(from + i)
+	}
+	return strings.Join(placeholders, ", ")
+}
+
+const sqlColumns = "transfer_id, chain_id, sender, token, token_type, amount, status, snapshot, created_at, updated_at"
+
+func scanRecord(row interface{ Scan(dest ...any) error }) (*Record, error) {
+	var (
+		record                          Record
+		transferId, sender, token       string
+		tokenType, amount, status, snap string
+		chainId, createdAt, updatedAt   int64
+	)
+	err := row.Scan(&transferId, &chainId, &sender, &token, &tokenType, &amount, &status, &snap, &createdAt, &updatedAt)
+	if err != nil {
+		return nil, err
+	}
+	record.TransferId = common.HexToAddress(transferId)
+	record.ChainId = types.ChainId(chainId)
+	record.Sender = common.HexToAddress(sender)
+	record.Token = common.HexToAddress(token)
+	record.TokenType = types.TokenType(tokenType)
+	if amount != "" {
+		var ok bool
+		record.Amount, ok = new(big.Int).SetString(amount, 10)
+		if !ok {
+			return nil, errors.New("invalid stored amount: " + amount)
+		}
+	}
+	record.Status = types.ClaimLinkStatusFromString(status)
+	record.Snapshot = json.RawMessage(snap)
+	record.CreatedAt = time.UnixMilli(createdAt)
+	record.UpdatedAt = time.UnixMilli(updatedAt)
+	return &record, nil
+}
@@ -0,0 +1,76 @@
+// Package store provides persistence of claim links between the SDK calls.
+// Use linkdrop.WithLinkStore to let the SDK persist the claim link state transitions automatically.
+package store
+
+import (
+	"context"
+	"encoding/json"
+	"errors"
+	"github.com/LinkdropHQ/linkdrop-go-sdk/types"
+	"github.com/ethereum/go-ethereum/common"
+	"math/big"
+	"slices"
+	"time"
+)
+
+var ErrNotFound = errors.New("claim link is not found in the store")
+
+// LinkStore persists claim links by their TransferId
+type LinkStore interface {
+	// Put inserts the record or replaces the stored one with the same TransferId
+	Put(ctx context.Context, record *Record) error
+	// Get returns the record by TransferId or ErrNotFound
+	Get(ctx context.Context, transferId common.Address) (*Record, error)
+	// List returns the records matching the filter ordered by CreatedAt
+	List(ctx context.Context, filter Filter) ([]*Record, error)
+	// UpdateStatus updates the status of the stored record or returns ErrNotFound
+	UpdateStatus(ctx context.Context, transferId common.Address, status types.ClaimLinkStatus) error
+}
+
+// Record is a stored claim link.
+// The indexed fields duplicate the Snapshot content to allow filtering without decoding it.
+type Record struct {
+	TransferId common.Address        `json:"transferId"`
+	ChainId    types.ChainId         `json:"chainId"`
+	Sender     common.Address        `json:"sender"`
+	Token      common.Address        `json:"token"`
+	TokenType  types.TokenType       `json:"tokenType"`
+	Amount     *big.Int              `json:"amount"`
+	Status     types.ClaimLinkStatus `json:"status"`
+	Snapshot   json.RawMessage       `json:"snapshot"` // Snapshot - JSON encoded linkdrop.ClaimLinkSnapshot
+	CreatedAt  time.Time             `json:"createdAt"`
+	UpdatedAt  time.Time             `json:"updatedAt"`
+}
+
+// Filter limits the records returned by LinkStore.List. Zero fields are ignored.
+type Filter struct {
+	ChainId  types.ChainId
+	Sender   *common.Address
+	Statuses []types.ClaimLinkStatus
+	Limit    int
+	Offset   int
+}
+
+// Match reports whether the record matches the filter conditions, Limit and Offset are not considered
+func (f *Filter) Match(record *Record) bool {
+	if f.ChainId != 0 && record.ChainId != f.ChainId {
+		return false
+	}
+	if f.Sender != nil && record.Sender != *f.Sender {
+		return false
+	}
+	if len(f.Statuses) > 0 && !slices.Contains(f.Statuses, record.Status) {
+		return false
+	}
+	return true
+}
+
+// clone returns a deep copy of the record so the stored one can't be modified by the caller
+func (r *Record) clone() *Record {
+	c := *r
+	if r.Amount != nil {
+		c.Amount = new(big.Int).Set(r.Amount)
+	}
+	c.Snapshot = slices.Clone(r.Snapshot)
+	return &c
+}
@@ -0,0 +1,244 @@
+package store
+
+import (
+	"context"
+	"database/sql"
+	"encoding/json"
+	"errors"
+	"github.com/LinkdropHQ/linkdrop-go-sdk/types"
+	"github.com/ethereum/go-ethereum/common"
+	"math/big"
+	_ "modernc.org/sqlite"
+	"path/filepath"
+	"testing"
+	"time"
+)
+
+var (
+	testSender      = common.HexToAddress("0x00000000000000000000000000000000000000a1")
+	testOtherSender = common.HexToAddress("0x00000000000000000000000000000000000000a2")
+)
+
+func testRecord(n int64, chainId types.ChainId, sender common.Address, status types.ClaimLinkStatus) *Record {
+	return &Record{
+		TransferId: common.BigToAddress(big.NewInt(n)),
+		ChainId:    chainId,
+		Sender:     sender,
+		Token:      common.HexToAddress("0x833589fCD6eDb6E08f4c7C32D4f71b54bdA02913"),
+		TokenType:  types.TokenTypeERC20,
+		Amount:     big.NewInt(n * 1000),
+		Status:     status,
+		Snapshot:   json.RawMessage(`{"version":1}`),
+		CreatedAt:  time.UnixMilli(1700000000000 + n),
+	}
+}
+
+func newSQLiteStore(t *testing.T) LinkStore {
+	t.Helper()
+	db, err := sql.Open("sqlite", ":memory:")
+	if err != nil {
+		t.Fatal(err)
+	}
+	// every connection opens its own in-memory database
+	db.SetMaxOpenConns(1)
+	t.Cleanup(func() { _ = db.Close() })
+	s := NewSQL(db, "claim_links", QuestionPlaceholder)
+	if err = s.CreateTable(context.Background()); err != nil {
+		t.Fatal(err)
+	}
+	return s
+}
+
+func newFileStore(t *testing.T) LinkStore {
+	t.Helper()
+	f, err := NewFile(filepath.Join(t.TempDir(), "links.json"))
+	if err != nil {
+		t.Fatal(err)
+	}
+	return f
+}
+
+func TestLinkStores(t *testing.T) {
+	stores := []struct {
+		name     string
+		newStore func(t *testing.T) LinkStore
+	}{
+		{name: "memory", newStore: func(t *testing.T) LinkStore { return NewMemory() }},
+		{name: "file", newStore: newFileStore},
+		{name: "sqlite", newStore: newSQLiteStore},
+	}
+	for _, s := range stores {
+		t.Run(s.name, func(t *testing.T) {
+			testLinkStore(t, s.newStore(t))
+		})
+	}
+}
+
+// testLinkStore checks the LinkStore contract shared by all backends
+func testLinkStore(t *testing.T, linkStore LinkStore) {
+	ctx := context.Background()
+	records := []*Record{
+		testRecord(1, types.ChainIdBase, testSender, types.ClaimLinkStatusCreated),
+		testRecord(2, types.ChainIdBase, testOtherSender, types.ClaimLinkStatusDeposited),
+		testRecord(3, types.ChainIdPolygon, testSender, types.ClaimLinkStatusDeposited),
+		testRecord(4, types.ChainIdBase, testSender, types.ClaimLinkStatusRedeemed),
+	}
+	for _, record := range records {
+		if err := linkStore.Put(ctx, record); err != nil {
+			t.Fatal(err)
+		}
+	}
+
+	got, err := linkStore.Get(ctx, records[1].TransferId)
+	if err != nil {
+		t.Fatal(err)
+	}
+	if got.TransferId != records[1].TransferId ||
+		got.ChainId != records[1].ChainId ||
+		got.Sender != records[1].Sender ||
+		got.Token != records[1].Token ||
+		got.TokenType != records[1].TokenType ||
+		got.Amount.Cmp(records[1].Amount) != 0 ||
+		got.Status != records[1].Status ||
+		string(got.Snapshot) != string(records[1].Snapshot) ||
+		!got.CreatedAt.Equal(records[1].CreatedAt) {
+		t.Fatalf("unexpected record %+v", got)
+	}
+	got.Amount.SetInt64(0)
+	if got, _ = linkStore.Get(ctx, records[1].TransferId); got.Amount.Cmp(records[1].Amount) != 0 {
+		t.Fatal("stored record is modified through the returned one")
+	}
+	if _, err = linkStore.Get(ctx, common.HexToAddress("0xff")); !errors.Is(err, ErrNotFound) {
+		t.Fatalf("expected ErrNotFound, got %v", err)
+	}
+
+	sender := testSender
+	filters := []struct {
+		name   string
+		filter Filter
+		want   []int64
+	}{
+		{name: "all", want: []int64{1, 2, 3, 4}},
+		{name: "chain", filter: Filter{ChainId: types.ChainIdBase}, want: []int64{1, 2, 4}},
+		{name: "sender", filter: Filter{Sender: &sender}, want: []int64{1, 3, 4}},
+		{
+			name:   "statuses",
+			filter: Filter{Statuses: []types.ClaimLinkStatus{types.ClaimLinkStatusDeposited, types.ClaimLinkStatusRedeemed}},
+			want:   []int64{2, 3, 4},
+		},
+		{name: "chain and sender", filter: Filter{ChainId: types.ChainIdBase, Sender: &sender}, want: []int64{1, 4}},
+		{name: "limit", filter: Filter{Limit: 2}, want: []int64{1, 2}},
+		{name: "offset", filter: Filter{Offset: 3}, want: []int64{4}},
+		{name: "limit and offset", filter: Filter{Limit: 2, Offset: 1}, want: []int64{2, 3}},
+		{name: "offset past the end", filter: Filter{Offset: 10}, want: []int64{}},
+	}
+	for _, tt := range filters {
+		t.Run("list "+tt.name, func(t *testing.T) {
+			listed, err := linkStore.List(ctx, tt.filter)
+			if err != nil {
+				t.Fatal(err)
+			}
+			if len(listed) != len(tt.want) {
+				t.Fatalf("got %d records, want %d", len(listed), len(tt.want))
+			}
+			for i, record := range listed {
+				if record.TransferId != common.BigToAddress(big.NewInt(tt.want[i])) {
+					t.Fatalf("record %d is %s, want %d", i, record.TransferId, tt.want[i])
+				}
+			}
+		})
+	}
+
+	if err = linkStore.UpdateStatus(ctx, records[0].TransferId, types.ClaimLinkStatusDepositing); err != nil {
+		t.Fatal(err)
+	}
+	got, err = linkStore.Get(ctx, records[0].TransferId)
+	if err != nil {
+		t.Fatal(err)
+	}
+	if got.Status != types.ClaimLinkStatusDepositing || string(got.Snapshot) != string(records[0].Snapshot) {
+		t.Fatalf("unexpected updated record %+v", got)
+	}
+	err = linkStore.UpdateStatus(ctx, common.HexToAddress("0xff"), types.ClaimLinkStatusDeposited)
+	if !errors.Is(err, ErrNotFound) {
+		t.Fatalf("expected ErrNotFound, got %v", err)
+	}
+
+	replacement := testRecord(1, types.ChainIdBase, testSender, types.ClaimLinkStatusDeposited)
+	replacement.CreatedAt = time.Time{}
+	replacement.Snapshot = json.RawMessage(`{"version":1,"status":"deposited"}`)
+	if err = linkStore.Put(ctx, replacement); err != nil {
+		t.Fatal(err)
+	}
+	got, err = linkStore.Get(ctx, replacement.TransferId)
+	if err != nil {
+		t.Fatal(err)
+	}
+	if got.Status != types.ClaimLinkStatusDeposited || string(got.Snapshot) != string(replacement.Snapshot) {
+		t.Fatalf("record is not replaced: %+v", got)
+	}
+	if !got.CreatedAt.Equal(records[0].CreatedAt) {
+		t.Fatalf("CreatedAt of the replaced record is not kept: %s", got.CreatedAt)
+	}
+}
+
+func TestFileReopen(t *testing.T) {
+	ctx := context.Background()
+	path := filepath.Join(t.TempDir(), "links.json")
+	f, err := NewFile(path)
+	if err != nil {
+		t.Fatal(err)
+	}
+	record := testRecord(1, types.ChainIdBase, testSender, types.ClaimLinkStatusCreated)
+	if err = f.Put(ctx, record); err != nil {
+		t.Fatal(err)
+	}
+	if err = f.UpdateStatus(ctx, record.TransferId, types.ClaimLinkStatusDeposited); err != nil {
+		t.Fatal(err)
+	}
+
+	reopened, err := NewFile(path)
+	if err != nil {
+		t.Fatal(err)
+	}
+	got, err := reopened.Get(ctx, record.TransferId)
+	if err != nil {
+		t.Fatal(err)
+	}
+	if got.Status != types.ClaimLinkStatusDeposited || got.Amount.Cmp(record.Amount) != 0 {
+		t.Fatalf("unexpected reopened record %+v", got)
+	}
+}
+
+func TestFileFailedSave(t *testing.T) {
+	ctx := context.Background()
+	dir := t.TempDir()
+	f, err := NewFile(filepath.Join(dir, "links.json"))
+	if err != nil {
+		t.Fatal(err)
+	}
+	record := testRecord(1, types.ChainIdBase, testSender, types.ClaimLinkStatusCreated)
+	if err = f.Put(ctx, record); err != nil {
+		t.Fatal(err)
+	}
+
+	// the temporary file can't be created in the missing directory
+	f.path = filepath.Join(dir, "missing", "links.json")
+	if err = f.Put(ctx, testRecord(2, types.ChainIdBase, testSender, types.ClaimLinkStatusCreated)); err == nil {
+		t.Fatal("expected save error")
+	}
+	if err = f.UpdateStatus(ctx, record.TransferId, types.ClaimLinkStatusDeposited); err == nil {
+		t.Fatal("expected save error")
+	}
+
+	if _, err = f.Get(ctx, common.BigToAddress(big.NewInt(2))); !errors.Is(err, ErrNotFound) {
+		t.Fatalf("record of the failed Put is visible: %v", err)
+	}
+	got, err := f.Get(ctx, record.TransferId)
+	if err != nil {
+		t.Fatal(err)
+	}
+	if got.Status != types.ClaimLinkStatusCreated {
+		t.Fatalf("status of the failed UpdateStatus is visible: %s", got.Status)
+	}
+}
@@ -0,0 +1,217 @@
+package linkdrop
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"github.com/LinkdropHQ/linkdrop-go-sdk/constants"
+	"github.com/LinkdropHQ/linkdrop-go-sdk/helpers"
+	"github.com/LinkdropHQ/linkdrop-go-sdk/types"
+	"github.com/ethereum/go-ethereum/accounts/abi/bind"
+	"github.com/ethereum/go-ethereum/common"
+	"math/big"
+	"strings"
+	"sync"
+)
+
+// TokenInfo is the token metadata required to render amounts
+type TokenInfo struct {
+	Token    types.Token
+	Symbol   string
+	Name     string
+	Decimals uint8
+}
+
+// FormatAmount converts the amount in base units to the decimal string, e.g. "12.5"
+func (ti *TokenInfo) FormatAmount(amount *big.Int) string {
+	return helpers.FormatUnits(amount, ti.Decimals)
+}
+
+// Format converts the amount in base units to the decimal string with the symbol, e.g. "12.5 USDC"
+func (ti *TokenInfo) Format(amount *big.Int) string {
+	if ti.Symbol == "" {
+		return ti.FormatAmount(amount)
+	}
+	return ti.FormatAmount(amount) + " " + ti.Symbol
+}
+
+// Parse converts the decimal string to the amount in base units.
+// The value may end with the token symbol, e.g. "12.50 USDC". Negative amounts are rejected.
+func (ti *TokenInfo) Parse(value string) (amount *big.Int, err error) {
+	value = strings.TrimSpace(value)
+	if number, symbol, ok := strings.Cut(value, " "); ok {
+		if !strings.EqualFold(strings.TrimSpace(symbol), ti.Symbol) {
+			return nil, fmt.Errorf("amount symbol %s doesn't match the token symbol %s", strings.TrimSpace(symbol), ti.Symbol)
+		}
+		value = number
+	}
+	amount, err = helpers.ParseUnits(value, ti.Decimals)
+	if err != nil {
+		return
+	}
+	if amount.Sign() < 0 {
+		return nil, errors.New("amount should not be negative")
+	}
+	return
+}
+
+// TokenInfoResolver resolves and caches token metadata.
+// Native currencies and stable coins are taken from the chain registry, other ERC20 tokens are read on-chain.
+type TokenInfoResolver struct {
+	chains *types.ChainRegistry
+
+	mu      sync.RWMutex
+	callers map[types.ChainId]bind.ContractCaller
+	cache   map[tokenInfoKey]TokenInfo
+}
+
+type tokenInfoKey struct {
+	chainId types.ChainId
+	address common.Address
+}
+
+// NewTokenInfoResolver creates the resolver of the tokens of chains
+func NewTokenInfoResolver(chains *types.ChainRegistry) *TokenInfoResolver {
+	return &TokenInfoResolver{
+		chains:  chains,
+		callers: make(map[types.ChainId]bind.ContractCaller),
+		cache:   make(map[tokenInfoKey]TokenInfo),
+	}
+}
+
+// SetCaller sets the client of the chain used to read ERC20 decimals and symbol, e.g. *ethclient.Client
+func (tir *TokenInfoResolver) SetCaller(chainId types.ChainId, caller bind.ContractCaller) {
+	tir.mu.Lock()
+	defer tir.mu.Unlock()
+	tir.callers[chainId] = caller
+}
+
+// Register sets the token metadata skipping the lookup, e.g. for NFTs or tokens without symbol
+func (tir *TokenInfoResolver) Register(info TokenInfo) {
+	tir.mu.Lock()
+	defer tir.mu.Unlock()
+	tir.cache[tokenInfoKey{info.Token.ChainId, info.Token.Address}] = info
+}
+
+// Resolve returns the token metadata. ERC721 and ERC1155 tokens have no decimals and are not read on-chain.
+func (tir *TokenInfoResolver) Resolve(ctx context.Context, token types.Token) (info *TokenInfo, err error) {
+	key := tokenInfoKey{token.ChainId, token.Address}
+	tir.mu.RLock()
+	cached, ok := tir.cache[key]
+	caller := tir.callers[token.ChainId]
+	tir.mu.RUnlock()
+	if ok {
+		cached.Token = token
+		return &cached, nil
+	}
+
+	resolved := TokenInfo{Token: token}
+	switch token.Type {
+	case types.TokenTypeNative:
+		chain, err := tir.chains.Chain(token.ChainId)
+		if err != nil {
+			return nil, err
+		}
+		resolved.Symbol = chain.NativeCurrency.Symbol
+		resolved.Name = chain.NativeCurrency.Name
+		resolved.Decimals = chain.NativeCurrency.Decimals
+	case types.TokenTypeERC20:
+		if stableCoin, ok := tir.chains.StableCoin(token); ok {
+			resolved.Symbol = stableCoin.Symbol
+			resolved.Name = stableCoin.Domain.Name
+			resolved.Decimals = stableCoin.Decimals
+			break
+		}
+		if caller == nil {
+			return nil, fmt.Errorf("contract caller is not set for chain %d", token.ChainId)
+		}
+		err = tir.readERC20(ctx, caller, &resolved)
+		if err != nil {
+			return
+		}
+	case types.TokenTypeERC721, types.TokenTypeERC1155:
+		return &resolved, nil
+	default:
+		return nil, fmt.Errorf("%w: token type is not supported", types.ErrInvalidToken)
+	}
+
+	tir.mu.Lock()
+	tir.cache[key] = resolved
+	tir.mu.Unlock()
+	return &resolved, nil
+}
+
+// readERC20 reads decimals, symbol and name of the token. Only decimals are required,
+// some tokens return symbol and name as bytes32 and are left without them.
+func (tir *TokenInfoResolver) readERC20(ctx context.Context, caller bind.ContractCaller, info *TokenInfo) error {
+	err := helpers.LoadABI()
+	if err != nil {
+		return err
+	}
+	outputs, err := helpers.CallContract(ctx, caller, constants.ERC20Abi, info.Token.Address, "decimals")
+	if err != nil {
+		return fmt.Errorf("failed to read token decimals: %w", err)
+	}
+	decimals, ok := outputs[0].(uint8)
+	if !ok {
+		return errors.New("unexpected decimals output")
+	}
+	info.Decimals = decimals
+	if outputs, err = helpers.CallContract(ctx, caller, constants.ERC20Abi, info.Token.Address, "symbol"); err == nil {
+		info.Symbol, _ = outputs[0].(string)
+	}
+	if outputs, err = helpers.CallContract(ctx, caller, constants.ERC20Abi, info.Token.Address, "name"); err == nil {
+		info.Name, _ = outputs[0].(string)
+	}
+	return nil
+}
+
+// FormattedAmounts is the human-readable breakdown of the claim link amounts
+type FormattedAmounts struct {
+	Amount      string // Amount - the amount received by the receiver
+	Fee         string // Fee - the fee in the fee token, e.g. the native currency
+	TotalAmount string // TotalAmount - the amount of the token deposited by the sender
+	Token       *TokenInfo
+	FeeToken    *TokenInfo
+}
+
+// FormatAmounts resolves the link and the fee tokens and formats the amounts
+func (cl *ClaimLink) FormatAmounts(ctx context.Context) (amounts *FormattedAmounts, err error) {
+	resolver := cl.SDK.TokenInfoResolver()
+	tokenInfo, err := resolver.Resolve(ctx, cl.Token)
+	if err != nil {
+		return
+	}
+	amounts = &FormattedAmounts{
+		Amount:      tokenInfo.Format(cl.Amount),
+		TotalAmount: tokenInfo.Format(cl.TotalAmount),
+		Token:       tokenInfo,
+	}
+	if cl.Fee != nil {
+		amounts.FeeToken, err = resolver.Resolve(ctx, cl.Fee.Token)
+		if err != nil {
+			return nil, err
+		}
+		amounts.Fee = amounts.FeeToken.Format(cl.Fee.Amount)
+	}
+	return
+}
+
+// TokenInfoResolver returns the resolver of the token metadata, see WithTokenInfoCaller
+func (sdk *SDK) TokenInfoResolver() *TokenInfoResolver {
+	return sdk.tokenInfo
+}
+
+// GetTokenInfo returns the token metadata, see TokenInfoResolver.Resolve
+func (sdk *SDK) GetTokenInfo(ctx context.Context, token types.Token) (*TokenInfo, error) {
+	return sdk.tokenInfo.Resolve(ctx, token)
+}
+
+// ParseAmount converts the decimal string, e.g. "12.50 USDC", to the amount of the token in base units
+func (sdk *SDK) ParseAmount(ctx context.Context, token types.Token, value string) (*big.Int, error) {
+	info, err := sdk.tokenInfo.Resolve(ctx, token)
+	if err != nil {
+		return nil, err
+	}
+	return info.Parse(value)
+}
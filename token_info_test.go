@@ -0,0 +1,208 @@
+package linkdrop
+
+import (
+	"context"
+	"errors"
+	"github.com/LinkdropHQ/linkdrop-go-sdk/constants"
+	"github.com/LinkdropHQ/linkdrop-go-sdk/types"
+	"github.com/ethereum/go-ethereum"
+	"github.com/ethereum/go-ethereum/common"
+	"math/big"
+	"net/http"
+	"testing"
+)
+
+// erc20Caller answers decimals, symbol and name of the token, symbol and name are returned as bytes32 if bytes32 is set
+type erc20Caller struct {
+	t        *testing.T
+	decimals uint8
+	symbol   string
+	name     string
+	bytes32  bool
+	err      error
+	calls    []string
+}
+
+func (c *erc20Caller) CodeAt(ctx context.Context, contract common.Address, blockNumber *big.Int) ([]byte, error) {
+	return []byte{1}, nil
+}
+
+func (c *erc20Caller) CallContract(ctx context.Context, call ethereum.CallMsg, blockNumber *big.Int) ([]byte, error) {
+	method, err := constants.ERC20Abi.MethodById(call.Data[:4])
+	if err != nil {
+		c.t.Fatal(err)
+	}
+	c.calls = append(c.calls, method.Name)
+	if c.err != nil {
+		return nil, c.err
+	}
+	switch method.Name {
+	case "decimals":
+		return method.Outputs.Pack(c.decimals)
+	case "symbol":
+		if c.bytes32 {
+			return common.RightPadBytes([]byte(c.symbol), 32), nil
+		}
+		return method.Outputs.Pack(c.symbol)
+	case "name":
+		if c.bytes32 {
+			return common.RightPadBytes([]byte(c.name), 32), nil
+		}
+		return method.Outputs.Pack(c.name)
+	}
+	c.t.Fatalf("unexpected %s call", method.Name)
+	return nil, nil
+}
+
+func TestTokenInfoResolverResolve(t *testing.T) {
+	dai := types.Token{Type: types.TokenTypeERC20, ChainId: types.ChainIdBase, Address: common.HexToAddress("0x50c5725949a6f0c72e6c4a641f24049a917db0cb")}
+	tests := []struct {
+		name      string
+		token     types.Token
+		caller    *erc20Caller // caller - the caller of the token chain, not set if nil
+		want      TokenInfo
+		wantCalls []string
+		wantErr   bool
+	}{
+		{
+			name:      "ERC20 token read on-chain",
+			token:     dai,
+			caller:    &erc20Caller{decimals: 18, symbol: "DAI", name: "Dai Stablecoin"},
+			want:      TokenInfo{Symbol: "DAI", Name: "Dai Stablecoin", Decimals: 18},
+			wantCalls: []string{"decimals", "symbol", "name"},
+		},
+		{
+			name:      "ERC20 token with bytes32 symbol",
+			token:     dai,
+			caller:    &erc20Caller{decimals: 18, symbol: "MKR", name: "Maker", bytes32: true},
+			want:      TokenInfo{Decimals: 18},
+			wantCalls: []string{"decimals", "symbol", "name"},
+		},
+		{
+			name:      "decimals call error",
+			token:     dai,
+			caller:    &erc20Caller{err: errors.New("node is unavailable")},
+			wantCalls: []string{"decimals"},
+			wantErr:   true,
+		},
+		{name: "ERC20 token without caller", token: dai, wantErr: true},
+		{
+			name:   "native currency",
+			token:  types.Token{Type: types.TokenTypeNative, ChainId: types.ChainIdBase},
+			caller: &erc20Caller{},
+			want:   TokenInfo{Symbol: "ETH", Name: "Ether", Decimals: 18},
+		},
+		{
+			name:   "stable coin of the chain registry",
+			token:  types.Token{Type: types.TokenTypeERC20, ChainId: types.ChainIdBase, Address: constants.TAUsdcBase},
+			caller: &erc20Caller{},
+			want:   TokenInfo{Symbol: "USDC", Name: "USD Coin", Decimals: 6},
+		},
+		{
+			name:   "ERC721 token",
+			token:  types.Token{Type: types.TokenTypeERC721, ChainId: types.ChainIdBase, Address: common.HexToAddress("0x0721"), Id: big.NewInt(7)},
+			caller: &erc20Caller{},
+		},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			resolver := NewTokenInfoResolver(types.DefaultChainRegistry())
+			if tt.caller != nil {
+				tt.caller.t = t
+				resolver.SetCaller(tt.token.ChainId, tt.caller)
+			}
+
+			for range 2 {
+				info, err := resolver.Resolve(context.Background(), tt.token)
+				if tt.wantErr {
+					if err == nil {
+						t.Fatal("expected error")
+					}
+					continue
+				}
+				if err != nil {
+					t.Fatal(err)
+				}
+				if info.Token.Address != tt.token.Address || info.Symbol != tt.want.Symbol || info.Name != tt.want.Name ||
+					info.Decimals != tt.want.Decimals {
+					t.Fatalf("got %+v, want %+v", info, tt.want)
+				}
+			}
+			// the second lookup is served from the cache, failed lookups are repeated
+			wantCalls := tt.wantCalls
+			if tt.wantErr {
+				wantCalls = append(wantCalls, wantCalls...)
+			}
+			var calls []string
+			if tt.caller != nil {
+				calls = tt.caller.calls
+			}
+			if len(calls) != len(wantCalls) {
+				t.Fatalf("got calls %v, want %v", calls, wantCalls)
+			}
+			for i := range calls {
+				if calls[i] != wantCalls[i] {
+					t.Fatalf("got calls %v, want %v", calls, wantCalls)
+				}
+			}
+		})
+	}
+}
+
+func TestTokenInfoResolverRegister(t *testing.T) {
+	token := types.Token{Type: types.TokenTypeERC20, ChainId: types.ChainIdBase, Address: constants.TAUsdcBase}
+	caller := &erc20Caller{t: t}
+	resolver := NewTokenInfoResolver(types.DefaultChainRegistry())
+	resolver.SetCaller(types.ChainIdBase, caller)
+	resolver.Register(TokenInfo{Token: token, Symbol: "USDbC", Decimals: 6})
+
+	info, err := resolver.Resolve(context.Background(), token)
+	if err != nil {
+		t.Fatal(err)
+	}
+	if info.Symbol != "USDbC" || info.Decimals != 6 || len(caller.calls) != 0 {
+		t.Fatalf("registered token info isn't used: %+v, calls %v", info, caller.calls)
+	}
+}
+
+func TestSDKParseAmount(t *testing.T) {
+	caller := &erc20Caller{t: t, decimals: 18, symbol: "DAI", name: "Dai Stablecoin"}
+	sdk := newTestSDK(t, func(w http.ResponseWriter, r *http.Request) {
+		t.Fatalf("unexpected request %s", r.URL)
+	}, WithTokenInfoCaller(types.ChainIdBase, caller))
+	dai := types.Token{Type: types.TokenTypeERC20, ChainId: types.ChainIdBase, Address: common.HexToAddress("0x50c5725949a6f0c72e6c4a641f24049a917db0cb")}
+	usdc := types.Token{Type: types.TokenTypeERC20, ChainId: types.ChainIdBase, Address: constants.TAUsdcBase}
+	tests := []struct {
+		name    string
+		token   types.Token
+		value   string
+		want    string
+		wantErr bool
+	}{
+		{name: "stable coin with symbol", token: usdc, value: "12.50 USDC", want: "12500000"},
+		{name: "stable coin without symbol", token: usdc, value: "0.000001", want: "1"},
+		{name: "on-chain token", token: dai, value: "1.5 dai", want: "1500000000000000000"},
+		{name: "wrong symbol", token: usdc, value: "12.50 DAI", wantErr: true},
+		{name: "negative", token: usdc, value: "-1", wantErr: true},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			amount, err := sdk.ParseAmount(context.Background(), tt.token, tt.value)
+			if tt.wantErr {
+				if err == nil {
+					t.Fatalf("expected error, got %s", amount)
+				}
+				return
+			}
+			if err != nil {
+				t.Fatal(err)
+			}
+			if amount.String() != tt.want {
+				t.Fatalf("got %s, want %s", amount, tt.want)
+			}
+		})
+	}
+	if len(caller.calls) != 3 {
+		t.Fatalf("the on-chain token is read %v", caller.calls)
+	}
+}
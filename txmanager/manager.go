@@ -0,0 +1,454 @@
+// Package txmanager sends the transactions of a single account: it builds EIP-1559 transactions,
+// assigns nonces to concurrent sends, waits for the receipts with confirmations and bumps the fees of stuck transactions.
+package txmanager
+
+import (
+	"context"
+	"crypto/ecdsa"
+	"errors"
+	"fmt"
+	"github.com/LinkdropHQ/linkdrop-go-sdk/types"
+	"github.com/ethereum/go-ethereum"
+	"github.com/ethereum/go-ethereum/accounts/abi/bind"
+	"github.com/ethereum/go-ethereum/common"
+	gethtypes "github.com/ethereum/go-ethereum/core/types"
+	"math"
+	"math/big"
+	"sync"
+	"time"
+)
+
+// Backend is the part of *ethclient.Client used by Manager
+type Backend interface {
+	bind.ContractTransactor
+	TransactionReceipt(ctx context.Context, txHash common.Hash) (*gethtypes.Receipt, error)
+	BlockNumber(ctx context.Context) (uint64, error)
+}
+
+// Config configures Manager, zero values are replaced with the defaults
+type Config struct {
+	Confirmations      uint64        // Confirmations - the number of blocks including the receipt block, 1 by default
+	PollInterval       time.Duration // PollInterval - the delay between the receipt checks, 2s by default
+	BumpInterval       time.Duration // BumpInterval - the time without the receipt after which the fees are bumped, 30s by default
+	BumpPercent        uint64        // BumpPercent - the fee increase of the replacement, 15 by default. Nodes require at least 10
+	MaxBumps           int           // MaxBumps - the max number of replacements of a transaction, 5 by default
+	MaxFeePerGas       *big.Int      // MaxFeePerGas - optional, the fee cap the bumps don't exceed
+	GasLimitMultiplier float64       // GasLimitMultiplier - applied to the estimated gas, 1.2 by default
+	SendTimeout        time.Duration // SendTimeout - the timeout of SendTransaction, 1 minute by default
+}
+
+// Manager sends the transactions of the account on the chain.
+// Use one Manager per account and chain: the nonces are assigned locally to the concurrent sends.
+type Manager struct {
+	backend Backend
+	chainId *big.Int
+	from    common.Address
+	signFn  bind.SignerFn
+	config  Config
+
+	mu         sync.Mutex // mu - guards the nonces and the pending transactions, it isn't held during the node calls
+	nonce      *uint64    // nonce - the next nonce, read from the pending state if nil
+	reserved   int        // reserved - the number of the reserved nonces of the sends in flight
+	resetNonce bool       // resetNonce - a send failed, the nonce is read again once the sends in flight are done
+	pending    map[common.Hash]*pendingTx
+}
+
+// pendingTx is a transaction with its replacements, all of them share the nonce
+type pendingTx struct {
+	nonce   uint64
+	latest  *gethtypes.Transaction
+	hashes  []common.Hash
+	sentAt  time.Time
+	bumps   int
+	bumping bool // bumping - the replacement is being sent
+}
+
+type fees struct {
+	gasTipCap *big.Int
+	gasFeeCap *big.Int
+	gasPrice  *big.Int // gasPrice - set for the chains without EIP-1559
+}
+
+// New creates the manager of the account from, signFn signs its transactions
+func New(backend Backend, chainId *big.Int, from common.Address, signFn bind.SignerFn, config Config) *Manager {
+	if config.Confirmations == 0 {
+		config.Confirmations = 1
+	}
+	if config.PollInterval == 0 {
+		config.PollInterval = 2 * time.Second
+	}
+	if config.BumpInterval == 0 {
+		config.BumpInterval = 30 * time.Second
+	}
+	if config.BumpPercent == 0 {
+		config.BumpPercent = 15
+	}
+	if config.MaxBumps == 0 {
+		config.MaxBumps = 5
+	}
+	if config.GasLimitMultiplier == 0 {
+		config.GasLimitMultiplier = 1.2
+	}
+	if config.SendTimeout == 0 {
+		config.SendTimeout = time.Minute
+	}
+	return &Manager{
+		backend: backend,
+		chainId: new(big.Int).Set(chainId),
+		from:    from,
+		signFn:  signFn,
+		config:  config,
+		pending: make(map[common.Hash]*pendingTx),
+	}
+}
+
+// NewWithKey creates the manager of the account of the private key
+func NewWithKey(backend Backend, chainId *big.Int, key *ecdsa.PrivateKey, config Config) (*Manager, error) {
+	opts, err := bind.NewKeyedTransactorWithChainID(key, chainId)
+	if err != nil {
+		return nil, err
+	}
+	return New(backend, chainId, opts.From, opts.Signer, config), nil
+}
+
+func (m *Manager) From() common.Address {
+	return m.from
+}
+
+func (m *Manager) ChainId() *big.Int {
+	return new(big.Int).Set(m.chainId)
+}
+
+// ResetNonce makes the next send read the nonce from the pending state, e.g. after the account sent a transaction elsewhere
+func (m *Manager) ResetNonce() {
+	m.mu.Lock()
+	defer m.mu.Unlock()
+	m.nonce = nil
+}
+
+// Send signs and sends the transaction, use WaitForReceipt to wait until it's mined
+func (m *Manager) Send(ctx context.Context, to common.Address, value *big.Int, data []byte) (tx *gethtypes.Transaction, err error) {
+	if value == nil {
+		value = new(big.Int)
+	}
+	gas, err := m.backend.EstimateGas(ctx, ethereum.CallMsg{
+		From:  m.from,
+		To:    &to,
+		Value: value,
+		Data:  data,
+	})
+	if err != nil {
+		return nil, fmt.Errorf("failed to estimate gas: %w", err)
+	}
+	gasLimit := float64(gas) * m.config.GasLimitMultiplier
+	if gasLimit >= math.MaxUint64 {
+		return nil, fmt.Errorf("gas limit overflows: %d * %v", gas, m.config.GasLimitMultiplier)
+	}
+	gas = uint64(gasLimit)
+	txFees, err := m.suggestFees(ctx)
+	if err != nil {
+		return
+	}
+
+	nonce, err := m.reserveNonce(ctx)
+	if err != nil {
+		return
+	}
+	tx, err = m.signAndSend(ctx, m.newTx(nonce, to, value, data, gas, txFees))
+	m.releaseNonce(err != nil)
+	if err != nil {
+		return nil, err
+	}
+	m.mu.Lock()
+	defer m.mu.Unlock()
+	m.pending[tx.Hash()] = &pendingTx{
+		nonce:  nonce,
+		latest: tx,
+		hashes: []common.Hash{tx.Hash()},
+		sentAt: time.Now(),
+	}
+	return
+}
+
+// reserveNonce returns the next nonce of the account, the pending state is read without holding mu
+func (m *Manager) reserveNonce(ctx context.Context) (nonce uint64, err error) {
+	m.mu.Lock()
+	known := m.nonce != nil
+	m.mu.Unlock()
+	if !known {
+		nonce, err = m.backend.PendingNonceAt(ctx, m.from)
+		if err != nil {
+			return
+		}
+	}
+
+	m.mu.Lock()
+	defer m.mu.Unlock()
+	// a concurrent send could have read the nonce meanwhile, its counter is kept
+	if m.nonce == nil {
+		next := nonce
+		m.nonce = &next
+	}
+	if *m.nonce == math.MaxUint64 {
+		return 0, errors.New("nonce overflows")
+	}
+	nonce = *m.nonce
+	*m.nonce = nonce + 1
+	m.reserved++
+	return
+}
+
+// releaseNonce ends the send of the reserved nonce.
+// After a failure the nonce could be taken or the transaction could be sent anyway,
+// it's read again from the pending state once no other send holds a reserved nonce.
+func (m *Manager) releaseNonce(failed bool) {
+	m.mu.Lock()
+	defer m.mu.Unlock()
+	m.reserved--
+	if failed {
+		m.resetNonce = true
+	}
+	if m.resetNonce && m.reserved == 0 {
+		m.nonce = nil
+		m.resetNonce = false
+	}
+}
+
+// WaitForReceipt waits until the transaction or its replacement is mined and confirmed.
+// The receipt of the replacement has a different TxHash. The stuck transactions sent by the manager are bumped.
+// The errors of the node are retried until ctx is done.
+// NOTE: the receipt is returned for the reverted transactions too, check its Status
+func (m *Manager) WaitForReceipt(ctx context.Context, chainId *big.Int, txHash common.Hash) (*gethtypes.Receipt, error) {
+	if chainId != nil && chainId.Cmp(m.chainId) != 0 {
+		return nil, fmt.Errorf("wrong chain ID: %s, expected: %s", chainId, m.chainId)
+	}
+	m.mu.Lock()
+	pending := m.pending[txHash]
+	m.mu.Unlock()
+
+	ticker := time.NewTicker(m.config.PollInterval)
+	defer ticker.Stop()
+	var lastErr error
+	for {
+		hashes := []common.Hash{txHash}
+		if pending != nil {
+			m.mu.Lock()
+			hashes = append([]common.Hash(nil), pending.hashes...)
+			m.mu.Unlock()
+		}
+		mined := false
+		failed := false // failed - the node failed in this iteration, the transactions could be mined
+		for _, hash := range hashes {
+			receipt, err := m.backend.TransactionReceipt(ctx, hash)
+			if errors.Is(err, ethereum.NotFound) {
+				continue
+			}
+			if err != nil {
+				// the node errors are retried, e.g. "transaction indexing is in progress"
+				lastErr = err
+				failed = true
+				continue
+			}
+			mined = true
+			head, err := m.backend.BlockNumber(ctx)
+			if err != nil {
+				lastErr = err
+				failed = true
+				continue
+			}
+			block := receipt.BlockNumber.Uint64()
+			if head >= block && head-block+1 >= m.config.Confirmations {
+				m.forget(pending)
+				return receipt, nil
+			}
+		}
+		if !mined && !failed && pending != nil {
+			// a failed bump is retried after BumpInterval, the sent transactions can still be mined
+			_ = m.bump(ctx, pending)
+		}
+		select {
+		case <-ctx.Done():
+			if lastErr != nil {
+				return nil, fmt.Errorf("%w, last error: %v", ctx.Err(), lastErr)
+			}
+			return nil, ctx.Err()
+		case <-ticker.C:
+		}
+	}
+}
+
+// SendAndWait sends the transaction and waits for its receipt, see WaitForReceipt
+func (m *Manager) SendAndWait(ctx context.Context, to common.Address, value *big.Int, data []byte) (*gethtypes.Receipt, error) {
+	tx, err := m.Send(ctx, to, value, data)
+	if err != nil {
+		return nil, err
+	}
+	return m.WaitForReceipt(ctx, m.chainId, tx.Hash())
+}
+
+// SendTransaction implements types.TransactionSender, the transaction is sent within SendTimeout
+func (m *Manager) SendTransaction(
+	ctx context.Context,
+	chainId *big.Int,
+	to common.Address,
+	value *big.Int,
+	data []byte,
+) (transaction *types.Transaction, err error) {
+	if chainId == nil || chainId.Cmp(m.chainId) != 0 {
+		return nil, fmt.Errorf("wrong chain ID: %v, expected: %s", chainId, m.chainId)
+	}
+	ctx, cancel := context.WithTimeout(ctx, m.config.SendTimeout)
+	defer cancel()
+	tx, err := m.Send(ctx, to, value, data)
+	if err != nil {
+		return
+	}
+	return &types.Transaction{
+		Hash: tx.Hash(),
+		Type: types.TransactionTypeTx,
+	}, nil
+}
+
+// bump replaces the pending transaction with the one paying higher fees once BumpInterval has passed.
+// mu is held only to claim the bump and to record the replacement.
+func (m *Manager) bump(ctx context.Context, pending *pendingTx) (err error) {
+	m.mu.Lock()
+	if pending.bumping || pending.bumps >= m.config.MaxBumps || time.Since(pending.sentAt) < m.config.BumpInterval {
+		m.mu.Unlock()
+		return nil
+	}
+	pending.bumping = true
+	pending.sentAt = time.Now()
+	latest := pending.latest
+	m.mu.Unlock()
+
+	tx, err := m.sendReplacement(ctx, pending.nonce, latest)
+
+	m.mu.Lock()
+	defer m.mu.Unlock()
+	pending.bumping = false
+	if err != nil {
+		return
+	}
+	pending.bumps++
+	pending.latest = tx
+	pending.hashes = append(pending.hashes, tx.Hash())
+	m.pending[tx.Hash()] = pending
+	return nil
+}
+
+// sendReplacement sends the transaction with the nonce paying higher fees than latest
+func (m *Manager) sendReplacement(ctx context.Context, nonce uint64, latest *gethtypes.Transaction) (*gethtypes.Transaction, error) {
+	suggested, err := m.suggestFees(ctx)
+	if err != nil {
+		return nil, err
+	}
+	var bumped fees
+	if latest.Type() == gethtypes.LegacyTxType {
+		bumped.gasPrice = maxBig(m.increase(latest.GasPrice()), suggested.gasPrice)
+	} else {
+		bumped.gasTipCap = maxBig(m.increase(latest.GasTipCap()), suggested.gasTipCap)
+		bumped.gasFeeCap = maxBig(m.increase(latest.GasFeeCap()), suggested.gasFeeCap)
+		if bumped.gasFeeCap.Cmp(bumped.gasTipCap) < 0 {
+			bumped.gasFeeCap = bumped.gasTipCap
+		}
+		if m.config.MaxFeePerGas != nil && bumped.gasFeeCap.Cmp(m.config.MaxFeePerGas) > 0 {
+			return nil, errors.New("bumped fee exceeds MaxFeePerGas")
+		}
+	}
+	if bumped.gasPrice != nil && m.config.MaxFeePerGas != nil && bumped.gasPrice.Cmp(m.config.MaxFeePerGas) > 0 {
+		return nil, errors.New("bumped gas price exceeds MaxFeePerGas")
+	}
+	return m.signAndSend(ctx, m.newTx(nonce, *latest.To(), latest.Value(), latest.Data(), latest.Gas(), bumped))
+}
+
+func (m *Manager) forget(pending *pendingTx) {
+	if pending == nil {
+		return
+	}
+	m.mu.Lock()
+	defer m.mu.Unlock()
+	for _, hash := range pending.hashes {
+		delete(m.pending, hash)
+	}
+}
+
+// suggestFees returns EIP-1559 fees paying up to twice the current base fee, or the gas price for legacy chains
+func (m *Manager) suggestFees(ctx context.Context) (suggested fees, err error) {
+	head, err := m.backend.HeaderByNumber(ctx, nil)
+	if err != nil {
+		return
+	}
+	if head.BaseFee == nil {
+		suggested.gasPrice, err = m.backend.SuggestGasPrice(ctx)
+		if err != nil {
+			return
+		}
+		if m.config.MaxFeePerGas != nil && suggested.gasPrice.Cmp(m.config.MaxFeePerGas) > 0 {
+			suggested.gasPrice = new(big.Int).Set(m.config.MaxFeePerGas)
+		}
+		return
+	}
+	suggested.gasTipCap, err = m.backend.SuggestGasTipCap(ctx)
+	if err != nil {
+		return
+	}
+	suggested.gasFeeCap = new(big.Int).Add(new(big.Int).Mul(head.BaseFee, big.NewInt(2)), suggested.gasTipCap)
+	if m.config.MaxFeePerGas != nil && suggested.gasFeeCap.Cmp(m.config.MaxFeePerGas) > 0 {
+		suggested.gasFeeCap = new(big.Int).Set(m.config.MaxFeePerGas)
+		if suggested.gasTipCap.Cmp(suggested.gasFeeCap) > 0 {
+			suggested.gasTipCap = new(big.Int).Set(suggested.gasFeeCap)
+		}
+	}
+	return
+}
+
+func (m *Manager) newTx(nonce uint64, to common.Address, value *big.Int, data []byte, gas uint64, txFees fees) *gethtypes.Transaction {
+	if txFees.gasPrice != nil {
+		return gethtypes.NewTx(&gethtypes.LegacyTx{
+			Nonce:    nonce,
+			To:       &to,
+			Value:    value,
+			Gas:      gas,
+			GasPrice: txFees.gasPrice,
+			Data:     data,
+		})
+	}
+	return gethtypes.NewTx(&gethtypes.DynamicFeeTx{
+		ChainID:   m.chainId,
+		Nonce:     nonce,
+		GasTipCap: txFees.gasTipCap,
+		GasFeeCap: txFees.gasFeeCap,
+		Gas:       gas,
+		To:        &to,
+		Value:     value,
+		Data:      data,
+	})
+}
+
+func (m *Manager) signAndSend(ctx context.Context, tx *gethtypes.Transaction) (*gethtypes.Transaction, error) {
+	signed, err := m.signFn(m.from, tx)
+	if err != nil {
+		return nil, err
+	}
+	err = m.backend.SendTransaction(ctx, signed)
+	if err != nil {
+		return nil, err
+	}
+	return signed, nil
+}
+
+// increase applies BumpPercent rounding up
+func (m *Manager) increase(value *big.Int) *big.Int {
+	percent := new(big.Int).SetUint64(m.config.BumpPercent)
+	increased := new(big.Int).Mul(value, percent.Add(percent, big.NewInt(100)))
+	increased.Add(increased, big.NewInt(99))
+	return increased.Div(increased, big.NewInt(100))
+}
+
+func maxBig(a, b *big.Int) *big.Int {
+	if b == nil || a.Cmp(b) >= 0 {
+		return a
+	}
+	return b
+}
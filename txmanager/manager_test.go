@@ -0,0 +1,244 @@
+package txmanager
+
+import (
+	"context"
+	"errors"
+	"github.com/ethereum/go-ethereum"
+	"github.com/ethereum/go-ethereum/common"
+	gethtypes "github.com/ethereum/go-ethereum/core/types"
+	gethcrypto "github.com/ethereum/go-ethereum/crypto"
+	"math"
+	"math/big"
+	"sync"
+	"testing"
+	"time"
+)
+
+// stubBackend is a node keeping the sent transactions, the mineSend-th sent transaction is mined
+type stubBackend struct {
+	mu         sync.Mutex
+	nonce      uint64
+	nonceReads int
+	sendErr    error
+	receiptErr error
+	mineSend   int
+	sent       []*gethtypes.Transaction
+	mined      map[common.Hash]bool
+}
+
+func (b *stubBackend) EstimateGas(ctx context.Context, call ethereum.CallMsg) (uint64, error) {
+	return 21000, nil
+}
+
+func (b *stubBackend) SuggestGasPrice(ctx context.Context) (*big.Int, error) {
+	return big.NewInt(2e9), nil
+}
+
+func (b *stubBackend) SuggestGasTipCap(ctx context.Context) (*big.Int, error) {
+	return big.NewInt(1e9), nil
+}
+
+func (b *stubBackend) HeaderByNumber(ctx context.Context, number *big.Int) (*gethtypes.Header, error) {
+	return &gethtypes.Header{Number: big.NewInt(10), BaseFee: big.NewInt(1e9)}, nil
+}
+
+func (b *stubBackend) PendingCodeAt(ctx context.Context, account common.Address) ([]byte, error) {
+	return nil, nil
+}
+
+func (b *stubBackend) PendingNonceAt(ctx context.Context, account common.Address) (uint64, error) {
+	b.mu.Lock()
+	defer b.mu.Unlock()
+	b.nonceReads++
+	return b.nonce, nil
+}
+
+func (b *stubBackend) SendTransaction(ctx context.Context, tx *gethtypes.Transaction) error {
+	b.mu.Lock()
+	defer b.mu.Unlock()
+	if b.sendErr != nil {
+		err := b.sendErr
+		b.sendErr = nil
+		return err
+	}
+	b.sent = append(b.sent, tx)
+	if len(b.sent) == b.mineSend {
+		b.mined[tx.Hash()] = true
+	}
+	return nil
+}
+
+func (b *stubBackend) TransactionReceipt(ctx context.Context, txHash common.Hash) (*gethtypes.Receipt, error) {
+	b.mu.Lock()
+	defer b.mu.Unlock()
+	if b.receiptErr != nil {
+		return nil, b.receiptErr
+	}
+	if !b.mined[txHash] {
+		return nil, ethereum.NotFound
+	}
+	return &gethtypes.Receipt{TxHash: txHash, BlockNumber: big.NewInt(10), Status: gethtypes.ReceiptStatusSuccessful}, nil
+}
+
+func (b *stubBackend) BlockNumber(ctx context.Context) (uint64, error) {
+	return 10, nil
+}
+
+func (b *stubBackend) sentTransactions() []*gethtypes.Transaction {
+	b.mu.Lock()
+	defer b.mu.Unlock()
+	return append([]*gethtypes.Transaction(nil), b.sent...)
+}
+
+func newTestManager(t *testing.T, backend *stubBackend, config Config) *Manager {
+	t.Helper()
+	if backend.mined == nil {
+		backend.mined = make(map[common.Hash]bool)
+	}
+	key, err := gethcrypto.GenerateKey()
+	if err != nil {
+		t.Fatal(err)
+	}
+	m, err := NewWithKey(backend, big.NewInt(8453), key, config)
+	if err != nil {
+		t.Fatal(err)
+	}
+	return m
+}
+
+func TestManagerSendConcurrent(t *testing.T) {
+	backend := &stubBackend{nonce: 5}
+	m := newTestManager(t, backend, Config{})
+
+	var wg sync.WaitGroup
+	for i := 0; i < 10; i++ {
+		wg.Add(1)
+		go func() {
+			defer wg.Done()
+			if _, err := m.Send(context.Background(), common.HexToAddress("0x01"), nil, nil); err != nil {
+				t.Error(err)
+			}
+		}()
+	}
+	wg.Wait()
+
+	nonces := make(map[uint64]bool)
+	for _, tx := range backend.sentTransactions() {
+		nonces[tx.Nonce()] = true
+	}
+	for nonce := uint64(5); nonce < 15; nonce++ {
+		if !nonces[nonce] {
+			t.Fatalf("nonce %d isn't sent, sent nonces: %v", nonce, nonces)
+		}
+	}
+}
+
+func TestManagerSendFailureReadsNonce(t *testing.T) {
+	backend := &stubBackend{nonce: 3, sendErr: errors.New("nonce too low")}
+	m := newTestManager(t, backend, Config{})
+	ctx := context.Background()
+
+	if _, err := m.Send(ctx, common.HexToAddress("0x01"), nil, nil); err == nil {
+		t.Fatal("expected send error")
+	}
+	backend.nonce = 4
+	tx, err := m.Send(ctx, common.HexToAddress("0x01"), nil, nil)
+	if err != nil {
+		t.Fatal(err)
+	}
+	if tx.Nonce() != 4 || backend.nonceReads != 2 {
+		t.Fatalf("expected the nonce to be read again, got nonce %d after %d reads", tx.Nonce(), backend.nonceReads)
+	}
+}
+
+func TestManagerSendOverflow(t *testing.T) {
+	tests := []struct {
+		name    string
+		nonce   uint64
+		config  Config
+		wantErr string
+	}{
+		{name: "gas limit", config: Config{GasLimitMultiplier: 1e30}, wantErr: "gas limit overflows: 21000 * 1e+30"},
+		{name: "nonce", nonce: math.MaxUint64, wantErr: "nonce overflows"},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			backend := &stubBackend{nonce: tt.nonce}
+			m := newTestManager(t, backend, tt.config)
+			_, err := m.Send(context.Background(), common.HexToAddress("0x01"), nil, nil)
+			if err == nil || err.Error() != tt.wantErr {
+				t.Fatalf("expected %q, got %v", tt.wantErr, err)
+			}
+			if len(backend.sentTransactions()) != 0 {
+				t.Fatal("transaction is sent")
+			}
+		})
+	}
+}
+
+func TestManagerWaitForReceipt(t *testing.T) {
+	tests := []struct {
+		name       string
+		backend    *stubBackend
+		config     Config
+		wantSent   int  // wantSent - the number of the sent transactions including the replacements
+		wantMined  bool // wantMined - the receipt is returned before the timeout
+		wantBumped bool // wantBumped - the receipt is of the replacement
+	}{
+		{name: "mined", backend: &stubBackend{mineSend: 1}, wantSent: 1, wantMined: true},
+		{name: "bumped", backend: &stubBackend{mineSend: 2}, wantSent: 2, wantMined: true, wantBumped: true},
+		{name: "max bumps", backend: &stubBackend{}, config: Config{MaxBumps: 2}, wantSent: 3},
+		{name: "node errors", backend: &stubBackend{receiptErr: errors.New("transaction indexing is in progress")}, wantSent: 1},
+		{name: "confirmations overflow", backend: &stubBackend{mineSend: 1}, config: Config{Confirmations: math.MaxUint64}, wantSent: 1},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			tt.config.PollInterval = time.Millisecond
+			tt.config.BumpInterval = time.Nanosecond
+			m := newTestManager(t, tt.backend, tt.config)
+			ctx, cancel := context.WithTimeout(context.Background(), 200*time.Millisecond)
+			defer cancel()
+			tx, err := m.Send(ctx, common.HexToAddress("0x01"), big.NewInt(1), nil)
+			if err != nil {
+				t.Fatal(err)
+			}
+
+			receipt, err := m.WaitForReceipt(ctx, nil, tx.Hash())
+			if tt.wantMined != (err == nil) {
+				t.Fatalf("unexpected error %v", err)
+			}
+			if !tt.wantMined && !errors.Is(err, context.DeadlineExceeded) {
+				t.Fatalf("expected DeadlineExceeded, got %v", err)
+			}
+			sent := tt.backend.sentTransactions()
+			if len(sent) != tt.wantSent {
+				t.Fatalf("got %d sent transactions, want %d", len(sent), tt.wantSent)
+			}
+			for _, replacement := range sent[1:] {
+				if replacement.Nonce() != tx.Nonce() || replacement.GasTipCap().Cmp(tx.GasTipCap()) <= 0 {
+					t.Fatalf("unexpected replacement nonce %d, tip %s", replacement.Nonce(), replacement.GasTipCap())
+				}
+			}
+			if tt.wantMined && (receipt.TxHash != tx.Hash()) == !tt.wantBumped {
+				t.Fatalf("unexpected receipt of %s", receipt.TxHash)
+			}
+		})
+	}
+}
+
+func TestManagerSendTransactionChainId(t *testing.T) {
+	backend := &stubBackend{}
+	m := newTestManager(t, backend, Config{})
+	for _, chainId := range []*big.Int{nil, big.NewInt(1)} {
+		if _, err := m.SendTransaction(context.Background(), chainId, common.HexToAddress("0x01"), nil, nil); err == nil {
+			t.Fatalf("expected error for chain ID %v", chainId)
+		}
+	}
+	transaction, err := m.SendTransaction(context.Background(), big.NewInt(8453), common.HexToAddress("0x01"), nil, nil)
+	if err != nil {
+		t.Fatal(err)
+	}
+	if sent := backend.sentTransactions(); len(sent) != 1 || sent[0].Hash() != transaction.Hash {
+		t.Fatalf("unexpected sent transactions %v", sent)
+	}
+}
@@ -1,13 +1,74 @@
 package types
 
 import (
+	"context"
+	"errors"
 	"github.com/ethereum/go-ethereum/common"
+	gethtypes "github.com/ethereum/go-ethereum/core/types"
 	"github.com/ethereum/go-ethereum/signer/core/apitypes"
 	"math/big"
 )
 
 type RandomBytesCallback func(length int64) []byte
 
-type SignTypedDataCallback func(typedData apitypes.TypedData) ([]byte, error)
+type SignTypedDataCallback func(ctx context.Context, typedData apitypes.TypedData) ([]byte, error)
 
-type SendTransactionCallback func(chainId *big.Int, to common.Address, value *big.Int, data []byte) (*Transaction, error)
+type SendTransactionCallback func(
+	ctx context.Context,
+	chainId *big.Int,
+	to common.Address,
+	value *big.Int,
+	data []byte,
+) (*Transaction, error)
+
+// TypedDataSigner signs EIP-712 typed data. Wrap a function with SignTypedDataCallback to use it as TypedDataSigner.
+type TypedDataSigner interface {
+	SignTypedData(ctx context.Context, typedData apitypes.TypedData) ([]byte, error)
+}
+
+// TransactionSender sends transactions. Wrap a function with SendTransactionCallback to use it as TransactionSender.
+type TransactionSender interface {
+	SendTransaction(ctx context.Context, chainId *big.Int, to common.Address, value *big.Int, data []byte) (*Transaction, error)
+}
+
+// ReceiptWaiter waits until the transaction is mined, see txmanager.Manager.
+// The receipt can belong to a replacement of the transaction with the higher fees.
+// TransactionSender implementing it lets the SDK register only the successful deposits.
+type ReceiptWaiter interface {
+	WaitForReceipt(ctx context.Context, chainId *big.Int, txHash common.Hash) (*gethtypes.Receipt, error)
+}
+
+// MessageSigner signs messages with EIP-191 prefix (personal_sign)
+type MessageSigner interface {
+	SignMessage(ctx context.Context, message []byte) ([]byte, error)
+}
+
+// Signer is the account of the sender or the receiver, see the signer package for the implementations
+type Signer interface {
+	TypedDataSigner
+	TransactionSender
+	MessageSigner
+	Address() common.Address
+}
+
+// SignTypedData calls the callback, the nil callback returns an error
+func (f SignTypedDataCallback) SignTypedData(ctx context.Context, typedData apitypes.TypedData) ([]byte, error) {
+	if f == nil {
+		return nil, errors.New("sign typed data callback is not set")
+	}
+	return f(ctx, typedData)
+}
+
+// SendTransaction calls the callback, the nil callback returns an error
+func (f SendTransactionCallback) SendTransaction(
+	ctx context.Context,
+	chainId *big.Int,
+	to common.Address,
+	value *big.Int,
+	data []byte,
+) (*Transaction, error) {
+	if f == nil {
+		return nil, errors.New("send transaction callback is not set")
+	}
+	return f(ctx, chainId, to, value, data)
+}
@@ -17,12 +17,16 @@ const (
 	ChainIdAvalanche ChainId = 43114
 	ChainIdOptimism  ChainId = 10
 	ChainIdArbitrum  ChainId = 42161
+
+	// Testnets and local networks, not included in DefaultChains, see BaseSepoliaChain, PolygonAmoyChain and AnvilChain
+	ChainIdBaseSepolia ChainId = 84532
+	ChainIdPolygonAmoy ChainId = 80002
+	ChainIdAnvil       ChainId = 31337
 )
 
+// IsSupported checks the chain is one of DefaultChains.
+//
+// Deprecated: the chains registered with linkdrop.WithChainRegistry are not checked, use SDK.Chains().IsSupported
 func (cid *ChainId) IsSupported() bool {
-	switch *cid {
-	case ChainIdBase, ChainIdPolygon, ChainIdAvalanche, ChainIdOptimism, ChainIdArbitrum:
-		return true
-	}
-	return false
+	return defaultChainRegistry.IsSupported(*cid)
 }
@@ -0,0 +1,327 @@
+package types
+
+import (
+	"cmp"
+	"errors"
+	"fmt"
+	"github.com/LinkdropHQ/linkdrop-go-sdk/constants"
+	"github.com/ethereum/go-ethereum/common"
+	"github.com/ethereum/go-ethereum/signer/core/apitypes"
+	"slices"
+	"sync"
+)
+
+// NativeCurrency describes the native token of the chain
+type NativeCurrency struct {
+	Name     string
+	Symbol   string
+	Decimals uint8
+}
+
+// StableCoin is a token supporting deposits with EIP-3009 authorization
+type StableCoin struct {
+	Address  common.Address
+	Symbol   string
+	Decimals uint8
+	Selector constants.Selector       // Selector - the authorization method of the token
+	Domain   apitypes.TypedDataDomain // Domain - EIP-712 domain of the token
+}
+
+// Chain describes a chain the SDK can work with
+type Chain struct {
+	ChainId          ChainId
+	Name             string
+	ApiPath          string         // ApiPath - the API path segment of the chain, e.g. "base". Leave empty for chains without the API, e.g. local forks
+	ApiURL           string         // ApiURL - optional, overrides the SDK API URL for the chain, e.g. for testnets served by a staging API
+	EscrowAddress    common.Address // EscrowAddress - the escrow of native and ERC20 tokens
+	EscrowNFTAddress common.Address // EscrowNFTAddress - the escrow of ERC721 and ERC1155 tokens
+	EscrowVersion    string         // EscrowVersion - the version of both escrows, see constants.Escrows
+	NativeCurrency   NativeCurrency
+	StableCoins      []StableCoin
+	Testnet          bool
+}
+
+func (c *Chain) validate() error {
+	if c.ChainId <= 0 {
+		return errors.New("chain id is not set")
+	}
+	if c.EscrowAddress == ZeroAddress || c.EscrowNFTAddress == ZeroAddress {
+		return fmt.Errorf("escrow addresses are not set for chain %d", c.ChainId)
+	}
+	if c.EscrowVersion == "" {
+		return fmt.Errorf("escrow version is not set for chain %d", c.ChainId)
+	}
+	return nil
+}
+
+// ChainRegistry keeps the chains supported by the SDK.
+// The registry of DefaultChains is used unless another registry is provided with linkdrop.WithChainRegistry.
+type ChainRegistry struct {
+	mu     sync.RWMutex
+	chains map[ChainId]Chain
+}
+
+// defaultChainRegistry contains the chains returned by DefaultChains, it's never modified
+var defaultChainRegistry = mustChainRegistry(DefaultChains()...)
+
+// DefaultChainRegistry returns a new registry of the chains returned by DefaultChains.
+// The chains registered in it don't affect other registries.
+func DefaultChainRegistry() *ChainRegistry {
+	return mustChainRegistry(DefaultChains()...)
+}
+
+// NewChainRegistry creates a registry of the chains.
+// Use NewChainRegistry(DefaultChains()...) to extend the default chains with testnets or local forks.
+func NewChainRegistry(chains ...Chain) (*ChainRegistry, error) {
+	cr := &ChainRegistry{chains: make(map[ChainId]Chain, len(chains))}
+	for _, chain := range chains {
+		if err := cr.Register(chain); err != nil {
+			return nil, err
+		}
+	}
+	return cr, nil
+}
+
+func mustChainRegistry(chains ...Chain) *ChainRegistry {
+	cr, err := NewChainRegistry(chains...)
+	if err != nil {
+		panic(err)
+	}
+	return cr
+}
+
+// Register adds the chain to the registry replacing the chain with the same ChainId
+func (cr *ChainRegistry) Register(chain Chain) error {
+	if err := chain.validate(); err != nil {
+		return err
+	}
+	chain.StableCoins = slices.Clone(chain.StableCoins)
+	cr.mu.Lock()
+	defer cr.mu.Unlock()
+	cr.chains[chain.ChainId] = chain
+	return nil
+}
+
+// Chain returns the chain by its id, ErrUnsupportedChain is returned for unknown chains
+func (cr *ChainRegistry) Chain(chainId ChainId) (Chain, error) {
+	cr.mu.RLock()
+	defer cr.mu.RUnlock()
+	chain, ok := cr.chains[chainId]
+	if !ok {
+		return Chain{}, fmt.Errorf("%w: chainId %d is not registered", ErrUnsupportedChain, chainId)
+	}
+	chain.StableCoins = slices.Clone(chain.StableCoins)
+	return chain, nil
+}
+
+// Chains returns the registered chains ordered by ChainId
+func (cr *ChainRegistry) Chains() []Chain {
+	cr.mu.RLock()
+	defer cr.mu.RUnlock()
+	chains := make([]Chain, 0, len(cr.chains))
+	for _, chain := range cr.chains {
+		chain.StableCoins = slices.Clone(chain.StableCoins)
+		chains = append(chains, chain)
+	}
+	slices.SortFunc(chains, func(a, b Chain) int {
+		return cmp.Compare(a.ChainId, b.ChainId)
+	})
+	return chains
+}
+
+func (cr *ChainRegistry) IsSupported(chainId ChainId) bool {
+	cr.mu.RLock()
+	defer cr.mu.RUnlock()
+	_, ok := cr.chains[chainId]
+	return ok
+}
+
+// ApiHost returns the API URL of the chain, apiUrl is used unless the chain overrides it
+func (cr *ChainRegistry) ApiHost(apiUrl string, chainId ChainId) (string, error) {
+	chain, err := cr.Chain(chainId)
+	if err != nil {
+		return "", err
+	}
+	if chain.ApiPath == "" {
+		return "", fmt.Errorf("%w: chainId %d is not served by the API", ErrUnsupportedChain, chainId)
+	}
+	if chain.ApiURL != "" {
+		apiUrl = chain.ApiURL
+	}
+	return apiUrl + "/" + chain.ApiPath, nil
+}
+
+// EscrowAddresses returns the token and the NFT escrow addresses of the chain
+func (cr *ChainRegistry) EscrowAddresses(chainId ChainId) (escrow common.Address, escrowNFT common.Address, err error) {
+	chain, err := cr.Chain(chainId)
+	if err != nil {
+		return
+	}
+	return chain.EscrowAddress, chain.EscrowNFTAddress, nil
+}
+
+// EscrowAddressByToken returns the escrow of the token type, ERC721 and ERC1155 tokens are kept in the NFT escrow
+func (cr *ChainRegistry) EscrowAddressByToken(token Token) (escrowAddress common.Address, err error) {
+	escrow, escrowNFT, err := cr.EscrowAddresses(token.ChainId)
+	if err != nil {
+		return
+	}
+	switch token.Type {
+	case TokenTypeERC1155, TokenTypeERC721:
+		escrowAddress = escrowNFT
+	default:
+		escrowAddress = escrow
+	}
+	return
+}
+
+// EscrowVersion returns the version of the escrow registered in any chain
+func (cr *ChainRegistry) EscrowVersion(escrowAddress common.Address) (version string, ok bool) {
+	cr.mu.RLock()
+	defer cr.mu.RUnlock()
+	for _, chain := range cr.chains {
+		if chain.EscrowAddress == escrowAddress || chain.EscrowNFTAddress == escrowAddress {
+			return chain.EscrowVersion, true
+		}
+	}
+	return "", false
+}
+
+// StableCoin returns the stable coin matching the token chain and address
+func (cr *ChainRegistry) StableCoin(token Token) (stableCoin StableCoin, ok bool) {
+	cr.mu.RLock()
+	defer cr.mu.RUnlock()
+	chain, ok := cr.chains[token.ChainId]
+	if !ok {
+		return
+	}
+	for _, sc := range chain.StableCoins {
+		if sc.Address == token.Address {
+			return sc, true
+		}
+	}
+	return StableCoin{}, false
+}
+
+// DefaultChains returns the mainnet chains supported by the Linkdrop API
+func DefaultChains() []Chain {
+	ether := NativeCurrency{Name: "Ether", Symbol: "ETH", Decimals: 18}
+	return []Chain{
+		{
+			ChainId:          ChainIdBase,
+			Name:             "Base",
+			ApiPath:          "base",
+			EscrowAddress:    constants.CbwEscrowContractAddress,
+			EscrowNFTAddress: constants.CbwEscrowNFTContractAddress,
+			EscrowVersion:    "3.2",
+			NativeCurrency:   ether,
+			StableCoins: []StableCoin{
+				defaultStableCoin(constants.TAUsdcBase, "USDC", 6),
+				defaultStableCoin(constants.TAEurcBase, "EURC", 6),
+				defaultStableCoin(constants.TACbBtcBase, "cbBTC", 8),
+			},
+		},
+		{
+			ChainId:          ChainIdPolygon,
+			Name:             "Polygon",
+			ApiPath:          "polygon",
+			EscrowAddress:    constants.EscrowContractAddress,
+			EscrowNFTAddress: constants.EscrowNFTContractAddress,
+			EscrowVersion:    "3.2",
+			NativeCurrency:   NativeCurrency{Name: "POL", Symbol: "POL", Decimals: 18},
+			StableCoins: []StableCoin{
+				defaultStableCoin(constants.TAUsdcBridgedPolygon, "USDC.e", 6),
+				defaultStableCoin(constants.TAUsdcPolygon, "USDC", 6),
+			},
+		},
+		{
+			ChainId:          ChainIdAvalanche,
+			Name:             "Avalanche",
+			ApiPath:          "avalanche",
+			EscrowAddress:    constants.EscrowContractAddress,
+			EscrowNFTAddress: constants.EscrowNFTContractAddress,
+			EscrowVersion:    "3.2",
+			NativeCurrency:   NativeCurrency{Name: "Avalanche", Symbol: "AVAX", Decimals: 18},
+			StableCoins: []StableCoin{
+				defaultStableCoin(constants.TAUsdcAvalanche, "USDC", 6),
+			},
+		},
+		{
+			ChainId:          ChainIdOptimism,
+			Name:             "Optimism",
+			ApiPath:          "optimism",
+			EscrowAddress:    constants.EscrowContractAddress,
+			EscrowNFTAddress: constants.EscrowNFTContractAddress,
+			EscrowVersion:    "3.2",
+			NativeCurrency:   ether,
+			StableCoins: []StableCoin{
+				defaultStableCoin(constants.TAUsdcOptimism, "USDC", 6),
+			},
+		},
+		{
+			ChainId:          ChainIdArbitrum,
+			Name:             "Arbitrum",
+			ApiPath:          "arbitrum",
+			EscrowAddress:    constants.EscrowContractAddress,
+			EscrowNFTAddress: constants.EscrowNFTContractAddress,
+			EscrowVersion:    "3.2",
+			NativeCurrency:   ether,
+			StableCoins: []StableCoin{
+				defaultStableCoin(constants.TAUsdcArbitrum, "USDC", 6),
+			},
+		},
+	}
+}
+
+// BaseSepoliaChain returns Base Sepolia testnet with the escrows deployed to it.
+// Set ApiPath and ApiURL of the chain if the testnet is served by the API.
+func BaseSepoliaChain(escrowAddress common.Address, escrowNFTAddress common.Address, escrowVersion string) Chain {
+	return Chain{
+		ChainId:          ChainIdBaseSepolia,
+		Name:             "Base Sepolia",
+		EscrowAddress:    escrowAddress,
+		EscrowNFTAddress: escrowNFTAddress,
+		EscrowVersion:    escrowVersion,
+		NativeCurrency:   NativeCurrency{Name: "Sepolia Ether", Symbol: "ETH", Decimals: 18},
+		Testnet:          true,
+	}
+}
+
+// PolygonAmoyChain returns Polygon Amoy testnet with the escrows deployed to it, see BaseSepoliaChain
+func PolygonAmoyChain(escrowAddress common.Address, escrowNFTAddress common.Address, escrowVersion string) Chain {
+	return Chain{
+		ChainId:          ChainIdPolygonAmoy,
+		Name:             "Polygon Amoy",
+		EscrowAddress:    escrowAddress,
+		EscrowNFTAddress: escrowNFTAddress,
+		EscrowVersion:    escrowVersion,
+		NativeCurrency:   NativeCurrency{Name: "POL", Symbol: "POL", Decimals: 18},
+		Testnet:          true,
+	}
+}
+
+// AnvilChain returns the local Anvil fork of the default chain keeping its escrows.
+// The fork is not served by the API and has no stable coins, their EIP-712 domains are bound to the forked chain id.
+func AnvilChain(fork ChainId) (Chain, error) {
+	chain, err := defaultChainRegistry.Chain(fork)
+	if err != nil {
+		return Chain{}, err
+	}
+	chain.ChainId = ChainIdAnvil
+	chain.Name = "Anvil (" + chain.Name + " fork)"
+	chain.ApiPath = ""
+	chain.ApiURL = ""
+	chain.StableCoins = nil
+	chain.Testnet = true
+	return chain, nil
+}
+
+func defaultStableCoin(address common.Address, symbol string, decimals uint8) StableCoin {
+	return StableCoin{
+		Address:  address,
+		Symbol:   symbol,
+		Decimals: decimals,
+		Selector: constants.SupportedStableCoins[address],
+		Domain:   constants.StableCoinDomains[address],
+	}
+}
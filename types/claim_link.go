@@ -2,8 +2,7 @@ package types
 
 import (
 	"crypto/ecdsa"
-	"encoding/json"
-	"errors"
+	"fmt"
 	"github.com/ethereum/go-ethereum/common"
 	"math/big"
 )
@@ -14,11 +13,19 @@ type ClaimLinkFee struct {
 	Authorization []byte
 }
 
+// Validate checks the fee against DefaultChains.
+//
+// Deprecated: the chains registered with linkdrop.WithChainRegistry are not checked, use ValidateWith(sdk.Chains())
 func (clf *ClaimLinkFee) Validate() error {
+	return clf.ValidateWith(defaultChainRegistry)
+}
+
+// ValidateWith checks the fee token is native or ERC20 and its chain is registered in chains
+func (clf *ClaimLinkFee) ValidateWith(chains *ChainRegistry) error {
 	if !(clf.Token.Type == TokenTypeNative || clf.Token.Type == TokenTypeERC20) {
-		return errors.New("fee token type is invalid, should be one of: native, ERC20")
+		return fmt.Errorf("%w: fee token type is invalid, should be one of: native, ERC20", ErrInvalidToken)
 	}
-	return clf.Token.Validate()
+	return clf.Token.ValidateWith(chains)
 }
 
 type ClaimLinkFeeData struct {
@@ -43,6 +50,7 @@ const (
 	ClaimLinkStatusCancelled
 	ClaimLinkStatusDropped
 	ClaimLinkStatusError
+	ClaimLinkStatusCancelling // ClaimLinkStatusCancelling - the cancel transaction is sent, added last to keep the stored values
 )
 
 func (clis ClaimLinkStatus) String() string {
@@ -69,6 +77,8 @@ func (clis ClaimLinkStatus) String() string {
 		return "dropped"
 	case ClaimLinkStatusError:
 		return "error"
+	case ClaimLinkStatusCancelling:
+		return "cancelling"
 	}
 	return ""
 }
@@ -97,10 +107,21 @@ func ClaimLinkStatusFromString(value string) ClaimLinkStatus {
 		return ClaimLinkStatusDropped
 	case "error":
 		return ClaimLinkStatusError
+	case "cancelling":
+		return ClaimLinkStatusCancelling
 	}
 	return ClaimLinkStatusUndefined
 }
 
+func (clis ClaimLinkStatus) MarshalText() ([]byte, error) {
+	return []byte(clis.String()), nil
+}
+
+func (clis *ClaimLinkStatus) UnmarshalText(text []byte) error {
+	*clis = ClaimLinkStatusFromString(string(text))
+	return nil
+}
+
 type ClaimLinkOperationStatus string
 
 const (
@@ -117,25 +138,8 @@ type ClaimLinkOperation struct {
 	TxHash    *common.Hash             `json:"txHash"`
 }
 
-type ClaimLinkDepositParams struct {
-	ChainId ChainId        `json:"chainId"`
-	Value   *big.Int       `json:"value"`
-	Data    []byte         `json:"data"`
-	To      common.Address `json:"to"`
-}
-
-func (cldp ClaimLinkDepositParams) MarshalJSON() ([]byte, error) {
-	type Alias ClaimLinkDepositParams
-	return json.Marshal(&struct {
-		Data  string `json:"data"`
-		Value string `json:"value"`
-		*Alias
-	}{
-		Data:  "0x" + common.Bytes2Hex(cldp.Data),
-		Value: cldp.Value.String(),
-		Alias: (*Alias)(&cldp),
-	})
-}
+// ClaimLinkDepositParams - parameters of the deposit transaction
+type ClaimLinkDepositParams = TransactionParams
 
 // Link
 // Represents the parsed structure of the link
@@ -0,0 +1,8 @@
+package types
+
+import "errors"
+
+var (
+	ErrUnsupportedChain = errors.New("unsupported chain")
+	ErrInvalidToken     = errors.New("invalid token")
+)
@@ -1,8 +1,21 @@
 package types
 
+import "fmt"
+
 type EscrowType int64
 
 const (
 	EscrowNFT EscrowType = iota
 	EscrowToken
 )
+
+// escrowTokenTypes - token types in the order of the escrow contract TokenType enum
+var escrowTokenTypes = []TokenType{TokenTypeNative, TokenTypeERC20, TokenTypeERC721, TokenTypeERC1155}
+
+// TokenTypeFromEscrow converts the escrow contract TokenType enum value
+func TokenTypeFromEscrow(value uint8) (TokenType, error) {
+	if int(value) >= len(escrowTokenTypes) {
+		return "", fmt.Errorf("%w: unknown escrow token type %d", ErrInvalidToken, value)
+	}
+	return escrowTokenTypes[value], nil
+}
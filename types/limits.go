@@ -5,6 +5,6 @@ import "math/big"
 type TransferLimits struct {
 	MinAmount    *big.Int `json:"min_transfer_amount"`
 	MaxAmount    *big.Int `json:"max_transfer_amount"`
-	MinAmountUSD *big.Int `json:"min_transfer_amount_usd"`
-	MaxAmountUSD *big.Int `json:"max_transfer_amount_usd"`
+	MinAmountUSD string   `json:"min_transfer_amount_usd"` // MinAmountUSD - decimal string, e.g. "10.5"
+	MaxAmountUSD string   `json:"max_transfer_amount_usd"` // MaxAmountUSD - decimal string, e.g. "10.5"
 }
@@ -1,7 +1,7 @@
 package types
 
 import (
-	"errors"
+	"fmt"
 	"github.com/ethereum/go-ethereum/common"
 	"math/big"
 )
@@ -32,30 +32,38 @@ type Token struct {
 	Id      *big.Int       `json:"id"`
 }
 
+// Validate checks the token against DefaultChains.
+//
+// Deprecated: the chains registered with linkdrop.WithChainRegistry are not checked, use ValidateWith(sdk.Chains())
 func (t *Token) Validate() error {
-	if !t.ChainId.IsSupported() {
-		return errors.New("token chainId is not supported")
+	return t.ValidateWith(defaultChainRegistry)
+}
+
+// ValidateWith checks the token is well-formed and its chain is registered in chains
+func (t *Token) ValidateWith(chains *ChainRegistry) error {
+	if !chains.IsSupported(t.ChainId) {
+		return fmt.Errorf("%w: token chainId %d is not supported", ErrUnsupportedChain, t.ChainId)
 	}
 	if !t.Type.isSupported() {
-		return errors.New("token type is not supported")
+		return fmt.Errorf("%w: token type is not supported", ErrInvalidToken)
 	}
 	if t.Type == TokenTypeNative {
 		if t.Address != ZeroAddress {
-			return errors.New("native token should not have address")
+			return fmt.Errorf("%w: native token should not have address", ErrInvalidToken)
 		}
 		if t.Id != nil {
-			return errors.New("native token should not have id")
+			return fmt.Errorf("%w: native token should not have id", ErrInvalidToken)
 		}
 	} else if t.Address == ZeroAddress {
-		return errors.New("address is not provided")
+		return fmt.Errorf("%w: address is not provided", ErrInvalidToken)
 	}
 
 	if t.Type == TokenTypeERC20 && t.Id != nil {
-		return errors.New("id is not supported for ERC20 token")
+		return fmt.Errorf("%w: id is not supported for ERC20 token", ErrInvalidToken)
 	}
 
 	if (t.Type == TokenTypeERC721 || t.Type == TokenTypeERC1155) && t.Id == nil {
-		return errors.New("id is not provided")
+		return fmt.Errorf("%w: id is not provided", ErrInvalidToken)
 	}
 
 	return nil
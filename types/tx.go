@@ -1,6 +1,10 @@
 package types
 
-import "github.com/ethereum/go-ethereum/common"
+import (
+	"encoding/json"
+	"github.com/ethereum/go-ethereum/common"
+	"math/big"
+)
 
 type TransactionType string
 
@@ -13,3 +17,24 @@ type Transaction struct {
 	Hash common.Hash
 	Type TransactionType
 }
+
+// TransactionParams - parameters of a transaction to be sent by the caller
+type TransactionParams struct {
+	ChainId ChainId        `json:"chainId"`
+	Value   *big.Int       `json:"value"`
+	Data    []byte         `json:"data"`
+	To      common.Address `json:"to"`
+}
+
+func (tp TransactionParams) MarshalJSON() ([]byte, error) {
+	type Alias TransactionParams
+	return json.Marshal(&struct {
+		Data  string `json:"data"`
+		Value string `json:"value"`
+		*Alias
+	}{
+		Data:  "0x" + common.Bytes2Hex(tp.Data),
+		Value: tp.Value.String(),
+		Alias: (*Alias)(&tp),
+	})
+}
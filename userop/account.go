@@ -0,0 +1,75 @@
+package userop
+
+import (
+	"context"
+	"errors"
+	"github.com/LinkdropHQ/linkdrop-go-sdk/constants"
+	"github.com/LinkdropHQ/linkdrop-go-sdk/types"
+	"github.com/ethereum/go-ethereum/common"
+	"github.com/ethereum/go-ethereum/common/hexutil"
+	"math/big"
+)
+
+// Account is the smart account sending the user operations.
+// SimpleAccount implements it, implement it for the accounts with the other call data or signature format.
+type Account interface {
+	Address() common.Address
+	// EncodeCalls encodes the calls executed by the account into the user operation call data
+	EncodeCalls(calls []types.TransactionParams) ([]byte, error)
+	// SignUserOpHash signs the user operation hash
+	SignUserOpHash(ctx context.Context, userOpHash common.Hash) ([]byte, error)
+	// DummySignature is the signature of the valid format used to estimate the gas
+	DummySignature() []byte
+}
+
+// dummyECDSASignature is r, s and v passing ECDSA recovery, so the estimation accounts for the signature check
+var dummyECDSASignature = hexutil.MustDecode(
+	"0xfffffffffffffffffffffffffffffff000000000000000000000000000000000" +
+		"7aaaaaaaaaaaaaaaaaaaaaaaaaaaaaaaaaaaaaaaaaaaaaaaaaaaaaaaaaaaaaaa" +
+		"1c",
+)
+
+// SimpleAccount is eth-infinitism SimpleAccount for EntryPoint v0.7 and the accounts compatible with it:
+// the calls are executed with execute and executeBatch, the owner signs the user operation hash with EIP-191 prefix
+type SimpleAccount struct {
+	address common.Address
+	owner   types.MessageSigner
+}
+
+// NewSimpleAccount creates the account at address owned by owner, e.g. signer.PrivateKey
+func NewSimpleAccount(address common.Address, owner types.MessageSigner) *SimpleAccount {
+	return &SimpleAccount{
+		address: address,
+		owner:   owner,
+	}
+}
+
+func (a *SimpleAccount) Address() common.Address {
+	return a.address
+}
+
+func (a *SimpleAccount) EncodeCalls(calls []types.TransactionParams) ([]byte, error) {
+	switch len(calls) {
+	case 0:
+		return nil, errors.New("no calls to encode")
+	case 1:
+		return constants.SimpleAccountAbi.Pack("execute", calls[0].To, bigOrZero(calls[0].Value), calls[0].Data)
+	}
+	destinations := make([]common.Address, len(calls))
+	values := make([]*big.Int, len(calls))
+	data := make([][]byte, len(calls))
+	for i, call := range calls {
+		destinations[i] = call.To
+		values[i] = bigOrZero(call.Value)
+		data[i] = call.Data
+	}
+	return constants.SimpleAccountAbi.Pack("executeBatch", destinations, values, data)
+}
+
+func (a *SimpleAccount) SignUserOpHash(ctx context.Context, userOpHash common.Hash) ([]byte, error) {
+	return a.owner.SignMessage(ctx, userOpHash.Bytes())
+}
+
+func (a *SimpleAccount) DummySignature() []byte {
+	return dummyECDSASignature
+}
@@ -0,0 +1,223 @@
+package userop
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"github.com/LinkdropHQ/linkdrop-go-sdk/constants"
+	"github.com/LinkdropHQ/linkdrop-go-sdk/helpers"
+	"github.com/LinkdropHQ/linkdrop-go-sdk/types"
+	"github.com/ethereum/go-ethereum/accounts/abi/bind"
+	"github.com/ethereum/go-ethereum/common"
+	gethtypes "github.com/ethereum/go-ethereum/core/types"
+	"math/big"
+	"time"
+)
+
+// Backend is the part of *ethclient.Client used by Builder to read the nonce, the account code and the fees
+type Backend interface {
+	bind.ContractCaller
+	HeaderByNumber(ctx context.Context, number *big.Int) (*gethtypes.Header, error)
+	SuggestGasTipCap(ctx context.Context) (*big.Int, error)
+}
+
+// Builder builds the user operations of the account executing the calls, signs and sends them through the bundler
+type Builder struct {
+	chainId       *big.Int
+	account       Account
+	bundler       Bundler
+	backend       Backend
+	fees          FeeSource
+	entryPoint    common.Address
+	factory       *common.Address
+	factoryData   []byte
+	paymaster     *common.Address
+	paymasterData []byte
+	pollInterval  time.Duration
+}
+
+// NewBuilder creates the builder of the account user operations on the chain, EntryPoint v0.7 is used by default
+func NewBuilder(chainId types.ChainId, account Account, bundler Bundler, backend Backend) *Builder {
+	return &Builder{
+		chainId:      big.NewInt(int64(chainId)),
+		account:      account,
+		bundler:      bundler,
+		backend:      backend,
+		fees:         nodeFees{backend: backend},
+		entryPoint:   constants.EntryPointV07Address,
+		pollInterval: 2 * time.Second,
+	}
+}
+
+// WithEntryPoint sets the address of the v0.7 compatible entry point
+func (b *Builder) WithEntryPoint(entryPoint common.Address) *Builder {
+	b.entryPoint = entryPoint
+	return b
+}
+
+// WithFactory sets the factory deploying the account with the first user operation, it's skipped once the account is deployed
+func (b *Builder) WithFactory(factory common.Address, factoryData []byte) *Builder {
+	b.factory = &factory
+	b.factoryData = factoryData
+	return b
+}
+
+// WithPaymaster sets the paymaster sponsoring the user operations, its gas limits are estimated by the bundler
+func (b *Builder) WithPaymaster(paymaster common.Address, paymasterData []byte) *Builder {
+	b.paymaster = &paymaster
+	b.paymasterData = paymasterData
+	return b
+}
+
+// WithFees sets the source of the user operation fees, the node suggested fees are used by default
+func (b *Builder) WithFees(fees FeeSource) *Builder {
+	b.fees = fees
+	return b
+}
+
+// WithPollInterval sets the delay between the receipt checks of WaitForReceipt, 2s by default
+func (b *Builder) WithPollInterval(pollInterval time.Duration) *Builder {
+	b.pollInterval = pollInterval
+	return b
+}
+
+func (b *Builder) Account() Account {
+	return b.account
+}
+
+func (b *Builder) ChainId() types.ChainId {
+	return types.ChainId(b.chainId.Int64())
+}
+
+func (b *Builder) EntryPoint() common.Address {
+	return b.entryPoint
+}
+
+// Build returns the signed user operation executing the calls, e.g. ERC20 approval followed by the deposit.
+// The gas limits are estimated by the bundler.
+func (b *Builder) Build(ctx context.Context, calls ...types.TransactionParams) (op *UserOperation, err error) {
+	for _, call := range calls {
+		if int64(call.ChainId) != b.chainId.Int64() {
+			return nil, fmt.Errorf("call is on chain %d, user operation is on chain %s", call.ChainId, b.chainId)
+		}
+	}
+	err = helpers.LoadABI()
+	if err != nil {
+		return
+	}
+	callData, err := b.account.EncodeCalls(calls)
+	if err != nil {
+		return
+	}
+	nonce, err := b.nonce(ctx)
+	if err != nil {
+		return
+	}
+	op = &UserOperation{
+		Sender:        b.account.Address(),
+		Nonce:         nonce,
+		CallData:      callData,
+		Paymaster:     b.paymaster,
+		PaymasterData: b.paymasterData,
+		Signature:     b.account.DummySignature(),
+	}
+	if b.factory != nil {
+		code, err := b.backend.CodeAt(ctx, op.Sender, nil)
+		if err != nil {
+			return nil, err
+		}
+		if len(code) == 0 {
+			op.Factory = b.factory
+			op.FactoryData = b.factoryData
+		}
+	}
+	op.MaxFeePerGas, op.MaxPriorityFeePerGas, err = b.fees.Fees(ctx)
+	if err != nil {
+		return nil, fmt.Errorf("failed to get user operation fees: %w", err)
+	}
+
+	estimate, err := b.bundler.EstimateUserOperationGas(ctx, op, b.entryPoint)
+	if err != nil {
+		return nil, fmt.Errorf("failed to estimate user operation gas: %w", err)
+	}
+	if estimate.PreVerificationGas == nil || estimate.VerificationGasLimit == nil || estimate.CallGasLimit == nil {
+		return nil, errors.New("incomplete user operation gas estimate")
+	}
+	op.PreVerificationGas = estimate.PreVerificationGas
+	op.VerificationGasLimit = estimate.VerificationGasLimit
+	op.CallGasLimit = estimate.CallGasLimit
+	if op.Paymaster != nil {
+		// the post-op limit is omitted by some bundlers if the paymaster has no postOp, it's packed as 0 then
+		if estimate.PaymasterVerificationGasLimit == nil {
+			return nil, errors.New("incomplete user operation paymaster gas estimate")
+		}
+		op.PaymasterVerificationGasLimit = estimate.PaymasterVerificationGasLimit
+		op.PaymasterPostOpGasLimit = estimate.PaymasterPostOpGasLimit
+	}
+
+	op.Signature, err = b.Sign(ctx, op)
+	if err != nil {
+		return nil, err
+	}
+	return
+}
+
+// Hash returns the hash of the user operation for the chain and the entry point of the builder
+func (b *Builder) Hash(op *UserOperation) (common.Hash, error) {
+	return op.Hash(b.entryPoint, b.chainId)
+}
+
+// Sign returns the account signature of the user operation
+func (b *Builder) Sign(ctx context.Context, op *UserOperation) ([]byte, error) {
+	userOpHash, err := b.Hash(op)
+	if err != nil {
+		return nil, err
+	}
+	return b.account.SignUserOpHash(ctx, userOpHash)
+}
+
+// Send builds the user operation executing the calls and sends it to the bundler
+func (b *Builder) Send(ctx context.Context, calls ...types.TransactionParams) (userOpHash common.Hash, err error) {
+	op, err := b.Build(ctx, calls...)
+	if err != nil {
+		return
+	}
+	return b.bundler.SendUserOperation(ctx, op, b.entryPoint)
+}
+
+// WaitForReceipt waits until the user operation is included. The errors of the bundler are retried until ctx is done.
+// NOTE: the receipt is returned for the failed user operations too, check its Success
+func (b *Builder) WaitForReceipt(ctx context.Context, userOpHash common.Hash) (*Receipt, error) {
+	ticker := time.NewTicker(b.pollInterval)
+	defer ticker.Stop()
+	var lastErr error
+	for {
+		receipt, err := b.bundler.GetUserOperationReceipt(ctx, userOpHash)
+		if err != nil {
+			lastErr = err
+		} else if receipt != nil {
+			return receipt, nil
+		}
+		select {
+		case <-ctx.Done():
+			if lastErr != nil {
+				return nil, fmt.Errorf("%w, last error: %v", ctx.Err(), lastErr)
+			}
+			return nil, ctx.Err()
+		case <-ticker.C:
+		}
+	}
+}
+
+// nonce reads the account nonce of the default key from the entry point
+func (b *Builder) nonce(ctx context.Context) (*big.Int, error) {
+	outputs, err := helpers.CallContract(ctx, b.backend, constants.EntryPointAbi, b.entryPoint, "getNonce", b.account.Address(), big.NewInt(0))
+	if err != nil {
+		return nil, fmt.Errorf("failed to get account nonce: %w", err)
+	}
+	nonce, ok := outputs[0].(*big.Int)
+	if !ok {
+		return nil, errors.New("unexpected getNonce output")
+	}
+	return nonce, nil
+}
@@ -0,0 +1,200 @@
+package userop
+
+import (
+	"bytes"
+	"context"
+	"errors"
+	"github.com/LinkdropHQ/linkdrop-go-sdk/constants"
+	"github.com/LinkdropHQ/linkdrop-go-sdk/signer"
+	"github.com/LinkdropHQ/linkdrop-go-sdk/types"
+	"github.com/ethereum/go-ethereum"
+	"github.com/ethereum/go-ethereum/accounts"
+	"github.com/ethereum/go-ethereum/common"
+	gethtypes "github.com/ethereum/go-ethereum/core/types"
+	gethcrypto "github.com/ethereum/go-ethereum/crypto"
+	"math/big"
+	"testing"
+	"time"
+)
+
+// stubBackend is the node of the undeployed account with the nonce 3
+type stubBackend struct {
+	tipCapCalls int
+}
+
+func (b *stubBackend) CodeAt(ctx context.Context, contract common.Address, blockNumber *big.Int) ([]byte, error) {
+	return nil, nil
+}
+
+func (b *stubBackend) CallContract(ctx context.Context, call ethereum.CallMsg, blockNumber *big.Int) ([]byte, error) {
+	if *call.To != constants.EntryPointV07Address {
+		return nil, errors.New("unexpected call")
+	}
+	return common.LeftPadBytes([]byte{3}, 32), nil
+}
+
+func (b *stubBackend) HeaderByNumber(ctx context.Context, number *big.Int) (*gethtypes.Header, error) {
+	return &gethtypes.Header{Number: big.NewInt(10), BaseFee: big.NewInt(2e9)}, nil
+}
+
+func (b *stubBackend) SuggestGasTipCap(ctx context.Context) (*big.Int, error) {
+	b.tipCapCalls++
+	return big.NewInt(1e9), nil
+}
+
+// stubBundler records the estimated and the sent user operations, the operation is included once sent
+type stubBundler struct {
+	estimate  GasEstimate
+	estimated *UserOperation
+	sent      *UserOperation
+}
+
+func (b *stubBundler) EstimateUserOperationGas(ctx context.Context, op *UserOperation, entryPoint common.Address) (*GasEstimate, error) {
+	copied := *op
+	b.estimated = &copied
+	return &b.estimate, nil
+}
+
+func (b *stubBundler) SendUserOperation(ctx context.Context, op *UserOperation, entryPoint common.Address) (common.Hash, error) {
+	b.sent = op
+	return op.Hash(entryPoint, big.NewInt(int64(types.ChainIdBase)))
+}
+
+func (b *stubBundler) GetUserOperationReceipt(ctx context.Context, userOpHash common.Hash) (*Receipt, error) {
+	if b.sent == nil {
+		return nil, nil
+	}
+	return &Receipt{UserOpHash: userOpHash, Sender: b.sent.Sender, Success: true}, nil
+}
+
+func TestBuilderSend(t *testing.T) {
+	key, err := gethcrypto.GenerateKey()
+	if err != nil {
+		t.Fatal(err)
+	}
+	accountAddress := common.HexToAddress("0x1111111111111111111111111111111111111111")
+	factory := common.HexToAddress("0x2222222222222222222222222222222222222222")
+	paymaster := common.HexToAddress("0x3333333333333333333333333333333333333333")
+	estimate := GasEstimate{
+		PreVerificationGas:            big.NewInt(50000),
+		VerificationGasLimit:          big.NewInt(200000),
+		CallGasLimit:                  big.NewInt(100000),
+		PaymasterVerificationGasLimit: big.NewInt(30000),
+		PaymasterPostOpGasLimit:       big.NewInt(10000),
+	}
+	tests := []struct {
+		name                     string
+		configure                func(b *Builder)
+		estimate                 GasEstimate
+		wantMaxFeePerGas         *big.Int
+		wantMaxPriorityFeePerGas *big.Int
+		wantNodeFees             bool
+		wantPaymaster            bool
+		wantErr                  bool
+	}{
+		{
+			name:                     "node fees",
+			configure:                func(b *Builder) {},
+			estimate:                 estimate,
+			wantMaxFeePerGas:         big.NewInt(5e9),
+			wantMaxPriorityFeePerGas: big.NewInt(1e9),
+			wantNodeFees:             true,
+		},
+		{
+			name: "fee source",
+			configure: func(b *Builder) {
+				b.WithFees(FeeSourceFunc(func(ctx context.Context) (*big.Int, *big.Int, error) {
+					return big.NewInt(7e9), big.NewInt(3e9), nil
+				}))
+			},
+			estimate:                 estimate,
+			wantMaxFeePerGas:         big.NewInt(7e9),
+			wantMaxPriorityFeePerGas: big.NewInt(3e9),
+		},
+		{
+			name: "fee source error",
+			configure: func(b *Builder) {
+				b.WithFees(FeeSourceFunc(func(ctx context.Context) (*big.Int, *big.Int, error) {
+					return nil, nil, errors.New("bundler gas price is unavailable")
+				}))
+			},
+			estimate: estimate,
+			wantErr:  true,
+		},
+		{
+			name:                     "paymaster",
+			configure:                func(b *Builder) { b.WithPaymaster(paymaster, []byte{0xff}) },
+			estimate:                 estimate,
+			wantMaxFeePerGas:         big.NewInt(5e9),
+			wantMaxPriorityFeePerGas: big.NewInt(1e9),
+			wantNodeFees:             true,
+			wantPaymaster:            true,
+		},
+		{
+			name:      "paymaster without estimate",
+			configure: func(b *Builder) { b.WithPaymaster(paymaster, []byte{0xff}) },
+			estimate:  GasEstimate{PreVerificationGas: big.NewInt(1), VerificationGasLimit: big.NewInt(1), CallGasLimit: big.NewInt(1)},
+			wantErr:   true,
+		},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			backend := &stubBackend{}
+			bundler := &stubBundler{estimate: tt.estimate}
+			builder := NewBuilder(types.ChainIdBase, NewSimpleAccount(accountAddress, signer.NewPrivateKey(key)), bundler, backend).
+				WithFactory(factory, []byte{0xab}).
+				WithPollInterval(time.Millisecond)
+			tt.configure(builder)
+
+			userOpHash, err := builder.Send(context.Background(), types.TransactionParams{
+				ChainId: types.ChainIdBase,
+				To:      common.HexToAddress("0x04"),
+				Value:   big.NewInt(1),
+			})
+			if tt.wantErr {
+				if err == nil || bundler.sent != nil {
+					t.Fatalf("expected error before sending, got %v", err)
+				}
+				return
+			}
+			if err != nil {
+				t.Fatal(err)
+			}
+
+			op := bundler.sent
+			if op.Nonce.Int64() != 3 || op.Factory == nil || *op.Factory != factory ||
+				op.MaxFeePerGas.Cmp(tt.wantMaxFeePerGas) != 0 || op.MaxPriorityFeePerGas.Cmp(tt.wantMaxPriorityFeePerGas) != 0 ||
+				op.CallGasLimit.Cmp(estimate.CallGasLimit) != 0 || op.PreVerificationGas.Cmp(estimate.PreVerificationGas) != 0 {
+				t.Fatalf("unexpected user operation %+v", op)
+			}
+			if tt.wantNodeFees != (backend.tipCapCalls > 0) {
+				t.Fatalf("unexpected node fee requests: %d", backend.tipCapCalls)
+			}
+			if !bytes.Equal(bundler.estimated.Signature, dummyECDSASignature) {
+				t.Fatal("gas is estimated without the dummy signature")
+			}
+			if tt.wantPaymaster != (op.Paymaster != nil) || tt.wantPaymaster != (bundler.estimated.Paymaster != nil) {
+				t.Fatalf("unexpected paymaster %v", op.Paymaster)
+			}
+			if tt.wantPaymaster && (op.PaymasterVerificationGasLimit.Cmp(estimate.PaymasterVerificationGasLimit) != 0 ||
+				op.PaymasterPostOpGasLimit.Cmp(estimate.PaymasterPostOpGasLimit) != 0) {
+				t.Fatalf("paymaster gas limits aren't estimated: %+v", op)
+			}
+
+			pub, err := gethcrypto.SigToPub(accounts.TextHash(userOpHash.Bytes()), append(op.Signature[:64:64], op.Signature[64]-27))
+			if err != nil {
+				t.Fatal(err)
+			}
+			if gethcrypto.PubkeyToAddress(*pub) != gethcrypto.PubkeyToAddress(key.PublicKey) {
+				t.Fatal("user operation isn't signed by the owner")
+			}
+			receipt, err := builder.WaitForReceipt(context.Background(), userOpHash)
+			if err != nil {
+				t.Fatal(err)
+			}
+			if receipt.UserOpHash != userOpHash || !receipt.Success {
+				t.Fatalf("unexpected receipt %+v", receipt)
+			}
+		})
+	}
+}
@@ -0,0 +1,109 @@
+package userop
+
+import (
+	"context"
+	"github.com/ethereum/go-ethereum/common"
+	"github.com/ethereum/go-ethereum/common/hexutil"
+	"github.com/ethereum/go-ethereum/rpc"
+	"math/big"
+)
+
+// Bundler is the ERC-4337 bundler, RPCBundler calls its JSON-RPC API.
+// Implement it to send the user operations through a custom client or to mock the bundler.
+type Bundler interface {
+	EstimateUserOperationGas(ctx context.Context, op *UserOperation, entryPoint common.Address) (*GasEstimate, error)
+	SendUserOperation(ctx context.Context, op *UserOperation, entryPoint common.Address) (common.Hash, error)
+	// GetUserOperationReceipt returns nil receipt until the user operation is included
+	GetUserOperationReceipt(ctx context.Context, userOpHash common.Hash) (*Receipt, error)
+}
+
+// GasEstimate is the result of eth_estimateUserOperationGas
+type GasEstimate struct {
+	PreVerificationGas            *big.Int
+	VerificationGasLimit          *big.Int
+	CallGasLimit                  *big.Int
+	PaymasterVerificationGasLimit *big.Int
+	PaymasterPostOpGasLimit       *big.Int
+}
+
+// Receipt is the result of eth_getUserOperationReceipt
+type Receipt struct {
+	UserOpHash common.Hash        `json:"userOpHash"`
+	Sender     common.Address     `json:"sender"`
+	Success    bool               `json:"success"`
+	Reason     string             `json:"reason"`
+	Receipt    TransactionReceipt `json:"receipt"`
+}
+
+// TransactionReceipt is the part of the receipt of the transaction including the user operation
+type TransactionReceipt struct {
+	TransactionHash common.Hash  `json:"transactionHash"`
+	BlockNumber     *hexutil.Big `json:"blockNumber"`
+}
+
+// RPCBundler calls the bundler JSON-RPC API
+type RPCBundler struct {
+	client *rpc.Client
+}
+
+// NewRPCBundler connects to the bundler at url
+func NewRPCBundler(ctx context.Context, url string) (*RPCBundler, error) {
+	client, err := rpc.DialContext(ctx, url)
+	if err != nil {
+		return nil, err
+	}
+	return NewRPCBundlerWithClient(client), nil
+}
+
+// NewRPCBundlerWithClient creates the bundler using the connected client
+func NewRPCBundlerWithClient(client *rpc.Client) *RPCBundler {
+	return &RPCBundler{client: client}
+}
+
+// Close closes the connection to the bundler
+func (b *RPCBundler) Close() {
+	b.client.Close()
+}
+
+func (b *RPCBundler) EstimateUserOperationGas(
+	ctx context.Context,
+	op *UserOperation,
+	entryPoint common.Address,
+) (*GasEstimate, error) {
+	var result struct {
+		PreVerificationGas            *hexutil.Big `json:"preVerificationGas"`
+		VerificationGasLimit          *hexutil.Big `json:"verificationGasLimit"`
+		CallGasLimit                  *hexutil.Big `json:"callGasLimit"`
+		PaymasterVerificationGasLimit *hexutil.Big `json:"paymasterVerificationGasLimit"`
+		PaymasterPostOpGasLimit       *hexutil.Big `json:"paymasterPostOpGasLimit"`
+	}
+	err := b.client.CallContext(ctx, &result, "eth_estimateUserOperationGas", op, entryPoint)
+	if err != nil {
+		return nil, err
+	}
+	return &GasEstimate{
+		PreVerificationGas:            result.PreVerificationGas.ToInt(),
+		VerificationGasLimit:          result.VerificationGasLimit.ToInt(),
+		CallGasLimit:                  result.CallGasLimit.ToInt(),
+		PaymasterVerificationGasLimit: result.PaymasterVerificationGasLimit.ToInt(),
+		PaymasterPostOpGasLimit:       result.PaymasterPostOpGasLimit.ToInt(),
+	}, nil
+}
+
+func (b *RPCBundler) SendUserOperation(
+	ctx context.Context,
+	op *UserOperation,
+	entryPoint common.Address,
+) (userOpHash common.Hash, err error) {
+	err = b.client.CallContext(ctx, &userOpHash, "eth_sendUserOperation", op, entryPoint)
+	return
+}
+
+func (b *RPCBundler) GetUserOperationReceipt(ctx context.Context, userOpHash common.Hash) (*Receipt, error) {
+	var receipt *Receipt
+	err := b.client.CallContext(ctx, &receipt, "eth_getUserOperationReceipt", userOpHash)
+	if err != nil {
+		return nil, err
+	}
+	return receipt, nil
+}
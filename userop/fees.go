@@ -0,0 +1,40 @@
+package userop
+
+import (
+	"context"
+	"math/big"
+)
+
+// FeeSource suggests the fees of the user operations.
+// Bundlers often reject the fees suggested by the node, use the bundler's gas price API with WithFees then.
+type FeeSource interface {
+	Fees(ctx context.Context) (maxFeePerGas *big.Int, maxPriorityFeePerGas *big.Int, err error)
+}
+
+// FeeSourceFunc is the function implementing FeeSource
+type FeeSourceFunc func(ctx context.Context) (maxFeePerGas *big.Int, maxPriorityFeePerGas *big.Int, err error)
+
+func (f FeeSourceFunc) Fees(ctx context.Context) (maxFeePerGas *big.Int, maxPriorityFeePerGas *big.Int, err error) {
+	return f(ctx)
+}
+
+// nodeFees is the default FeeSource, it pays the node suggested tip and up to twice the current base fee
+type nodeFees struct {
+	backend Backend
+}
+
+func (n nodeFees) Fees(ctx context.Context) (maxFeePerGas *big.Int, maxPriorityFeePerGas *big.Int, err error) {
+	head, err := n.backend.HeaderByNumber(ctx, nil)
+	if err != nil {
+		return
+	}
+	maxPriorityFeePerGas, err = n.backend.SuggestGasTipCap(ctx)
+	if err != nil {
+		return
+	}
+	maxFeePerGas = new(big.Int).Set(maxPriorityFeePerGas)
+	if head.BaseFee != nil {
+		maxFeePerGas.Add(maxFeePerGas, new(big.Int).Mul(head.BaseFee, big.NewInt(2)))
+	}
+	return
+}
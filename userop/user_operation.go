@@ -0,0 +1,224 @@
+// Package userop sends transactions from ERC-4337 smart accounts: it builds user operations for EntryPoint v0.7,
+// computes their hashes and sends them through a bundler
+package userop
+
+import (
+	"encoding/json"
+	"fmt"
+	"github.com/ethereum/go-ethereum/accounts/abi"
+	"github.com/ethereum/go-ethereum/common"
+	"github.com/ethereum/go-ethereum/common/hexutil"
+	"github.com/ethereum/go-ethereum/crypto"
+	"math/big"
+)
+
+// UserOperation is the user operation of EntryPoint v0.7 in the bundler RPC format.
+// The optional factory and paymaster are nil if not used.
+type UserOperation struct {
+	Sender                        common.Address
+	Nonce                         *big.Int
+	Factory                       *common.Address
+	FactoryData                   []byte
+	CallData                      []byte
+	CallGasLimit                  *big.Int
+	VerificationGasLimit          *big.Int
+	PreVerificationGas            *big.Int
+	MaxFeePerGas                  *big.Int
+	MaxPriorityFeePerGas          *big.Int
+	Paymaster                     *common.Address
+	PaymasterVerificationGasLimit *big.Int
+	PaymasterPostOpGasLimit       *big.Int
+	PaymasterData                 []byte
+	Signature                     []byte
+}
+
+// PackedUserOperation is the user operation as it's passed to EntryPoint v0.7 handleOps
+type PackedUserOperation struct {
+	Sender             common.Address
+	Nonce              *big.Int
+	InitCode           []byte
+	CallData           []byte
+	AccountGasLimits   [32]byte // AccountGasLimits - verificationGasLimit and callGasLimit, 16 bytes each
+	PreVerificationGas *big.Int
+	GasFees            [32]byte // GasFees - maxPriorityFeePerGas and maxFeePerGas, 16 bytes each
+	PaymasterAndData   []byte
+	Signature          []byte
+}
+
+var (
+	bytes32Type, _ = abi.NewType("bytes32", "", nil)
+	uint256Type, _ = abi.NewType("uint256", "", nil)
+	addressType, _ = abi.NewType("address", "", nil)
+
+	packedUserOpArguments = abi.Arguments{
+		{Type: addressType}, // sender
+		{Type: uint256Type}, // nonce
+		{Type: bytes32Type}, // keccak256(initCode)
+		{Type: bytes32Type}, // keccak256(callData)
+		{Type: bytes32Type}, // accountGasLimits
+		{Type: uint256Type}, // preVerificationGas
+		{Type: bytes32Type}, // gasFees
+		{Type: bytes32Type}, // keccak256(paymasterAndData)
+	}
+	userOpHashArguments = abi.Arguments{
+		{Type: bytes32Type}, // keccak256 of the packed user operation
+		{Type: addressType}, // entry point
+		{Type: uint256Type}, // chain ID
+	}
+)
+
+// InitCode returns the factory address followed by the factory data, empty for the deployed accounts
+func (op *UserOperation) InitCode() []byte {
+	if op.Factory == nil {
+		return nil
+	}
+	return append(op.Factory.Bytes(), op.FactoryData...)
+}
+
+// PaymasterAndData returns the paymaster address, its gas limits and data, empty without the paymaster.
+// The gas limits must fit into 128 bits.
+func (op *UserOperation) PaymasterAndData() (data []byte, err error) {
+	if op.Paymaster == nil {
+		return
+	}
+	verificationGasLimit, err := uint128Bytes(op.PaymasterVerificationGasLimit)
+	if err != nil {
+		return nil, fmt.Errorf("paymasterVerificationGasLimit: %w", err)
+	}
+	postOpGasLimit, err := uint128Bytes(op.PaymasterPostOpGasLimit)
+	if err != nil {
+		return nil, fmt.Errorf("paymasterPostOpGasLimit: %w", err)
+	}
+	data = op.Paymaster.Bytes()
+	data = append(data, verificationGasLimit...)
+	data = append(data, postOpGasLimit...)
+	return append(data, op.PaymasterData...), nil
+}
+
+// Pack returns the user operation in the EntryPoint v0.7 format, the gas limits and the fees must fit into 128 bits
+func (op *UserOperation) Pack() (packed PackedUserOperation, err error) {
+	accountGasLimits, err := packUint128s(op.VerificationGasLimit, op.CallGasLimit)
+	if err != nil {
+		return packed, fmt.Errorf("verificationGasLimit or callGasLimit: %w", err)
+	}
+	gasFees, err := packUint128s(op.MaxPriorityFeePerGas, op.MaxFeePerGas)
+	if err != nil {
+		return packed, fmt.Errorf("maxPriorityFeePerGas or maxFeePerGas: %w", err)
+	}
+	paymasterAndData, err := op.PaymasterAndData()
+	if err != nil {
+		return
+	}
+	return PackedUserOperation{
+		Sender:             op.Sender,
+		Nonce:              bigOrZero(op.Nonce),
+		InitCode:           op.InitCode(),
+		CallData:           op.CallData,
+		AccountGasLimits:   accountGasLimits,
+		PreVerificationGas: bigOrZero(op.PreVerificationGas),
+		GasFees:            gasFees,
+		PaymasterAndData:   paymasterAndData,
+		Signature:          op.Signature,
+	}, nil
+}
+
+// Hash returns the user operation hash signed by the account, it doesn't depend on the signature
+func (op *UserOperation) Hash(entryPoint common.Address, chainId *big.Int) (hash common.Hash, err error) {
+	packed, err := op.Pack()
+	if err != nil {
+		return
+	}
+	encoded, err := packedUserOpArguments.Pack(
+		packed.Sender,
+		packed.Nonce,
+		crypto.Keccak256Hash(packed.InitCode),
+		crypto.Keccak256Hash(packed.CallData),
+		packed.AccountGasLimits,
+		packed.PreVerificationGas,
+		packed.GasFees,
+		crypto.Keccak256Hash(packed.PaymasterAndData),
+	)
+	if err != nil {
+		return
+	}
+	encoded, err = userOpHashArguments.Pack(crypto.Keccak256Hash(encoded), entryPoint, chainId)
+	if err != nil {
+		return
+	}
+	return crypto.Keccak256Hash(encoded), nil
+}
+
+func (op *UserOperation) MarshalJSON() ([]byte, error) {
+	type rpcUserOperation struct {
+		Sender                        common.Address  `json:"sender"`
+		Nonce                         *hexutil.Big    `json:"nonce"`
+		Factory                       *common.Address `json:"factory,omitempty"`
+		FactoryData                   *hexutil.Bytes  `json:"factoryData,omitempty"`
+		CallData                      hexutil.Bytes   `json:"callData"`
+		CallGasLimit                  *hexutil.Big    `json:"callGasLimit"`
+		VerificationGasLimit          *hexutil.Big    `json:"verificationGasLimit"`
+		PreVerificationGas            *hexutil.Big    `json:"preVerificationGas"`
+		MaxFeePerGas                  *hexutil.Big    `json:"maxFeePerGas"`
+		MaxPriorityFeePerGas          *hexutil.Big    `json:"maxPriorityFeePerGas"`
+		Paymaster                     *common.Address `json:"paymaster,omitempty"`
+		PaymasterVerificationGasLimit *hexutil.Big    `json:"paymasterVerificationGasLimit,omitempty"`
+		PaymasterPostOpGasLimit       *hexutil.Big    `json:"paymasterPostOpGasLimit,omitempty"`
+		PaymasterData                 *hexutil.Bytes  `json:"paymasterData,omitempty"`
+		Signature                     hexutil.Bytes   `json:"signature"`
+	}
+	rpcOp := rpcUserOperation{
+		Sender:               op.Sender,
+		Nonce:                (*hexutil.Big)(bigOrZero(op.Nonce)),
+		Factory:              op.Factory,
+		CallData:             op.CallData,
+		CallGasLimit:         (*hexutil.Big)(bigOrZero(op.CallGasLimit)),
+		VerificationGasLimit: (*hexutil.Big)(bigOrZero(op.VerificationGasLimit)),
+		PreVerificationGas:   (*hexutil.Big)(bigOrZero(op.PreVerificationGas)),
+		MaxFeePerGas:         (*hexutil.Big)(bigOrZero(op.MaxFeePerGas)),
+		MaxPriorityFeePerGas: (*hexutil.Big)(bigOrZero(op.MaxPriorityFeePerGas)),
+		Signature:            op.Signature,
+	}
+	if op.Factory != nil {
+		factoryData := hexutil.Bytes(op.FactoryData)
+		rpcOp.FactoryData = &factoryData
+	}
+	if op.Paymaster != nil {
+		rpcOp.Paymaster = op.Paymaster
+		rpcOp.PaymasterVerificationGasLimit = (*hexutil.Big)(bigOrZero(op.PaymasterVerificationGasLimit))
+		rpcOp.PaymasterPostOpGasLimit = (*hexutil.Big)(bigOrZero(op.PaymasterPostOpGasLimit))
+		paymasterData := hexutil.Bytes(op.PaymasterData)
+		rpcOp.PaymasterData = &paymasterData
+	}
+	return json.Marshal(rpcOp)
+}
+
+// packUint128s packs the values into 16 bytes each
+func packUint128s(high *big.Int, low *big.Int) (packed [32]byte, err error) {
+	highBytes, err := uint128Bytes(high)
+	if err != nil {
+		return
+	}
+	lowBytes, err := uint128Bytes(low)
+	if err != nil {
+		return
+	}
+	copy(packed[:16], highBytes)
+	copy(packed[16:], lowBytes)
+	return
+}
+
+// uint128Bytes returns the value as 16 bytes, the negative values and the values over 128 bits are rejected
+func uint128Bytes(value *big.Int) ([]byte, error) {
+	value = bigOrZero(value)
+	if value.Sign() < 0 || value.BitLen() > 128 {
+		return nil, fmt.Errorf("%s doesn't fit into uint128", value)
+	}
+	return common.LeftPadBytes(value.Bytes(), 16), nil
+}
+
+func bigOrZero(value *big.Int) *big.Int {
+	if value == nil {
+		return new(big.Int)
+	}
+	return value
+}
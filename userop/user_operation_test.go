@@ -0,0 +1,106 @@
+package userop
+
+import (
+	"github.com/LinkdropHQ/linkdrop-go-sdk/constants"
+	"github.com/ethereum/go-ethereum/common"
+	"math/big"
+	"strings"
+	"testing"
+)
+
+func newTestUserOperation() *UserOperation {
+	factory := common.HexToAddress("0x2222222222222222222222222222222222222222")
+	paymaster := common.HexToAddress("0x3333333333333333333333333333333333333333")
+	return &UserOperation{
+		Sender:                        common.HexToAddress("0x1111111111111111111111111111111111111111"),
+		Nonce:                         big.NewInt(7),
+		Factory:                       &factory,
+		FactoryData:                   []byte{0xab, 0xcd},
+		CallData:                      []byte{0x12, 0x34},
+		CallGasLimit:                  big.NewInt(100000),
+		VerificationGasLimit:          big.NewInt(200000),
+		PreVerificationGas:            big.NewInt(50000),
+		MaxFeePerGas:                  big.NewInt(3e9),
+		MaxPriorityFeePerGas:          big.NewInt(1e9),
+		Paymaster:                     &paymaster,
+		PaymasterVerificationGasLimit: big.NewInt(30000),
+		PaymasterPostOpGasLimit:       big.NewInt(10000),
+		PaymasterData:                 []byte{0xff},
+		Signature:                     []byte{1, 2, 3},
+	}
+}
+
+// TestUserOperationHash checks the hashes against EntryPoint v0.7 getUserOpHash on Base
+func TestUserOperationHash(t *testing.T) {
+	tests := []struct {
+		name   string
+		modify func(op *UserOperation)
+		want   common.Hash
+	}{
+		{
+			name:   "factory and paymaster",
+			modify: func(op *UserOperation) {},
+			want:   common.HexToHash("0x8ec06777548ad1a9c1b531290c4da28e85859cf3f27281339f146a6f5a7fce9e"),
+		},
+		{
+			name: "deployed account without paymaster",
+			modify: func(op *UserOperation) {
+				op.Factory = nil
+				op.Paymaster = nil
+				op.Signature = nil
+			},
+			want: common.HexToHash("0x444be2fe2ab69526c917071a062426b04935b5595db24d08362e24894c215edf"),
+		},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			op := newTestUserOperation()
+			tt.modify(op)
+			hash, err := op.Hash(constants.EntryPointV07Address, big.NewInt(8453))
+			if err != nil {
+				t.Fatal(err)
+			}
+			if hash != tt.want {
+				t.Fatalf("got %s, want %s", hash, tt.want)
+			}
+		})
+	}
+}
+
+func TestUserOperationPackOverflow(t *testing.T) {
+	overflow := new(big.Int).Lsh(big.NewInt(1), 128)
+	tests := []struct {
+		name    string
+		modify  func(op *UserOperation)
+		wantErr string
+	}{
+		{name: "max uint128", modify: func(op *UserOperation) { op.CallGasLimit = new(big.Int).Sub(overflow, big.NewInt(1)) }},
+		{name: "call gas limit", modify: func(op *UserOperation) { op.CallGasLimit = overflow }, wantErr: "callGasLimit"},
+		{name: "max fee", modify: func(op *UserOperation) { op.MaxFeePerGas = overflow }, wantErr: "maxFeePerGas"},
+		{name: "negative fee", modify: func(op *UserOperation) { op.MaxPriorityFeePerGas = big.NewInt(-1) }, wantErr: "maxPriorityFeePerGas"},
+		{
+			name:    "paymaster gas limit",
+			modify:  func(op *UserOperation) { op.PaymasterPostOpGasLimit = overflow },
+			wantErr: "paymasterPostOpGasLimit",
+		},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			op := newTestUserOperation()
+			tt.modify(op)
+			_, err := op.Pack()
+			if tt.wantErr == "" {
+				if err != nil {
+					t.Fatal(err)
+				}
+				return
+			}
+			if err == nil || !strings.Contains(err.Error(), tt.wantErr) {
+				t.Fatalf("expected %s error, got %v", tt.wantErr, err)
+			}
+			if _, err = op.Hash(constants.EntryPointV07Address, big.NewInt(8453)); err == nil {
+				t.Fatal("expected hash error")
+			}
+		})
+	}
+}
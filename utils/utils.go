@@ -6,12 +6,10 @@ import (
 	"crypto/rand"
 	"fmt"
 	"github.com/LinkdropHQ/linkdrop-go-sdk/helpers"
+	"github.com/LinkdropHQ/linkdrop-go-sdk/signer"
+	"github.com/LinkdropHQ/linkdrop-go-sdk/txmanager"
 	"github.com/LinkdropHQ/linkdrop-go-sdk/types"
-	"github.com/ethereum/go-ethereum"
-	"github.com/ethereum/go-ethereum/accounts/abi/bind"
 	"github.com/ethereum/go-ethereum/common"
-	geth_types "github.com/ethereum/go-ethereum/core/types"
-	"github.com/ethereum/go-ethereum/crypto"
 	"github.com/ethereum/go-ethereum/ethclient"
 	"github.com/ethereum/go-ethereum/signer/core/apitypes"
 	"log"
@@ -33,19 +31,17 @@ func GetRandomBytes(length int64) []byte {
 	return b
 }
 
+// SignTypedData signs EIP-712 hash of the typed data with the key
+//
+// Deprecated: use signer.PrivateKey or signer.SignTypedData
 func SignTypedData(typedData apitypes.TypedData, privateKey *ecdsa.PrivateKey) ([]byte, error) {
-	typedDataHash, _, err := apitypes.TypedDataAndHash(typedData)
-	if err != nil {
-		return nil, fmt.Errorf("failed to hash typed data: %w", err)
-	}
-	signature, err := crypto.Sign(typedDataHash[:], privateKey)
-	if err != nil {
-		return nil, fmt.Errorf("failed to sign the typed data: %w", err)
-	}
-	signature[64] += 27
-	return signature, nil
+	return signer.SignTypedData(typedData, privateKey)
 }
 
+// SendTransaction sends EIP-1559 transaction signed with the key, or the legacy one if the chain doesn't support it.
+// It doesn't wait for the receipt and reads the nonce on every call, so the concurrent calls can conflict.
+//
+// Deprecated: use signer.PrivateKey with the chain backend or txmanager.Manager
 func SendTransaction(
 	chainId *big.Int,
 	to common.Address,
@@ -55,62 +51,16 @@ func SendTransaction(
 	client *ethclient.Client,
 	privateKey *ecdsa.PrivateKey,
 ) (transaction *types.Transaction, err error) {
-	rpcChainId, err := client.NetworkID(context.Background())
+	rpcChainId, err := client.ChainID(context.Background())
 	if err != nil {
 		return
 	}
 	if rpcChainId.Cmp(chainId) != 0 {
 		return nil, fmt.Errorf("wrong RPC chain ID: %s, expected: %s", rpcChainId, chainId)
 	}
-
-	sender, err := bind.NewKeyedTransactorWithChainID(privateKey, chainId)
+	manager, err := txmanager.NewWithKey(client, chainId, privateKey, txmanager.Config{})
 	if err != nil {
 		return
 	}
-
-	nonce, err := client.PendingNonceAt(context.Background(), sender.From)
-	if err != nil {
-		return
-	}
-	log.Println("Nonce:", nonce)
-
-	gasLimit, err := client.EstimateGas(context.Background(), ethereum.CallMsg{
-		From:  sender.From,
-		To:    &to,
-		Value: value,
-		Data:  data,
-	})
-	if err != nil {
-		gasLimit = 100000
-	}
-	gasPrice, err := client.SuggestGasPrice(context.Background())
-	gasPrice.Add(gasPrice, gasPrice)
-	if err != nil {
-		log.Fatalf("Failed to get gas price: %v", err)
-	}
-
-	tx := geth_types.NewTx(&geth_types.LegacyTx{
-		Nonce:    nonce,
-		To:       &to,
-		Value:    value,
-		Gas:      gasLimit,
-		GasPrice: gasPrice,
-		Data:     data,
-	})
-
-	signedTx, err := geth_types.SignTx(tx, geth_types.NewEIP155Signer(chainId), privateKey)
-	if err != nil {
-		return
-	}
-
-	err = client.SendTransaction(context.Background(), signedTx)
-	if err != nil {
-		return
-	}
-
-	transaction = &types.Transaction{
-		Hash: signedTx.Hash(),
-		Type: "tx",
-	}
-	return
+	return manager.SendTransaction(context.Background(), chainId, to, value, data)
 }